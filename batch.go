@@ -0,0 +1,202 @@
+// Copyright (c) 2024, Jasakode Authors.
+// All rights reserved.
+// Use of this source code is governed by a BSD 3-Clause
+// license that can be found in the LICENSE file.
+
+package cago
+
+import (
+	"fmt"
+
+	"github.com/jasakode/cago/store"
+)
+
+// MSet menyimpan banyak nilai sekaligus dengan satu kali penguncian semua shard.
+// Sama seperti Put, key yang sudah ada akan ditimpa. Jika maxAge tidak
+// diberikan, maxAge dari nilai lama setiap key akan dipertahankan.
+// Semua nilai disimpan, atau tidak ada sama sekali jika batch gagal.
+//
+// Parameter:
+//   - items (map[string]store.Compare): Pasangan key dan nilai yang akan disimpan.
+//   - maxAge (opsional) (uint64): Waktu maksimal dalam milidetik selama nilai akan disimpan.
+//
+// Mengembalikan:
+//   - error: Kesalahan jika salah satu nilai tidak dapat dikonversi, ErrMemoryLimit
+//     jika semua nilai tidak muat, atau kesalahan database.
+func MSet(items map[string]store.Compare, maxAge ...uint64) error {
+	// Mengonversi semua nilai sebelum mengambil lock agar waktu lock sesingkat mungkin
+	encoded, err := app.encodeAll(items, maxAge)
+	if err != nil {
+		return err
+	}
+
+	defer app.dispatch()
+	app.lockAll()
+	defer app.unlockAll()
+	if len(maxAge) == 0 {
+		for key, data := range encoded {
+			if old, ok := app.shardFor(key).data[key]; ok {
+				encoded[key] = data.SetMaxAge(old.MaxAge()) // Store bersifat copy-on-write, sehingga hasilnya disimpan kembali
+			}
+		}
+	}
+	return app.storeAll(encoded)
+}
+
+// SetMany menyimpan banyak nilai sekaligus dengan aturan yang sama seperti Set.
+// Jika salah satu key sudah ada dan belum kedaluwarsa, tidak ada nilai yang
+// disimpan dan ErrKeyExists dikembalikan bersama key tersebut.
+//
+// Parameter:
+//   - items (map[string]store.Compare): Pasangan key dan nilai yang akan disimpan.
+//   - maxAge (opsional) (uint64): Waktu maksimal dalam milidetik selama nilai akan disimpan.
+//
+// Mengembalikan:
+//   - error: ErrKeyExists jika salah satu key sudah ada, ErrMemoryLimit jika semua
+//     nilai tidak muat, atau kesalahan konversi dan database.
+func SetMany(items map[string]store.Compare, maxAge ...uint64) error {
+	encoded, err := app.encodeAll(items, maxAge)
+	if err != nil {
+		return err
+	}
+
+	defer app.dispatch()
+	app.lockAll()
+	defer app.unlockAll()
+	now := app.now()
+	for key := range encoded {
+		if old, ok := app.shardFor(key).data[key]; ok && !app.expiredEntry(key, old, now) {
+			return fmt.Errorf("key %q: %w", key, ErrKeyExists)
+		}
+	}
+	return app.storeAll(encoded)
+}
+
+// storeAll menyimpan semua store di dalam batch ke cache dan database, atau
+// tidak menyimpan apa pun jika batch gagal. Batas memori diperiksa untuk seluruh
+// batch sebelum ada yang disimpan, sehingga setEntry tidak gagal di tengah jalan,
+// dan database ditulis lebih dahulu agar kegagalannya tidak meninggalkan
+// cache yang berbeda dengan database. Fungsi ini harus dipanggil ketika
+// write lock semua shard sedang dipegang.
+//
+// Mengembalikan:
+//   - error: ErrMemoryLimit jika batch tidak muat, atau kesalahan database.
+func (app *App) storeAll(batch map[string]store.Store) error {
+	// Batas memori berlaku per shard, sehingga ukuran batch dihitung per shard
+	limit := app.memLimit()
+	total, next := make(map[*shard]uint64), make(map[*shard]uint64)
+	for key, data := range batch {
+		s := app.shardFor(key)
+		size := uint64(len(key) + len(data))
+		if size > limit {
+			return fmt.Errorf("key %q: %w", key, ErrMemoryLimit)
+		}
+		if _, ok := next[s]; !ok {
+			next[s] = s.data_size
+		}
+		total[s] += size
+		next[s] += size
+		if old, ok := s.data[key]; ok {
+			next[s] -= uint64(len(key) + len(old))
+		}
+	}
+	// Dengan EvictOldestOnMaxMem, batch yang lebih besar dari MAX_MEM akan
+	// menghapus sebagian isinya sendiri
+	for s, size := range total {
+		if size > limit || (!app.config.EvictOldestOnMaxMem && next[s] > limit) {
+			return ErrMemoryLimit
+		}
+	}
+
+	if app.db != nil {
+		ops := make([]writeOp, 0, len(batch))
+		for key, data := range batch {
+			ops = append(ops, writeOp{key: key, data: data})
+		}
+		if err := app.db.apply(ops); err != nil {
+			return err
+		}
+	}
+	for key, data := range batch {
+		if err := app.setEntry(key, data); err != nil {
+			return err // Tidak terjadi karena batas memori sudah diperiksa di atas
+		}
+	}
+	return nil
+}
+
+// encodeAll memeriksa setiap key dengan checkKey lalu mengonversi nilainya
+// dengan app.encode, sehingga TTLJitter dan MaxValueSize berlaku seperti pada Set.
+//
+// Mengembalikan:
+//   - map[string]store.Store: Store untuk setiap key.
+//   - error: Kesalahan dari checkKey atau app.encode untuk key pertama yang gagal.
+func (app *App) encodeAll(items map[string]store.Compare, maxAge []uint64) (map[string]store.Store, error) {
+	encoded := make(map[string]store.Store, len(items))
+	for key, value := range items {
+		if err := app.checkKey(key); err != nil {
+			return nil, err
+		}
+		data, err := app.encode(key, value, maxAge)
+		if err != nil {
+			return nil, err
+		}
+		encoded[key] = data
+	}
+	return encoded, nil
+}
+
+// MGet mengambil banyak nilai sekaligus dengan satu kali penguncian semua shard.
+// Key yang tidak ada atau sudah kedaluwarsa tidak disertakan dalam hasil.
+//
+// Parameter:
+//   - keys (...string): Key yang akan diambil.
+//
+// Mengembalikan:
+//   - map[string]store.Store: Store untuk setiap key yang ditemukan.
+func MGet(keys ...string) map[string]store.Store {
+	app.rlockAll()
+	defer app.runlockAll()
+
+	now := app.now()
+	result := make(map[string]store.Store, len(keys))
+	for _, key := range keys {
+		if data, ok := app.shardFor(key).data[key]; ok && !app.expiredEntry(key, data, now) {
+			result[key] = data
+		}
+	}
+	return result
+}
+
+// RemoveMany menghapus banyak key sekaligus dengan satu kali penguncian semua shard.
+// Penghapusan dari database ditulis dalam satu transaksi.
+//
+// Parameter:
+//   - keys (...string): Key yang akan dihapus.
+//
+// Mengembalikan:
+//   - int: Jumlah key yang ada di cache sebelum dihapus.
+func (app *App) RemoveMany(keys ...string) int {
+	defer app.dispatch()
+	app.lockAll()
+	defer app.unlockAll()
+	count := 0
+	ops := make([]writeOp, 0, len(keys))
+	for _, key := range keys {
+		if app.deleteEntry(key, EvictRemoved) {
+			count++
+		}
+		ops = append(ops, writeOp{key: key, remove: true})
+	}
+	if app.db != nil {
+		if err := app.db.apply(ops); err != nil {
+			app.logf("cago: remove many: %v", err)
+		}
+	}
+	return count
+}
+
+// RemoveMany menjalankan App.RemoveMany pada instance global yang dibuat oleh New.
+func RemoveMany(keys ...string) int {
+	return app.RemoveMany(keys...)
+}
@@ -0,0 +1,161 @@
+// Copyright (c) 2024, Jasakode Authors.
+// All rights reserved.
+// Use of this source code is governed by a BSD 3-Clause
+// license that can be found in the LICENSE file.
+
+package cago_test
+
+import (
+	"errors"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/jasakode/cago"
+	"github.com/jasakode/cago/store"
+)
+
+func TestMSetMGet(t *testing.T) {
+	var now atomic.Int64
+	now.Store(1_700_000_000_000)
+	if err := cago.New(cago.Config{Clock: now.Load}); err != nil {
+		t.Fatal(err)
+	}
+	cago.Set("name", "old", 60000)
+	cago.Set("short", "x", 1)
+
+	// MSet menimpa key yang sudah ada seperti Put
+	err := cago.MSet(map[string]store.Compare{
+		"name": "Jhon Doe",
+		"city": "Jakarta",
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	now.Add(1)
+
+	rs := cago.MGet("name", "city", "short", "missing")
+	if len(rs) != 2 {
+		t.Fatalf("expected 2 entries, got %d", len(rs))
+	}
+	if rs["name"].Text() != "Jhon Doe" || rs["city"].Text() != "Jakarta" {
+		t.Errorf("unexpected values: %q, %q", rs["name"].Text(), rs["city"].Text())
+	}
+	// maxAge lama dipertahankan ketika tidak diberikan
+	if rs["name"].MaxAge() != 60000 {
+		t.Errorf("expected max age 60000, got %d", rs["name"].MaxAge())
+	}
+}
+
+func TestSetMany(t *testing.T) {
+	if err := cago.New(); err != nil {
+		t.Fatal(err)
+	}
+	cago.Set("taken", "old")
+
+	// Satu key yang sudah ada membatalkan seluruh batch
+	err := cago.SetMany(map[string]store.Compare{
+		"free":  "a",
+		"taken": "b",
+	})
+	if !errors.Is(err, cago.ErrKeyExists) || !strings.Contains(err.Error(), `"taken"`) {
+		t.Errorf("expected ErrKeyExists for taken, got %v", err)
+	}
+	if cago.Exist("free") {
+		t.Error("expected nothing to be written on conflict")
+	}
+	if rs := cago.Get[string]("taken"); rs == nil || *rs != "old" {
+		t.Errorf("expected taken to keep its value, got %v", rs)
+	}
+
+	if err := cago.SetMany(map[string]store.Compare{"free": "a", "other": 1}); err != nil {
+		t.Fatal(err)
+	}
+	if rs := cago.Get[int]("other"); rs == nil || *rs != 1 {
+		t.Errorf("expected 1, got %v", rs)
+	}
+}
+
+func TestRemoveMany(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "remove.db")
+	if err := cago.New(cago.Config{Path: path}); err != nil {
+		t.Fatal(err)
+	}
+	defer cago.Close()
+	for _, key := range []string{"a", "b", "c", "d"} {
+		cago.Set(key, key)
+	}
+
+	if n := cago.RemoveMany("a", "c", "missing"); n != 2 {
+		t.Errorf("expected 2 removed keys, got %d", n)
+	}
+	if cago.Exist("a") || cago.Exist("c") || !cago.Exist("b") || !cago.Exist("d") {
+		t.Error("expected only a and c to be removed")
+	}
+	if n := countRows(t, path); n != 2 {
+		t.Errorf("expected 2 rows left in the database, got %d", n)
+	}
+}
+
+func TestMSetPersistsMaxAge(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "mset.db")
+	if err := cago.New(cago.Config{Path: path}); err != nil {
+		t.Fatal(err)
+	}
+	cago.Put("k", "a", 3600000)
+	if err := cago.MSet(map[string]store.Compare{"k": "b"}); err != nil {
+		t.Fatal(err)
+	}
+
+	// maxAge lama yang dipertahankan juga ditulis ke database
+	if err := cago.New(cago.Config{Path: path}); err != nil {
+		t.Fatal(err)
+	}
+	defer cago.Close()
+	if rs := cago.MGet("k"); rs["k"] == nil || rs["k"].Text() != "b" || rs["k"].MaxAge() != 3600000 {
+		t.Errorf("expected b with max age 3600000 after reload, got %v", rs["k"])
+	}
+}
+
+func TestBatchValidation(t *testing.T) {
+	if err := cago.New(cago.Config{MaxKeyLength: 4, MaxValueSize: 8}); err != nil {
+		t.Fatal(err)
+	}
+	// MSet dan SetMany memeriksa key dan ukuran nilai seperti Set
+	if err := cago.MSet(map[string]store.Compare{"too-long": "x"}); !errors.Is(err, cago.ErrInvalidKey) {
+		t.Errorf("expected ErrInvalidKey from MSet, got %v", err)
+	}
+	if err := cago.SetMany(map[string]store.Compare{"k": strings.Repeat("x", 9)}); !errors.Is(err, cago.ErrValueTooLarge) {
+		t.Errorf("expected ErrValueTooLarge from SetMany, got %v", err)
+	}
+	if cago.Exist("too-long") || cago.Exist("k") {
+		t.Error("expected nothing to be stored")
+	}
+}
+
+func TestMSetMemoryLimit(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "mset.db")
+	if err := cago.New(cago.Config{Path: path, MAX_MEM: 8 * 200}); err != nil {
+		t.Fatal(err)
+	}
+	defer cago.Close()
+
+	// Batch yang melampaui MAX_MEM ditolak seluruhnya, di memori maupun di database
+	batch := map[string]store.Compare{}
+	for _, key := range []string{"a", "b", "c", "d"} {
+		batch[key] = strings.Repeat("x", 60)
+	}
+	if err := cago.MSet(batch); !errors.Is(err, cago.ErrMemoryLimit) {
+		t.Fatalf("expected ErrMemoryLimit, got %v", err)
+	}
+	if n := cago.Size(); n != 0 {
+		t.Errorf("expected nothing in memory, got %d bytes", n)
+	}
+	if err := cago.New(cago.Config{Path: path}); err != nil {
+		t.Fatal(err)
+	}
+	if n := cago.Size(); n != 0 {
+		t.Errorf("expected nothing in the database, got %d bytes", n)
+	}
+}
@@ -14,8 +14,19 @@ package cago
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
+	"hash/maphash"
+	"log"
+	"math"
+	"math/rand"
+	"net"
+	"net/url"
+	"reflect"
+	"sort"
+	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/jasakode/cago/lib"
@@ -33,6 +44,10 @@ type Config struct {
 	// File seperti "database.db" akan menyimpan data untuk mengantisipasi jika
 	// program terhenti, sehingga data yang telah dicache dapat dimuat ulang.
 	Path string
+	// Jika true, direktori induk dari Path dibuat ketika belum ada.
+	// Jika false, New mengembalikan kesalahan ketika direktori tersebut tidak ada.
+	// default : false
+	CreateDir bool
 	// Memori maksimal yang diperbolehkan digunakan (dalam bit).
 	// 8.388.608 bit = 1 MB.
 	// default: 8589934592 bit (1 GB).
@@ -45,31 +60,259 @@ type Config struct {
 	// ketika batas memori maksimal tercapai.
 	// default : false
 	EvictOldestOnMaxMem bool
+	// Rentang acak yang ditambahkan ke maxAge oleh Set dan Put (dalam milidetik),
+	// agar key yang disimpan dengan maxAge sama tidak kedaluwarsa bersamaan.
+	// Setiap penyimpanan mendapat tambahan acak antara 0 dan TTLJitter yang
+	// disimpan di store, sehingga tidak berubah saat dibaca. Nilai tanpa maxAge
+	// tidak terpengaruh.
+	// default: 0 (tanpa jitter).
+	TTLJitter uint64
+	// Jika true, masa berlaku entri bergeser setiap kali entri berhasil dibaca,
+	// seperti cache sesi: entri baru kedaluwarsa setelah tidak diakses selama
+	// maxAge-nya. Waktu akses terakhir dicatat terpisah dari store, sehingga
+	// CreateAt dan maxAge asli tidak berubah dan pembacaan tidak menulis ke database.
+	// Karena itu waktu akses hanya ada di memori: setelah dimuat ulang dari
+	// database, masa berlaku kembali dihitung dari CreateAt. GetMeta, Export,
+	// dan SnapshotBinary melaporkan masa berlaku yang sudah bergeser.
+	// Karena setiap pembacaan mencatat akses, Get memakai write lock seperti
+	// PolicyLRU dan PolicyLFU sehingga pembacaan bersamaan tidak lagi berjalan paralel.
+	// default : false
+	SlidingTTL bool
+	// Ukuran maksimal satu nilai (dalam byte) yang boleh disimpan oleh Set dan Put.
+	// Ukuran dihitung dari hasil serialisasi: panjang string atau []byte apa
+	// adanya, dan panjang JSON untuk tipe lain. Nilai yang melebihi batas ini
+	// ditolak dengan ErrValueTooLarge tanpa ada yang disimpan.
+	// default: 0 (tidak terbatas).
+	MaxValueSize uint64
+	// Panjang maksimal key (dalam byte) yang diterima oleh Set, Put, SetIfAbsent, dan Update.
+	// default: 0 (tidak terbatas).
+	MaxKeyLength int
+	// Fungsi untuk memeriksa key sebelum disimpan oleh Set, Put, SetIfAbsent,
+	// dan Update, misalnya untuk menolak key kosong atau yang mengandung byte nol.
+	// Kesalahan yang dikembalikan dibungkus bersama ErrInvalidKey.
+	// default: nil.
+	KeyValidator func(key string) error
+	// Sumber waktu yang dipakai untuk waktu pembuatan, pemeriksaan kedaluwarsa,
+	// dan pembersihan entri, dalam milidetik unix. Berguna untuk pengujian
+	// kedaluwarsa tanpa menunggu: jam palsu dapat dimajukan secara langsung.
+	// Interval pemeriksaan TimeoutCheck tetap memakai jam sistem.
+	// default: nil (time.Now).
+	Clock func() int64
 	// Timeout untuk pemeriksaan entri yang kedaluwarsa (dalam milidetik).
 	// Ini menentukan interval waktu antara setiap pemeriksaan data dalam cache.
+	// Nilai di bawah 10 ditolak oleh New, karena setiap pemeriksaan memindai
+	// cache sambil memegang lock.
 	// Default: 10000 (10 detik).
 	TimeoutCheck uint64
+	// Jika true, data dari database yang masih memakai format Store versi lama
+	// tidak akan di-upgrade dan New akan mengembalikan ErrUnsupportedVersion.
+	// default : false
+	StrictVersion bool
+	// Lama waktu penyimpanan catatan key yang dihapus (dalam milidetik).
+	// Catatan ini digunakan oleh DiffSince untuk melaporkan penghapusan.
+	// Default: 60000 (1 menit).
+	DiffWindow uint64
+	// Logger untuk mencatat kesalahan internal, misalnya data yang gagal
+	// dikonversi saat Get. Jika nil, kesalahan tidak akan dicetak.
+	Logger *log.Logger
+	// Jumlah maksimal entri di dalam cache. Ketika batas ini terlampaui,
+	// entri akan dihapus sesuai EvictionPolicy.
+	// default: 0 (tidak terbatas).
+	MaxEntries int
+	// Kebijakan penghapusan entri ketika MaxEntries terlampaui.
+	// PolicyNone menghapus entri dengan CreateAt paling lama, sedangkan
+	// PolicyLRU menghapus entri yang paling lama tidak diakses. Dengan
+	// PolicyLRU, Get memakai write lock karena setiap akses mengubah urutan.
+	// PolicyRandom menghapus entri acak yang dekat dengan waktu kedaluwarsa.
+	// PolicyLFU menghapus entri yang paling jarang diakses dan, seperti
+	// PolicyLRU, membuat Get memakai write lock untuk mencatat setiap akses.
+	// default: PolicyNone.
+	EvictionPolicy EvictionPolicy
+	// Jumlah entri yang diambil sebagai sampel oleh PolicyRandom.
+	// default: 5.
+	EvictionSampleSize int
+	// Jumlah shard tempat entri cache dibagi. Setiap shard memiliki map dan
+	// lock sendiri, dan setiap key selalu berada di shard yang sama berdasarkan
+	// hash-nya, sehingga operasi atas key di shard berbeda tidak saling menunggu.
+	// MaxEntries dan MAX_MEM dibagi rata ke setiap shard, dan EvictionPolicy
+	// hanya memilih entri yang dihapus dari shard yang sama dengan key baru,
+	// sehingga dengan lebih dari satu shard kedua batas tersebut dan urutan
+	// penghapusan berlaku per shard, bukan untuk seluruh cache. Operasi atas
+	// banyak key seperti Txn, MSet, Clear, Export, dan DiffSince mengunci semua shard.
+	// default: 1 (satu lock untuk seluruh cache).
+	Shards int
+	// Jumlah shard yang dibersihkan bersamaan oleh proses latar belakang pada
+	// setiap pemeriksaan. Setiap worker hanya memegang lock shard yang sedang
+	// dibersihkan, dan jumlah worker tidak pernah melebihi Shards, sehingga
+	// penggunaan CPU oleh pemeriksaan tetap terbatas.
+	// default: 1 (shard dibersihkan satu per satu).
+	CleanWorkers int
+	// Callback yang dipanggil ketika entri dihapus karena kedaluwarsa.
+	// Callback dipanggil di luar lock sehingga boleh memanggil fungsi cago lainnya.
+	OnExpire func(key string, value store.Store)
+	// Callback yang dipanggil setiap kali entri keluar dari cache, baik karena
+	// kedaluwarsa, batas kapasitas, maupun dihapus secara manual.
+	// Callback dipanggil di luar lock sehingga boleh memanggil fungsi cago lainnya.
+	OnEvict func(key string, value store.Store, reason EvictReason)
+	// Jika true, perubahan ke database diantrekan dan ditulis oleh goroutine
+	// latar belakang dalam satu transaksi, sehingga Set dan Put tidak menunggu SQLite.
+	// Gunakan Flush untuk menunggu antrean kosong. Close selalu mengosongkan antrean.
+	// default : false
+	WriteBehind bool
+	// Jika true, Set, Put, dan fungsi serupa menulis ke database terlebih dahulu
+	// dan hanya memperbarui cache jika penulisan berhasil, sehingga cache dan
+	// database tidak berbeda ketika database gagal. Tidak dapat digunakan bersama WriteBehind.
+	// default : false
+	WriteThrough bool
+	// Jarak waktu maksimal antar penulisan antrean WriteBehind (dalam milidetik).
+	// Default: 100.
+	FlushInterval uint64
+	// Jumlah perubahan yang memicu penulisan antrean WriteBehind sebelum FlushInterval.
+	// Default: 100.
+	FlushSize int
+	// Jika true, database SQLite memakai journal_mode WAL sehingga pembaca
+	// tidak terblokir oleh penulisan.
+	// default : false
+	SQLiteWAL bool
+	// Lama waktu SQLite menunggu lock database sebelum mengembalikan
+	// kesalahan "database is locked" (dalam milidetik).
+	// Default: 5000 (5 detik).
+	SQLiteBusyTimeout uint64
+	// Jika true, channel Subscribe yang penuh akan memblokir perubahan cache
+	// sampai subscriber membaca event. Jika false, event untuk subscriber
+	// yang lambat dibuang dan dihitung di CacheStats.DroppedEvents.
+	// Karena event dikirim selama lock cache dipegang, subscriber yang memblokir
+	// tidak boleh memanggil kembali fungsi cago dari goroutine pembacanya.
+	// Close tidak lagi menunggu subscriber tersebut.
+	// default : false
+	BlockingSubscribers bool
+	// Jumlah maksimal watcher WatchKey dan subscriber Subscribe yang terdaftar
+	// bersamaan. Pendaftaran setelah batas tercapai mengembalikan ErrTooManySubscribers,
+	// sehingga subscriber lambat yang tidak terbatas tidak menghabiskan memori untuk buffer.
+	// default: 0 (tidak terbatas).
+	MaxSubscribers int
+	// Jika true, Close menghapus entri yang sudah kedaluwarsa satu kali lagi
+	// sebelum berhenti, sehingga OnExpire tetap dipanggil untuk entri yang
+	// kedaluwarsa setelah pemeriksaan terakhir.
+	// default : false
+	FlushExpiredOnClose bool
+	// Jika true, Close menjalankan Compact sebelum menutup database, sehingga
+	// ruang dari data yang dihapus dikembalikan ke sistem berkas.
+	// default : false
+	VacuumOnClose bool
+	// Strategi pemeriksaan entri kedaluwarsa oleh proses latar belakang.
+	// CleanFullScan memeriksa semua entri setiap TimeoutCheck, sedangkan
+	// CleanSampled hanya memeriksa sampel acak sehingga biaya per pemeriksaan terbatas.
+	// default: CleanFullScan.
+	CleanStrategy CleanStrategy
+	// Jumlah entri yang diperiksa per putaran oleh CleanSampled di setiap shard.
+	// default: 20.
+	CleanSampleSize int
+	// Jika rasio entri kedaluwarsa di dalam sampel melebihi nilai ini,
+	// CleanSampled mengambil sampel berikutnya pada pemeriksaan yang sama.
+	// default: 0.25.
+	CleanExpiredRatio float64
+	// Loader dipanggil oleh Get, GetErr, GetFrom, dan fungsi Get bertipe ketika
+	// key tidak ditemukan. Nilai yang dikembalikan disimpan dengan masa berlaku
+	// maxAge (dalam milidetik, 0 berarti tidak kedaluwarsa) lalu dikembalikan.
+	// Jika Loader mengembalikan kesalahan, tidak ada yang disimpan dan kesalahan
+	// tersebut dikembalikan oleh GetErr. Dengan Loader, Get dapat memblokir
+	// selama Loader berjalan; pemanggil lain untuk key yang sama menunggu hasil
+	// dari satu pemanggilan Loader. Loader dijalankan di luar lock.
+	// default: nil.
+	Loader func(key string) (value any, maxAge uint64, err error)
+	// Zona waktu untuk timestamp yang dikembalikan oleh GetMeta dan Export.
+	// Masa berlaku tetap dihitung dalam unix milidetik. New mengembalikan
+	// kesalahan jika nama zona waktu tidak dikenali.
+	// default: TimezoneLocal.
+	Timezone Timezone
+	// Nama tabel SQLite yang digunakan untuk menyimpan data. Beberapa instance
+	// dapat memakai file database yang sama dengan nama tabel berbeda.
+	// Hanya huruf, angka, dan garis bawah yang diperbolehkan.
+	// Default: "cagos".
+	TableName string
 }
 
+// ErrUnsupportedVersion dikembalikan ketika data yang dimuat memakai versi
+// format Store yang tidak dapat digunakan dengan konfigurasi saat ini.
+var ErrUnsupportedVersion = store.ErrUnsupportedVersion
+
+// ErrWriteMode dikembalikan oleh New ketika WriteThrough dan WriteBehind diaktifkan bersamaan.
+var ErrWriteMode = errors.New("WriteThrough and WriteBehind cannot be used together")
+
+// ErrInvalidConfig dikembalikan oleh New dan NewCache ketika Config berisi
+// nilai atau kombinasi opsi yang tidak dapat digunakan.
+var ErrInvalidConfig = errors.New("invalid config")
+
+// ErrMemoryLimit dikembalikan ketika menyimpan data akan melampaui MAX_MEM.
+var ErrMemoryLimit = errors.New("memory limit exceeded")
+
+// ErrValueTooLarge dikembalikan oleh Set dan Put ketika nilai melebihi Config.MaxValueSize.
+var ErrValueTooLarge = errors.New("value too large")
+
+// ErrInvalidKey dikembalikan ketika key melebihi Config.MaxKeyLength atau ditolak oleh Config.KeyValidator.
+var ErrInvalidKey = errors.New("invalid key")
+
+// ErrKeyExists dikembalikan oleh Set ketika key yang diberikan sudah ada dan belum kedaluwarsa.
+var ErrKeyExists = errors.New("data already exists")
+
+// ErrTypeMismatch dikembalikan ketika isi store tidak dapat dikonversi ke tipe
+// yang diminta, misalnya ketika GetErr[int] dipanggil untuk key berisi JSON.
+var ErrTypeMismatch = errors.New("type mismatch")
+
+// ErrNotFound dikembalikan oleh GetErr ketika key tidak ada atau sudah kedaluwarsa.
+var ErrNotFound = errors.New("key not found")
+
+// ErrBackend membungkus kesalahan dari database, misalnya ketika file database
+// tidak dapat dibuka atau query SQLite gagal.
+var ErrBackend = errors.New("storage backend failure")
+
 // Struktur `App` digunakan untuk mengelola seluruh aplikasi, termasuk konfigurasi, database, dan data cache.
 //
 // Field-field:
-//   - mu: Mutex untuk memastikan operasi thread-safe pada aplikasi, mencegah race condition.
+//   - shards: Bagian-bagian cache, masing-masing dengan map dan lock sendiri untuk memastikan operasi thread-safe.
 //   - start: Waktu start aplikasi dalam format Unix timestamp (uint64).
 //   - config: Objek konfigurasi aplikasi (Config) yang menyimpan pengaturan aplikasi.
 //   - db: Pointer ke objek database yang mengelola koneksi dan operasi database.
-//   - data: Cache data dalam bentuk map, yang menggunakan string sebagai key dan store.Store sebagai value.
 type App struct {
-	mu        sync.Mutex             // Mutex untuk memastikan thread-safe akses ke field dalam struct App.
-	db        *database              // Pointer ke objek database yang digunakan aplikasi.
-	data      map[string]store.Store // Cache data aplikasi dalam map, dengan string sebagai key dan store.Store sebagai value.
-	data_size uint64                 // ukuran total data berserta key
-	start     uint64                 // Timestamp yang merepresentasikan waktu mulai aplikasi.
-	config    Config                 // Konfigurasi aplikasi, berisi pengaturan penting.
+	shards    []*shard              // Bagian cache sesuai Config.Shards, lihat shardFor.
+	seed      maphash.Seed          // Seed hash untuk membagi key ke shard.
+	db        *database             // Pointer ke objek database, diganti hanya ketika semua lock shard dipegang.
+	decodeErr atomic.Uint64         // Jumlah kegagalan konversi data saat Get.
+	evmu      sync.Mutex            // Mutex untuk antrean callback penghapusan.
+	evicted   []eviction            // Antrean callback penghapusan yang belum dijalankan.
+	smu       sync.Mutex            // Mutex untuk stale.
+	stale     map[string]struct{}   // Key kedaluwarsa yang ditemukan saat dibaca, dihapus oleh sweep berikutnya.
+	stats     counters              // Penghitung untuk Stats.
+	wmu       sync.Mutex            // Mutex untuk daftar watcher.
+	watchers  map[string][]*watcher // Watcher yang didaftarkan oleh WatchKey per key.
+	subs      []*subscriber         // Subscriber yang didaftarkan oleh Subscribe.
+	nwatch    int                   // Jumlah watcher di dalam watchers.
+	nextSub   uint64                // ID terakhir yang diberikan ke watcher atau subscriber.
+	clearing  bool                  // True selama Clear berjalan, agar subscriber hanya menerima OpClear.
+	stop      chan struct{}         // Ditutup oleh Close untuk menghentikan runNode.
+	closing   chan struct{}         // Ditutup di awal Close agar publish berhenti memblokir.
+	closeOnce sync.Once             // Menjamin closing hanya ditutup sekali.
+	loc       *time.Location        // Zona waktu dari Config.Timezone untuk menampilkan timestamp.
+	start     uint64                // Timestamp yang merepresentasikan waktu mulai aplikasi.
+	config    Config                // Konfigurasi aplikasi, berisi pengaturan penting.
 }
 
-// Variabel global `app` adalah instance dari struct `App` yang digunakan di seluruh aplikasi.
-var app App = App{}
+// Variabel global `app` adalah instance dari struct `App` yang digunakan oleh fungsi-fungsi
+// tingkat paket. Instance ini diganti setiap kali New dipanggil. Sebelum New
+// dipanggil, instance global memakai Config default tanpa database, sehingga
+// fungsi tingkat paket tetap dapat dipakai dan tidak pernah panic.
+var app = defaultApp()
+
+// defaultApp membuat instance global awal dengan Config default.
+func defaultApp() *App {
+	c, err := NewCache()
+	if err != nil {
+		// Config default tidak memakai database maupun zona waktu, sehingga tidak pernah gagal
+		panic(err)
+	}
+	return c
+}
 
 // New menginisialisasi aplikasi dengan konfigurasi yang diberikan.
 // Jika konfigurasi tidak disediakan, aplikasi akan menggunakan nilai default.
@@ -77,63 +320,475 @@ var app App = App{}
 // Jika Path untuk database diberikan, aplikasi akan menginisialisasi
 // database dan memuat data dari database ke dalam cache.
 func New(config ...Config) error {
-	app = App{}
+	c, err := NewCache(config...)
+	if err != nil {
+		return err
+	}
+	// Instance lama ditutup agar proses pemeriksaannya berhenti
+	old := app
+	app = c
+	return old.Close()
+}
+
+// NewCache membuat instance cache baru yang berdiri sendiri dari instance global.
+// Beberapa instance dapat digunakan bersamaan dalam satu proses, misalnya satu
+// cache untuk setiap tenant. Instance harus ditutup dengan Close setelah tidak dipakai.
+//
+// Parameter:
+//   - config (opsional) (Config): Konfigurasi instance. Jika tidak disediakan,
+//     nilai default akan digunakan.
+//
+// Mengembalikan:
+//   - *App: Instance cache yang baru.
+//   - error: Kesalahan jika database gagal diinisialisasi atau dimuat.
+func NewCache(config ...Config) (*App, error) {
+	app := &App{}
 	// Mengatur konfigurasi default
 	app.config = Config{}
 	// Jika ada konfigurasi yang diberikan, gunakan konfigurasi tersebut
 	if len(config) > 0 {
 		app.config = config[0]
 	}
+	// Konfigurasi divalidasi sebelum proses latar belakang dijalankan
+	if err := app.config.validate(); err != nil {
+		return nil, err
+	}
+	app.loc, _ = app.config.Timezone.Location() // Sudah diperiksa oleh validate
 	// Menginisialisasi aplikasi
 	app.init()
-	// Jika Path database tidak kosong, inisialisasi database
+	defer app.dispatch()
+	// Jika Path database tidak kosong, muat data dari database
 	if app.config.Path != "" {
-		if err := app.InitializeDB(); err != nil {
-			return err
+		if err := app.load(); err != nil {
+			app.Close()
+			return nil, err
 		}
-		// Membuat tabel jika belum ada
-		if err := app.db.CreateTableIfNotExist(); err != nil {
-			return err
+	}
+	return app, nil
+}
+
+// minTimeoutCheck adalah nilai Config.TimeoutCheck terkecil yang diterima (dalam milidetik).
+const minTimeoutCheck = 10
+
+// validate memeriksa nilai dan kombinasi opsi Config sebelum nilai default
+// diterapkan. Semua masalah yang ditemukan dikembalikan sekaligus.
+//
+// Mengembalikan:
+//   - error: ErrWriteMode, kesalahan zona waktu, atau ErrInvalidConfig yang
+//     menjelaskan opsi yang bermasalah; nil jika Config dapat digunakan.
+func (c Config) validate() error {
+	var errs []error
+	invalid := func(format string, args ...any) {
+		errs = append(errs, fmt.Errorf("%w: "+format, append([]any{ErrInvalidConfig}, args...)...))
+	}
+	if c.WriteThrough && c.WriteBehind {
+		errs = append(errs, ErrWriteMode)
+	}
+	if _, err := c.Timezone.Location(); err != nil {
+		errs = append(errs, err)
+	}
+	if c.Path == "" {
+		// Opsi berikut hanya berlaku untuk database dan akan diabaikan diam-diam
+		for _, opt := range []struct {
+			name string
+			set  bool
+		}{
+			{"WriteBehind", c.WriteBehind}, {"WriteThrough", c.WriteThrough},
+			{"SQLiteWAL", c.SQLiteWAL}, {"VacuumOnClose", c.VacuumOnClose},
+		} {
+			if opt.set {
+				invalid("%s requires Path", opt.name)
+			}
 		}
-		// Mengambil semua data dari database
-		rows, err := app.db.FindALL()
+	}
+	if c.EvictionPolicy < PolicyNone || c.EvictionPolicy > PolicyLFU {
+		invalid("unknown EvictionPolicy %d", c.EvictionPolicy)
+	}
+	if c.CleanStrategy < CleanFullScan || c.CleanStrategy > CleanSampled {
+		invalid("unknown CleanStrategy %d", c.CleanStrategy)
+	}
+	if c.TimeoutCheck != 0 && c.TimeoutCheck < minTimeoutCheck {
+		invalid("TimeoutCheck %d is below the minimum of %d", c.TimeoutCheck, minTimeoutCheck)
+	}
+	if c.MaxSubscribers < 0 {
+		invalid("MaxSubscribers %d is negative", c.MaxSubscribers)
+	}
+	if c.Shards < 0 {
+		invalid("Shards %d is negative", c.Shards)
+	}
+	if c.CleanWorkers < 0 {
+		invalid("CleanWorkers %d is negative", c.CleanWorkers)
+	}
+	if c.CleanExpiredRatio > 1 {
+		invalid("CleanExpiredRatio %v is above 1", c.CleanExpiredRatio)
+	}
+	if c.MAX_MEM != 0 && c.MIN_MEM_ALLOCATION > uint64(c.MAX_MEM) {
+		invalid("MIN_MEM_ALLOCATION %d exceeds MAX_MEM %d", c.MIN_MEM_ALLOCATION, c.MAX_MEM)
+	}
+	// TTLJitter dipakai sebagai batas rand.Int63n sehingga harus muat di int64
+	if c.TTLJitter >= math.MaxInt64 {
+		invalid("TTLJitter %d is too large", c.TTLJitter)
+	}
+	return errors.Join(errs...)
+}
+
+// loadPageSize adalah jumlah baris yang diambil dari database dalam satu halaman oleh load.
+const loadPageSize = 1000
+
+// load menginisialisasi database dan memuat data di dalamnya ke dalam cache.
+// Baris diambil per halaman sehingga tabel yang besar tidak perlu dimuat ke
+// memori sekaligus. Pemuatan ke sebuah shard berhenti ketika bagiannya dari
+// MaxEntries atau MAX_MEM tercapai, dan seluruh pemuatan berhenti ketika semua
+// shard penuh. Baris yang tidak termuat tetap berada di database tanpa dihapus.
+//
+// Mengembalikan:
+//   - error: Kesalahan jika database gagal diinisialisasi atau data gagal dimuat.
+func (app *App) load() error {
+	if err := app.InitializeDB(); err != nil {
+		return err
+	}
+	// Membuat tabel jika belum ada
+	if err := app.db.CreateTableIfNotExist(); err != nil {
+		return err
+	}
+	now := app.now()
+	app.lockAll()
+	defer app.unlockAll()
+	// Perubahan yang perlu ditulis kembali dikumpulkan lalu disimpan dalam satu
+	// transaksi, sehingga proses yang terhenti di tengah tidak meninggalkan tabel setengah jadi
+	var ops []writeOp
+	var after uint64
+	full := make(map[*shard]bool)
+	for {
+		// Mengambil halaman berikutnya dari data yang belum kedaluwarsa
+		rows, err := app.db.FindUnexpired(now, after, loadPageSize)
 		if err != nil {
 			return err
 		}
 		// Memasukkan data yang diambil dari database ke dalam cache
 		for i := range *rows {
 			val := (*rows)[i]
+			after = val.ID
+			// Blob yang terpotong atau rusak dilewati agar tidak merusak cache
+			parsed, err := store.ParseStoreChecked(val.Value)
+			if errors.Is(err, ErrUnsupportedVersion) {
+				// Data dari versi cago yang lebih baru tidak boleh dibuang diam-diam
+				return fmt.Errorf("key %q: %w", val.Key, err)
+			}
+			if err != nil {
+				app.logf("cago: load %q: %v", val.Key, err)
+				continue
+			}
+			// Baris lama tanpa kolom metadata belum tersaring oleh query, sehingga
+			// masa berlakunya diperiksa dari header store
+			if expired(parsed, now) {
+				ops = append(ops, writeOp{key: val.Key, remove: true})
+				continue
+			}
+			data, err := app.upgrade(val.Key, parsed)
+			if err != nil {
+				return err
+			}
+			// Shard yang sudah penuh tidak menghapus entri yang baru dimuat
+			if s := app.shardFor(val.Key); full[s] || app.full(s, val.Key, data) {
+				full[s] = true
+				if len(full) == len(app.shards) {
+					return app.db.apply(ops)
+				}
+				continue
+			}
+			if data.Version() != parsed.Version() {
+				ops = append(ops, writeOp{key: val.Key, data: data})
+			}
 			// Menambahkan data ke cache berdasarkan key tertentu
-			app.data[val.Key] = store.ParseStore(val.Value)
+			if err := app.setEntry(val.Key, data); err != nil {
+				return err
+			}
 		}
+		if len(*rows) < loadPageSize {
+			return app.db.apply(ops)
+		}
+	}
+}
+
+// Close menghentikan proses pemeriksaan entri kedaluwarsa dan menutup koneksi
+// database milik instance. Data di memori tetap dapat dibaca, tetapi perubahan
+// berikutnya tidak lagi disimpan ke database. Jika Config.FlushExpiredOnClose
+// aktif, entri yang sudah kedaluwarsa dihapus terlebih dahulu sehingga
+// OnExpire dan OnEvict tetap dipanggil untuk entri tersebut. Jika
+// Config.VacuumOnClose aktif, Compact dijalankan sebelum database ditutup.
+//
+// Mengembalikan:
+//   - error: Kesalahan jika Compact gagal atau koneksi database gagal ditutup.
+func (app *App) Close() error {
+	defer app.dispatch()
+	// Subscriber yang memblokir dilepas sebelum mengambil lock, karena
+	// pengiriman ke subscriber tersebut terjadi selama lock shard dipegang
+	app.closeOnce.Do(func() { close(app.closing) })
+	if app.config.FlushExpiredOnClose {
+		// Dijalankan sebelum database ditutup agar penghapusan ikut tersimpan.
+		// cleanup memeriksa ulang setiap key, sehingga aman berjalan bersamaan dengan runNode.
+		app.cleanup(app.now())
+	}
+	app.lockAll()
+	defer app.unlockAll()
+	if app.stop != nil {
+		close(app.stop)
+		app.stop = nil
+	}
+	app.closeWatchers()
+	if app.db == nil {
 		return nil
 	}
-	return nil
+	var err error
+	if app.config.VacuumOnClose {
+		err = app.compact()
+	}
+	err = errors.Join(err, app.db.close())
+	app.db = nil
+	return err
+}
+
+// Close menjalankan App.Close pada instance global yang dibuat oleh New.
+func Close() error {
+	return app.Close()
+}
+
+// upgrade memastikan store yang dimuat dari database memakai format terbaru.
+// Store versi lama akan di-upgrade, kecuali jika StrictVersion aktif sehingga
+// store tersebut ditolak. Pemanggil bertanggung jawab menyimpan hasilnya ke database.
+//
+// Parameter:
+//   - key (string): Key dari store yang sedang dimuat.
+//   - data (store.Store): Store hasil ParseStore.
+//
+// Mengembalikan:
+//   - store.Store: Store dalam format terbaru, atau Store kosong jika data tidak valid.
+//   - error: ErrUnsupportedVersion jika versi store tidak dapat digunakan.
+func (app *App) upgrade(key string, data store.Store) (store.Store, error) {
+	if len(data) == 0 {
+		return data, nil
+	}
+	if data.Version() == store.CurrentVersion {
+		return data, nil
+	}
+	if app.config.StrictVersion {
+		return nil, fmt.Errorf("key %q: %w: %d", key, ErrUnsupportedVersion, data.Version())
+	}
+	upgraded, err := data.Upgrade()
+	if err != nil {
+		return nil, fmt.Errorf("key %q: %w", key, err)
+	}
+	return upgraded, nil
 }
 
 // runNode menjalankan proses yang terus-menerus untuk memeriksa data dalam cache.
 // Fungsi ini berfungsi untuk menghapus entri yang sudah kedaluwarsa berdasarkan MaxAge yang ditentukan.
-func (app *App) runNode() {
-	// Loop tanpa henti untuk terus memeriksa data dalam cache
+func (app *App) runNode(stop <-chan struct{}) {
+	// Loop untuk terus memeriksa data dalam cache sampai instance ditutup
 	for {
-		// Tidur selama waktu yang ditentukan oleh TimeoutCheck dalam milidetik
+		// Menunggu selama waktu yang ditentukan oleh TimeoutCheck dalam milidetik
 		// untuk mengatur interval pemeriksaan entri yang kedaluwarsa.
-		time.Sleep(time.Duration(app.config.TimeoutCheck) * time.Millisecond)
-
-		// Iterasi melalui setiap entri dalam cache
-		for k, v := range app.data {
-			// Memeriksa apakah MaxAge untuk entri ini tidak sama dengan 0
-			if v.MaxAge() != 0 {
-				// Jika waktu sekarang dikurangi waktu pembuatan entri masih dalam batas waktu
-				if uint64(time.Now().UnixMilli())-v.CreateAt() >= v.MaxAge() {
-					// Menghapus entri dari cache berdasarkan kunci
-					Remove(k)
-				}
+		select {
+		case <-stop:
+			return
+		case <-time.After(time.Duration(app.config.TimeoutCheck) * time.Millisecond):
+		}
+
+		app.sweep(app.now())
+		app.dispatch()
+	}
+}
+
+// cleanup menghapus semua entri yang sudah kedaluwarsa pada waktu now (dalam
+// milidetik) dari cache dan database, lalu membuang catatan penghapusan lama.
+// Shard dibersihkan satu per satu, sehingga hanya shard yang sedang dibersihkan
+// yang terkunci. Callback penghapusan hanya diantrekan, sehingga pemanggil harus menjalankan dispatch.
+//
+// Mengembalikan:
+//   - int: Jumlah entri yang dihapus.
+func (app *App) cleanup(now uint64) int {
+	removed := 0
+	for _, s := range app.shards {
+		removed += app.cleanShard(s, now)
+	}
+	return removed
+}
+
+// cleanShard menghapus semua entri yang sudah kedaluwarsa pada waktu now dari
+// shard s, lalu membuang catatan penghapusan lama milik shard tersebut.
+// Hanya lock s yang diambil.
+//
+// Mengembalikan:
+//   - int: Jumlah entri yang dihapus.
+func (app *App) cleanShard(s *shard, now uint64) int {
+	// Mengumpulkan key yang kedaluwarsa di bawah read lock agar iterasi
+	// tidak berbenturan dengan penulisan map oleh goroutine lain.
+	keys := []string{}
+	s.mu.RLock()
+	for k, v := range s.data {
+		if app.expiredEntry(k, v, now) {
+			keys = append(keys, k)
+		}
+	}
+	s.mu.RUnlock()
+
+	// Menghapus entri dari cache berdasarkan kunci. Setiap entri diperiksa
+	// ulang karena dapat diperbarui setelah dikumpulkan.
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	removed := 0
+	for _, k := range keys {
+		if v, ok := s.data[k]; ok && app.expiredEntry(k, v, now) {
+			app.expireEntry(k)
+			removed++
+		}
+	}
+	app.pruneRemoved(s, now)
+	return removed
+}
+
+// expireEntry menghapus key yang sudah kedaluwarsa dari cache dan database.
+// Fungsi ini harus dipanggil ketika write lock shard dari key sedang dipegang.
+func (app *App) expireEntry(key string) {
+	app.deleteEntry(key, EvictExpired)
+	if app.db != nil {
+		if err := app.db.RemoveByKey(key); err != nil {
+			app.logf("cago: expire %q: %v", key, err)
+		}
+	}
+}
+
+// setEntry menyimpan store ke dalam cache dan memperbarui urutan EvictionPolicy.
+// Sebelum store disimpan, entri lain di shard yang sama akan dihapus dari cache
+// dan database jika bagian shard dari MaxEntries terlampaui, atau jika bagian
+// shard dari MAX_MEM terlampaui dan EvictOldestOnMaxMem aktif. Watcher key
+// menerima OpSet untuk key baru dan OpPut untuk key yang ditimpa. Fungsi ini
+// harus dipanggil ketika write lock shard dari key sedang dipegang.
+//
+// Mengembalikan:
+//   - error: ErrMemoryLimit jika store tidak dapat dimuat dalam batas MAX_MEM.
+func (app *App) setEntry(key string, data store.Store) error {
+	s := app.shardFor(key)
+	size := uint64(len(key) + len(data))
+	limit := app.memLimit()
+	var oldSize uint64
+	old, replaced := s.data[key]
+	if replaced {
+		oldSize = uint64(len(key) + len(old))
+	}
+	if size > limit || (!app.config.EvictOldestOnMaxMem && s.data_size-oldSize+size > limit) {
+		return fmt.Errorf("key %q: %w", key, ErrMemoryLimit)
+	}
+
+	// Entri lama dilepas terlebih dahulu agar tidak terpilih untuk dihapus
+	if el, ok := s.elems[key]; ok {
+		s.order.Remove(el)
+		delete(s.elems, key)
+		delete(s.data, key)
+		s.data_size -= oldSize
+	}
+	for s.data_size+size > limit || app.atCapacity(s) {
+		victim := app.victim(s)
+		app.deleteEntry(victim, EvictCapacity)
+		if app.db != nil {
+			if err := app.db.RemoveByKey(victim); err != nil {
+				app.logf("cago: evict %q: %v", victim, err)
+			}
+		}
+	}
+
+	s.data[key] = data
+	s.data_size += size
+	// Key yang disimpan kembali tidak lagi dilaporkan sebagai terhapus oleh DiffSince
+	delete(s.removed, key)
+	if _, ok := s.freq[key]; !ok && app.config.EvictionPolicy == PolicyLFU {
+		s.freq[key] = 1 // Key baru dihitung sekali agar tidak langsung kalah dari key lain
+	}
+
+	// Store baru hampir selalu memiliki CreateAt terbaru, sehingga pencarian
+	// posisi dimulai dari belakang daftar. Kebijakan lain tidak membutuhkan
+	// urutan CreateAt, sehingga entri baru langsung ditaruh di belakang.
+	mark := s.order.Back()
+	for app.config.EvictionPolicy == PolicyNone && mark != nil && s.data[mark.Value.(string)].CreateAt() > data.CreateAt() {
+		mark = mark.Prev()
+	}
+	if mark == nil {
+		s.elems[key] = s.order.PushFront(key)
+	} else {
+		s.elems[key] = s.order.InsertAfter(key, mark)
+	}
+
+	op := OpSet
+	if replaced && !app.expiredEntry(key, old, app.now()) {
+		op = OpPut
+	}
+	// Store baru menggantikan waktu akses SlidingTTL dari store lama
+	delete(s.access, key)
+	app.notify(op, key, data)
+	return nil
+}
+
+// deleteEntry menghapus key dari cache, mencatat waktu penghapusannya, dan
+// mengantrekan callback OnExpire/OnEvict sesuai reason. Callback baru dijalankan
+// oleh dispatch setelah lock dilepas, sedangkan watcher key langsung menerima
+// OpExpire atau OpRemove. Fungsi ini harus dipanggil ketika write lock shard
+// dari key sedang dipegang.
+//
+// Mengembalikan:
+//   - bool: True jika key ada sebelum dihapus.
+func (app *App) deleteEntry(key string, reason EvictReason) bool {
+	s := app.shardFor(key)
+	data, ok := s.data[key]
+	if !ok {
+		return false
+	}
+	delete(s.data, key)
+	s.data_size -= uint64(len(key) + len(data))
+	s.order.Remove(s.elems[key])
+	delete(s.elems, key)
+	delete(s.freq, key)
+	delete(s.access, key)
+	s.removed[key] = app.now()
+	app.recordEvict(reason)
+	app.notifyEvict(key, data, reason)
+	op := OpRemove
+	if reason == EvictExpired {
+		op = OpExpire
+	}
+	app.notify(op, key, data)
+	return true
+}
+
+// pruneRemoved membuang catatan penghapusan shard s yang lebih tua dari DiffWindow.
+// Fungsi ini harus dipanggil ketika write lock s sedang dipegang.
+func (app *App) pruneRemoved(s *shard, now uint64) {
+	for key, at := range s.removed {
+		if now-at > app.config.DiffWindow {
+			delete(s.removed, key)
+			if at > s.pruned {
+				s.pruned = at
 			}
 		}
 	}
 }
 
+// expired memeriksa apakah store sudah kedaluwarsa pada waktu now (dalam milidetik).
+// CreateAt dan MaxAge keduanya dalam milidetik, sehingga store kedaluwarsa ketika
+// now - CreateAt >= MaxAge. Store dengan MaxAge 0 tidak pernah kedaluwarsa.
+func expired(v store.Store, now uint64) bool {
+	return v.IsExpiredAt(now)
+}
+
+// now mengembalikan waktu saat ini dalam milidetik unix dari Config.Clock,
+// atau dari jam sistem jika Config.Clock tidak diatur.
+func (app *App) now() uint64 {
+	if app.config.Clock != nil {
+		return uint64(app.config.Clock())
+	}
+	return uint64(time.Now().UnixMilli())
+}
+
 // init menginisialisasi nilai maksimum dan minimum memori untuk aplikasi.
 // Jika MAX_MEM dan MIN_MEM_ALLOCATION tidak ditentukan, akan diatur
 // ke nilai default yang sesuai.
@@ -149,33 +804,80 @@ func (app *App) init() {
 	if app.config.TimeoutCheck == 0 {
 		app.config.TimeoutCheck = 10000 // 1 MB
 	}
+	if app.config.DiffWindow == 0 {
+		app.config.DiffWindow = 60000 // 1 menit
+	}
+	if app.config.EvictionSampleSize <= 0 {
+		app.config.EvictionSampleSize = 5
+	}
+	if app.config.FlushInterval == 0 {
+		app.config.FlushInterval = 100
+	}
+	if app.config.FlushSize <= 0 {
+		app.config.FlushSize = 100
+	}
+	if app.config.SQLiteBusyTimeout == 0 {
+		app.config.SQLiteBusyTimeout = 5000
+	}
+	if app.config.TableName == "" {
+		app.config.TableName = "cagos"
+	}
+	if app.config.CleanSampleSize <= 0 {
+		app.config.CleanSampleSize = 20
+	}
+	if app.config.CleanExpiredRatio <= 0 {
+		app.config.CleanExpiredRatio = 0.25
+	}
+	if app.config.Shards == 0 {
+		app.config.Shards = 1
+	}
+	if app.config.CleanWorkers == 0 {
+		app.config.CleanWorkers = 1
+	}
 
-	// Menginisialisasi data cache untuk menyimpan store
-	app.data = make(map[string]store.Store)
+	// Menginisialisasi shard data cache untuk menyimpan store
+	app.shards = make([]*shard, app.config.Shards)
+	for i := range app.shards {
+		app.shards[i] = newShard()
+	}
+	app.seed = maphash.MakeSeed()
+	app.stale = make(map[string]struct{})
 	// Menyimpan waktu mulai aplikasi dalam milidetik
-	app.start = uint64(time.Now().UnixMilli())
-	app.data_size = uint64(0)
+	app.start = app.now()
 
-	go app.runNode()
+	app.stop = make(chan struct{})
+	app.closing = make(chan struct{})
+	go app.runNode(app.stop)
 }
 
-// TotalSize menghitung ukuran total dari semua key dan nilai yang disimpan dalam map app.data.
-// Ukuran dihitung sebagai jumlah byte dari panjang string key dan panjang nilai (store)
-// yang disimpan. Fungsi ini efisien dan tidak menggunakan banyak memori tambahan.
+// Size menghitung ukuran total dari semua key dan nilai yang belum kedaluwarsa.
+// Ukuran setiap entri adalah panjang key ditambah panjang store lengkap dengan
+// header, yaitu ukuran yang sama yang dihitung terhadap MAX_MEM. Ukuran ini
+// sudah dicatat ketika entri disimpan, sehingga Size hanya perlu mengurangi
+// entri yang sudah kedaluwarsa tetapi belum dihapus. Setiap shard dihitung di
+// bawah read lock-nya sendiri.
 //
 // Mengembalikan:
 // - uint64: Total ukuran data (key dan value) dalam byte.
-func Size() uint64 {
-	var totalSize uint64
-	// Iterasi melalui setiap pasangan key-value di dalam map data
-	for key, store := range app.data {
-		// Hitung ukuran key (string) dalam byte
-		totalSize += uint64(len(key))
-		// Hitung ukuran nilai (store) dengan fungsi Length(true)
-		// Length(true) menghitung ukuran store secara keseluruhan
-		totalSize += store.Length(true)
+func (app *App) Size() uint64 {
+	now := app.now()
+	total := uint64(0)
+	for _, s := range app.shards {
+		s.mu.RLock()
+		total += s.data_size
+		for key, value := range s.data {
+			if app.expiredEntry(key, value, now) {
+				total -= uint64(len(key) + len(value))
+			}
+		}
+		s.mu.RUnlock()
 	}
-	return totalSize
+	return total
+}
+
+// Size menjalankan App.Size pada instance global yang dibuat oleh New.
+func Size() uint64 {
+	return app.Size()
 }
 
 // Set menyimpan nilai ke dalam store dengan key yang diberikan.
@@ -186,302 +888,694 @@ func Size() uint64 {
 //   - key (string): Key unik yang digunakan untuk mengidentifikasi nilai dalam store.
 //   - value (store.Compare): Nilai yang akan disimpan. Harus memiliki tipe data yang sesuai
 //     dengan interface Compare, seperti integer, float, string, atau tipe apapun yang diizinkan.
-//   - maxAge (opsional) (uint64): Waktu maksimal dalam milidetik selama nilai akan disimpan.
-//     Jika tidak disertakan, nilai ini akan diabaikan.
+//   - maxAge (opsional) (uint64): Waktu maksimal dalam milidetik selama nilai akan disimpan,
+//     dihitung dari CreateAt. Jika tidak disertakan atau 0, nilai tidak pernah kedaluwarsa.
 //
 // Mengembalikan:
 // - error: Kesalahan jika terjadi selama penyimpanan data.
+func (app *App) Set(key string, value store.Compare, maxAge ...uint64) error {
+	if err := app.checkKey(key); err != nil {
+		return err
+	}
+	data, err := app.encode(key, value, maxAge)
+	if err != nil {
+		return err
+	}
+
+	defer app.dispatch()
+	s := app.shardFor(key)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if old, ok := s.data[key]; ok && !app.expiredEntry(key, old, app.now()) {
+		return ErrKeyExists
+	}
+	return app.persist(key, data)
+}
+
+// Set menjalankan App.Set pada instance global yang dibuat oleh New.
 func Set(key string, value store.Compare, maxAge ...uint64) error {
-	app.mu.Lock()
-	defer app.mu.Unlock()
-	_, ok := app.data[key]
-	if ok {
-		return fmt.Errorf("data already exists")
+	return app.Set(key, value, maxAge...)
+}
+
+// persist menyimpan store ke dalam cache lalu ke database jika ada.
+// Jika Config.WriteThrough aktif, urutannya dibalik: store ditulis ke database
+// terlebih dahulu dan cache hanya diperbarui jika penulisan tersebut berhasil.
+// Fungsi ini harus dipanggil ketika write lock shard dari key sedang dipegang.
+//
+// Mengembalikan:
+//   - error: Kesalahan jika batas memori terlampaui atau penyimpanan ke database gagal.
+func (app *App) persist(key string, data store.Store) error {
+	if app.config.WriteThrough && app.db != nil {
+		return app.writeThrough(key, data)
+	}
+	if err := app.setEntry(key, data); err != nil {
+		return err
+	}
+	if app.db != nil {
+		return app.db.InsertOrUpdate(key, data)
+	}
+	return nil
+}
+
+// writeThrough menulis store ke database terlebih dahulu, lalu ke cache hanya
+// jika penulisan tersebut berhasil. Jika cache menolak store, database
+// dikembalikan ke nilai lama. app.db harus tidak nil, dan fungsi ini harus
+// dipanggil ketika write lock shard dari key sedang dipegang.
+//
+// Mengembalikan:
+//   - error: Kesalahan jika database gagal ditulis atau batas memori terlampaui.
+func (app *App) writeThrough(key string, data store.Store) error {
+	if err := app.db.InsertOrUpdate(key, data); err != nil {
+		return err
+	}
+	if err := app.setEntry(key, data); err != nil {
+		// Database dikembalikan agar tidak menyimpan nilai yang tidak ada di cache
+		var undo error
+		if old, ok := app.shardFor(key).data[key]; ok {
+			undo = app.db.InsertOrUpdate(key, old)
+		} else {
+			undo = app.db.RemoveByKey(key)
+		}
+		return errors.Join(err, undo)
+	}
+	return nil
+}
+
+// checkKey memeriksa key terhadap Config.MaxKeyLength dan Config.KeyValidator.
+//
+// Parameter:
+//   - key (string): Key yang akan disimpan.
+//
+// Mengembalikan:
+//   - error: Kesalahan yang membungkus ErrInvalidKey jika key ditolak.
+func (app *App) checkKey(key string) error {
+	if limit := app.config.MaxKeyLength; limit > 0 && len(key) > limit {
+		return fmt.Errorf("%w: key is %d bytes, limit is %d", ErrInvalidKey, len(key), limit)
+	}
+	if app.config.KeyValidator != nil {
+		if err := app.config.KeyValidator(key); err != nil {
+			return fmt.Errorf("%w %q: %w", ErrInvalidKey, key, err)
+		}
+	}
+	return nil
+}
+
+// encode mengubah value menjadi store.Store untuk Set dan Put. maxAge ditambah
+// jitter dari Config.TTLJitter, lalu ukuran payload diperiksa terhadap Config.MaxValueSize.
+//
+// Parameter:
+//   - key (string): Key dari nilai, digunakan di pesan kesalahan.
+//   - value (store.Compare): Nilai yang akan dikonversi.
+//   - maxAge ([]uint64): maxAge opsional yang diberikan ke Set atau Put.
+//
+// Mengembalikan:
+//   - store.Store: Store yang siap disimpan.
+//   - error: Kesalahan jika value tidak dapat dikonversi atau melebihi MaxValueSize.
+func (app *App) encode(key string, value store.Compare, maxAge []uint64) (store.Store, error) {
+	return app.encodeAt(key, value, app.now(), app.jitter(maxAge))
+}
+
+// encodeAt sama dengan encode, tetapi CreateAt ditetapkan ke now dan maxAge
+// dipakai apa adanya tanpa Config.TTLJitter, misalnya untuk SetAt yang tenggatnya harus tepat.
+//
+// Parameter:
+//   - key (string): Key dari nilai, digunakan di pesan kesalahan.
+//   - value (store.Compare): Nilai yang akan dikonversi.
+//   - now (uint64): Waktu pembuatan store dalam milidetik.
+//   - maxAge ([]uint64): maxAge opsional dalam milidetik.
+//
+// Mengembalikan:
+//   - store.Store: Store yang siap disimpan.
+//   - error: Kesalahan jika value tidak dapat dikonversi atau melebihi MaxValueSize.
+func (app *App) encodeAt(key string, value store.Compare, now uint64, maxAge []uint64) (store.Store, error) {
+	data, err := encode(now, value, maxAge...)
+	if err != nil {
+		return nil, err
+	}
+	if err := app.checkSize(key, data); err != nil {
+		return nil, err
+	}
+	return data, nil
+}
+
+// checkSize memeriksa ukuran payload store terhadap Config.MaxValueSize.
+//
+// Mengembalikan:
+//   - error: Kesalahan yang membungkus ErrValueTooLarge jika payload terlalu besar.
+func (app *App) checkSize(key string, data store.Store) error {
+	if limit := app.config.MaxValueSize; limit > 0 && data.Length() > limit {
+		return fmt.Errorf("key %q: value is %d bytes, limit is %d: %w", key, data.Length(), limit, ErrValueTooLarge)
+	}
+	return nil
+}
+
+// jitter menambahkan offset acak antara 0 dan Config.TTLJitter ke maxAge.
+// Offset hanya ditambahkan sehingga masa berlaku tidak pernah berkurang,
+// dan maxAge yang kosong atau 0 dikembalikan apa adanya.
+//
+// Parameter:
+//   - maxAge ([]uint64): maxAge opsional yang diberikan ke Set atau Put.
+//
+// Mengembalikan:
+//   - []uint64: maxAge setelah ditambah offset acak.
+func (app *App) jitter(maxAge []uint64) []uint64 {
+	if app.config.TTLJitter == 0 || len(maxAge) == 0 || maxAge[0] == 0 {
+		return maxAge
 	}
+	return []uint64{maxAge[0] + uint64(rand.Int63n(int64(app.config.TTLJitter)+1))}
+}
+
+// encode mengubah value menjadi store.Store sesuai dengan tipe datanya.
+// Fungsi ini digunakan oleh Set, Put, dan MSet: semua tipe integer disimpan
+// dalam bentuk biner big-endian 8 byte, sehingga dapat dibaca oleh Increment
+// maupun sebagai tipe integer lain selama nilainya muat, time.Time disimpan
+// sebagai detik dan nanodetik unix, string dan *url.URL disimpan sebagai teks,
+// net.IP dan []byte disimpan dalam bentuk byte-nya, dan tipe lain disimpan sebagai JSON.
+// Jenis nilai asal dicatat sebagai store.StoreKind agar decode dapat menolak tipe yang tidak sesuai.
+//
+// Parameter:
+//   - now (uint64): Waktu pembuatan store dalam milidetik unix.
+//   - value (store.Compare): Nilai yang akan dikonversi.
+//   - maxAge (opsional) (uint64): Waktu maksimal dalam milidetik selama nilai akan disimpan.
+//
+// Mengembalikan:
+//   - store.Store: Store yang berisi metadata dan value yang telah dikonversi.
+//   - error: Kesalahan jika value tidak dapat dikonversi.
+func encode(now uint64, value store.Compare, maxAge ...uint64) (store.Store, error) {
+	var (
+		data []byte
+		kind store.StoreKind
+	)
 	switch v := any(value).(type) {
 	case string:
-		data := store.NewStore([]byte(v), maxAge...)
-		if app.db != nil {
-			app.db.InsertOrUpdate(key, data)
-		}
-		app.data[key] = data
-		if app.db != nil {
-			if err := app.db.InsertOrUpdate(key, data); err != nil {
-				return err
-			}
-		}
+		data, kind = []byte(v), store.KindString
 	case int:
-		data := store.NewStore(lib.Int64ToByte(int64(v)), maxAge...)
-		if app.db != nil {
-			app.db.InsertOrUpdate(key, data)
-		}
-		app.data[key] = data
-		if app.db != nil {
-			if err := app.db.InsertOrUpdate(key, data); err != nil {
-				return err
-			}
-		}
+		data, kind = lib.Int64ToByte(int64(v)), store.KindInt
 	case int8:
-		data := store.NewStore(lib.Int8ToByte(int8(v)), maxAge...)
-		if app.db != nil {
-			app.db.InsertOrUpdate(key, data)
-		}
-		app.data[key] = data
-		if app.db != nil {
-			if err := app.db.InsertOrUpdate(key, data); err != nil {
-				return err
-			}
-		}
+		data, kind = lib.Int64ToByte(int64(v)), store.KindInt
 	case int16:
-		data := store.NewStore(lib.Int16ToByte(int16(v)), maxAge...)
-		if app.db != nil {
-			app.db.InsertOrUpdate(key, data)
-		}
-		app.data[key] = data
-		if app.db != nil {
-			if err := app.db.InsertOrUpdate(key, data); err != nil {
-				return err
-			}
-		}
+		data, kind = lib.Int64ToByte(int64(v)), store.KindInt
 	case int32:
-		data := store.NewStore(lib.Int32ToByte(int32(v)), maxAge...)
-		if app.db != nil {
-			app.db.InsertOrUpdate(key, data)
-		}
-		app.data[key] = data
-		if app.db != nil {
-			if err := app.db.InsertOrUpdate(key, data); err != nil {
-				return err
-			}
-		}
+		data, kind = lib.Int64ToByte(int64(v)), store.KindInt
 	case int64:
-		data := store.NewStore(lib.Int64ToByte(int64(v)), maxAge...)
-		if app.db != nil {
-			app.db.InsertOrUpdate(key, data)
-		}
-		app.data[key] = data
-		if app.db != nil {
-			if err := app.db.InsertOrUpdate(key, data); err != nil {
-				return err
-			}
-		}
+		data, kind = lib.Int64ToByte(v), store.KindInt
 	case uint:
-		data := store.NewStore(lib.Uint64ToByte(uint64(v)), maxAge...)
-		if app.db != nil {
-			app.db.InsertOrUpdate(key, data)
-		}
-		app.data[key] = data
-		if app.db != nil {
-			if err := app.db.InsertOrUpdate(key, data); err != nil {
-				return err
-			}
-		}
+		data, kind = lib.Uint64ToByte(uint64(v)), store.KindUint
 	case uint8:
-		data := store.NewStore(lib.Uint8ToByte(uint8(v)), maxAge...)
-		if app.db != nil {
-			app.db.InsertOrUpdate(key, data)
-		}
-		app.data[key] = data
-		if app.db != nil {
-			if err := app.db.InsertOrUpdate(key, data); err != nil {
-				return err
-			}
-		}
+		data, kind = lib.Uint64ToByte(uint64(v)), store.KindUint
 	case uint16:
-		data := store.NewStore(lib.Uint16ToByte(uint16(v)), maxAge...)
-		if app.db != nil {
-			app.db.InsertOrUpdate(key, data)
-		}
-		app.data[key] = data
-		if app.db != nil {
-			if err := app.db.InsertOrUpdate(key, data); err != nil {
-				return err
-			}
-		}
+		data, kind = lib.Uint64ToByte(uint64(v)), store.KindUint
 	case uint32:
-		data := store.NewStore(lib.Uint32ToByte(uint32(v)), maxAge...)
-		if app.db != nil {
-			app.db.InsertOrUpdate(key, data)
-		}
-		app.data[key] = data
-		if app.db != nil {
-			if err := app.db.InsertOrUpdate(key, data); err != nil {
-				return err
-			}
-		}
+		data, kind = lib.Uint64ToByte(uint64(v)), store.KindUint
 	case uint64:
-		data := store.NewStore(lib.Uint64ToByte(uint64(v)), maxAge...)
-		if app.db != nil {
-			app.db.InsertOrUpdate(key, data)
-		}
-		app.data[key] = data
-		if app.db != nil {
-			if err := app.db.InsertOrUpdate(key, data); err != nil {
-				return err
-			}
-		}
-	case float32, float64:
-		by, err := json.Marshal(value)
-		if err != nil {
-			return err
-		}
-		data := store.NewStore(by, maxAge...)
-		if app.db != nil {
-			app.db.InsertOrUpdate(key, data)
-		}
-		app.data[key] = data
-		if app.db != nil {
-			if err := app.db.InsertOrUpdate(key, data); err != nil {
-				return err
-			}
-		}
-	case any:
+		data, kind = lib.Uint64ToByte(v), store.KindUint
+	case bool:
+		data, kind = lib.BoolToByte(v), store.KindBool
+	case time.Time:
+		data, kind = lib.TimeToByte(v), store.KindTime
+	case *url.URL:
+		if v == nil {
+			return nil, fmt.Errorf("nil *url.URL")
+		}
+		data, kind = []byte(v.String()), store.KindString
+	case net.IP:
+		data, kind = []byte(v), store.KindBytes
+	case []byte:
+		data, kind = v, store.KindBytes
+	default:
 		by, err := json.Marshal(value)
 		if err != nil {
-			return err
-		}
-		data := store.NewStore(by, maxAge...)
-		if app.db != nil {
-			app.db.InsertOrUpdate(key, data)
+			return nil, err
 		}
-		app.data[key] = data
-		if app.db != nil {
-			if err := app.db.InsertOrUpdate(key, data); err != nil {
-				return err
-			}
+		data, kind = by, store.KindJSON
+		switch value.(type) {
+		case float32, float64:
+			kind = store.KindFloat
 		}
-	default:
-		return fmt.Errorf("unsupported type: %T", value)
 	}
-	return nil
+	s := store.NewStoreAt(data, now, maxAge...)
+	s[store.KindIndex] = uint8(kind) // Store baru belum dibagikan sehingga tag dapat ditulis langsung
+	return s, nil
 }
 
-// Get mengambil nilai dari store berdasarkan key yang diberikan.
-// Fungsi ini mengembalikan pointer ke nilai yang ditemukan. Jika tidak ada nilai
-// yang cocok dengan key, akan mengembalikan nil.
+// decode mengubah isi store menjadi nilai dengan tipe K.
+// Aturan konversi merupakan kebalikan dari encode. Store yang memiliki tag
+// jenis hanya dikonversi jika jenisnya sesuai dengan K, lihat readableAs.
 //
 // Parameter:
-//   - key (string): Key unik yang digunakan untuk mencari nilai dalam store.
-//
-// Tipe Parameter:
-//   - K (store.Compare): Tipe data yang diharapkan sesuai dengan interface Compare,
-//     seperti integer, float, string, atau tipe apapun yang diizinkan.
+//   - value (store.Store): Store yang akan dikonversi.
 //
 // Mengembalikan:
-//   - *K: Pointer ke nilai yang diambil dari store. Jika nilai tidak ditemukan,
-//     akan mengembalikan nil.
-func Get[K store.Compare](key string) *K {
-	app.mu.Lock()
-	defer app.mu.Unlock()
-
-	value, ok := app.data[key]
-	if !ok {
-		return nil // Mengembalikan nil jika key tidak ada
-	}
-
+//   - *K: Pointer ke nilai hasil konversi.
+//   - error: Kesalahan jika isi store tidak sesuai dengan tipe K.
+func decode[K store.Compare](value store.Store) (*K, error) {
 	var result K
+	if kind := value.Kind(); !readableAs[K](kind) {
+		return nil, fmt.Errorf("cannot read %s value as %v", kind, reflect.TypeOf((*K)(nil)).Elem())
+	}
 
 	// Menangani setiap tipe dalam switch
 	switch any(result).(type) {
 	case string:
 		result = any(value.Text()).(K)
 	case int:
-		intValue, err := value.Int()
+		intValue, err := value.Int64()
 		if err != nil {
-			fmt.Println("Error retrieving int:", err)
-			return nil // Tangani kesalahan dengan baik
+			return nil, fmt.Errorf("retrieving int: %w", err)
 		}
-		result = any(intValue).(K)
+		result = any(int(intValue)).(K)
 	case int8:
-		intValue, err := value.Int()
+		intValue, err := value.Int8()
 		if err != nil {
-			fmt.Println("Error retrieving int8:", err)
-			return nil // Tangani kesalahan dengan baik
+			return nil, fmt.Errorf("retrieving int8: %w", err)
 		}
-		result = any(int8(intValue)).(K) // Konversi jika perlu
+		result = any(intValue).(K)
 	case int16:
-		intValue, err := value.Int()
+		intValue, err := value.Int16()
 		if err != nil {
-			fmt.Println("Error retrieving int16:", err)
-			return nil // Tangani kesalahan dengan baik
+			return nil, fmt.Errorf("retrieving int16: %w", err)
 		}
-		result = any(int16(intValue)).(K) // Konversi jika perlu
+		result = any(intValue).(K)
 	case int32:
-		intValue, err := value.Int()
+		intValue, err := value.Int32()
 		if err != nil {
-			fmt.Println("Error retrieving int32:", err)
-			return nil // Tangani kesalahan dengan baik
+			return nil, fmt.Errorf("retrieving int32: %w", err)
 		}
-		result = any(int32(intValue)).(K) // Konversi jika perlu
+		result = any(intValue).(K)
 	case int64:
-		intValue, err := value.Int()
+		intValue, err := value.Int64()
 		if err != nil {
-			fmt.Println("Error retrieving int64:", err)
-			return nil // Tangani kesalahan dengan baik
+			return nil, fmt.Errorf("retrieving int64: %w", err)
 		}
-		result = any(int64(intValue)).(K) // Konversi jika perlu
+		result = any(intValue).(K)
 	case uint:
-		intValue, err := value.Int()
+		intValue, err := value.Uint64()
 		if err != nil {
-			fmt.Println("Error retrieving uint:", err)
-			return nil // Tangani kesalahan dengan baik
+			return nil, fmt.Errorf("retrieving uint: %w", err)
 		}
-		result = any(uint(intValue)).(K) // Konversi jika perlu
+		result = any(uint(intValue)).(K)
 	case uint8:
-		intValue, err := value.Int()
+		intValue, err := value.Uint8()
 		if err != nil {
-			fmt.Println("Error retrieving uint8:", err)
-			return nil // Tangani kesalahan dengan baik
+			return nil, fmt.Errorf("retrieving uint8: %w", err)
 		}
-		result = any(uint8(intValue)).(K) // Konversi jika perlu
+		result = any(intValue).(K)
 	case uint16:
-		intValue, err := value.Int()
+		intValue, err := value.Uint16()
 		if err != nil {
-			fmt.Println("Error retrieving uint16:", err)
-			return nil // Tangani kesalahan dengan baik
+			return nil, fmt.Errorf("retrieving uint16: %w", err)
 		}
-		result = any(uint16(intValue)).(K) // Konversi jika perlu
+		result = any(intValue).(K)
 	case uint32:
-		intValue, err := value.Int()
+		intValue, err := value.Uint32()
 		if err != nil {
-			fmt.Println("Error retrieving uint32:", err)
-			return nil // Tangani kesalahan dengan baik
+			return nil, fmt.Errorf("retrieving uint32: %w", err)
 		}
-		result = any(uint32(intValue)).(K) // Konversi jika perlu
+		result = any(intValue).(K)
 	case uint64:
-		intValue, err := value.Int()
+		intValue, err := value.Uint64()
 		if err != nil {
-			fmt.Println("Error retrieving uint64:", err)
-			return nil // Tangani kesalahan dengan baik
+			return nil, fmt.Errorf("retrieving uint64: %w", err)
 		}
-		result = any(uint64(intValue)).(K) // Konversi jika perlu
-	case float32:
-		intValue, err := value.Int()
+		result = any(intValue).(K)
+	case bool:
+		boolValue, err := value.Bool()
+		if err != nil {
+			return nil, fmt.Errorf("retrieving bool: %w", err)
+		}
+		result = any(boolValue).(K)
+	case time.Time:
+		timeValue, err := value.Time()
 		if err != nil {
-			fmt.Println("Error retrieving float32:", err)
-			return nil // Tangani kesalahan dengan baik
+			return nil, fmt.Errorf("retrieving time.Time: %w", err)
 		}
-		result = any(float32(intValue)).(K) // Konversi jika perlu
-	case float64:
-		intValue, err := value.Int()
+		result = any(timeValue).(K)
+	case *url.URL:
+		urlValue, err := url.Parse(value.Text())
 		if err != nil {
-			fmt.Println("Error retrieving float64:", err)
-			return nil // Tangani kesalahan dengan baik
+			return nil, fmt.Errorf("retrieving *url.URL: %w", err)
+		}
+		result = any(urlValue).(K)
+	case net.IP:
+		// Panjang diambil dari payload, bukan dari header yang bisa saja rusak
+		ip := value.Bytes()
+		if l := len(ip); l != net.IPv4len && l != net.IPv6len {
+			return nil, fmt.Errorf("retrieving net.IP: invalid length %d", l)
+		}
+		// Menyalin byte agar IP tidak berbagi memori dengan cache
+		result = any(append(net.IP{}, ip...)).(K)
+	case []byte:
+		// Menyalin byte agar hasil tidak berbagi memori dengan cache
+		result = any(append([]byte{}, value.Bytes()...)).(K)
+	case float32, float64:
+		// Float disimpan sebagai JSON oleh Set dan Put
+		if err := value.JSON(&result); err != nil {
+			return nil, fmt.Errorf("retrieving %T: %w", result, err)
 		}
-		result = any(float64(intValue)).(K) // Konversi jika perlu
 	default:
 		err := value.JSON(&result)
 		if err != nil {
-			fmt.Println("Error unmarshaling JSON:", err)
-			return nil // Tangani kesalahan dengan baik
+			return nil, fmt.Errorf("unmarshaling JSON: %w", err)
 		}
 	}
 
-	return &result
+	return &result, nil
+}
+
+// readableAs memeriksa apakah store dengan jenis kind dapat dibaca sebagai K.
+// Store tanpa tag selalu diterima agar blob lama tetap dapat dibaca. Integer
+// bertanda dan tak bertanda saling dapat dibaca selama nilainya muat, teks dapat
+// dibaca dari byte dan JSON, dan []byte dapat membaca jenis apa pun.
+func readableAs[K store.Compare](kind store.StoreKind) bool {
+	if kind == store.KindUnknown {
+		return true
+	}
+	var zero K
+	switch any(zero).(type) {
+	case string, *url.URL:
+		return kind == store.KindString || kind == store.KindBytes || kind == store.KindJSON || kind == store.KindFloat
+	case int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64:
+		return kind == store.KindInt || kind == store.KindUint
+	case float32, float64:
+		return kind == store.KindFloat
+	case bool:
+		return kind == store.KindBool
+	case time.Time:
+		return kind == store.KindTime
+	case net.IP:
+		return kind == store.KindBytes
+	case []byte:
+		return true
+	default:
+		return kind == store.KindJSON || kind == store.KindFloat
+	}
+}
+
+// Get mengambil nilai dari store berdasarkan key yang diberikan.
+// Fungsi ini mengembalikan pointer ke nilai yang ditemukan. Jika tidak ada nilai
+// yang cocok dengan key atau nilai sudah kedaluwarsa, akan mengembalikan nil.
+// Kecuali dengan PolicyLRU, PolicyLFU, atau SlidingTTL, Get hanya memegang read lock; entri kedaluwarsa
+// yang ditemukan dihapus oleh pemeriksaan latar belakang berikutnya.
+// Jika Config.Loader diatur, key yang tidak ditemukan dimuat melalui Loader
+// sehingga Get dapat memblokir selama Loader berjalan.
+//
+// Parameter:
+//   - key (string): Key unik yang digunakan untuk mencari nilai dalam store.
+//
+// Tipe Parameter:
+//   - K (store.Compare): Tipe data yang diharapkan sesuai dengan interface Compare,
+//     seperti integer, float, string, atau tipe apapun yang diizinkan.
+//
+// Mengembalikan:
+//   - *K: Pointer ke nilai yang diambil dari store. Jika nilai tidak ditemukan,
+//     akan mengembalikan nil.
+func Get[K store.Compare](key string) *K {
+	result, err := GetErr[K](key)
+	if err != nil {
+		return nil // Kesalahan sudah dicatat oleh GetErr
+	}
+	return result
+}
+
+// GetErr bekerja seperti Get, tetapi mengembalikan kesalahan yang menjelaskan
+// mengapa nilai tidak dapat diambil, sehingga dapat diperiksa dengan errors.Is:
+// ErrNotFound jika key tidak ada atau sudah kedaluwarsa, dan ErrTypeMismatch
+// jika isi store tidak dapat dikonversi ke tipe K, misalnya karena data rusak.
+// Kesalahan konversi juga dicatat melalui Config.Logger dan dihitung oleh
+// DeserializeErrors, sehingga pemanggil dapat bereaksi, misalnya dengan
+// menghapus key tersebut. Kesalahan dari Config.Loader dikembalikan apa adanya.
+//
+// Parameter:
+//   - key (string): Key unik yang digunakan untuk mencari nilai dalam store.
+//
+// Mengembalikan:
+//   - *K: Pointer ke nilai yang diambil dari store, atau nil jika terjadi kesalahan.
+//   - error: ErrNotFound, ErrTypeMismatch, atau kesalahan dari Config.Loader.
+func GetErr[K store.Compare](key string) (*K, error) {
+	return readThrough[K](app, key)
+}
+
+// GetFrom bekerja seperti Get, tetapi mengambil nilai dari instance c yang
+// dibuat oleh NewCache. Fungsi ini diperlukan karena method tidak dapat
+// memiliki parameter tipe.
+//
+// Parameter:
+//   - c (*App): Instance cache yang dibuat oleh NewCache.
+//   - key (string): Key unik yang digunakan untuk mencari nilai dalam store.
+//
+// Mengembalikan:
+//   - *K: Pointer ke nilai yang diambil dari store, atau nil jika tidak ditemukan.
+func GetFrom[K store.Compare](c *App, key string) *K {
+	result, err := readThrough[K](c, key)
+	if err != nil {
+		return nil
+	}
+	return result
+}
+
+// getValue bekerja seperti GetFrom, tetapi mengembalikan nilai beserta penanda
+// ditemukan. Nilai yang tidak sesuai tipe K dilaporkan sebagai tidak ditemukan.
+func getValue[K store.Compare](app *App, key string) (K, bool) {
+	result, err := readThrough[K](app, key)
+	if err != nil || result == nil {
+		var zero K
+		return zero, false
+	}
+	return *result, true
+}
+
+// GetOr mengambil nilai dengan key yang diberikan, atau mengembalikan def jika
+// key tidak ditemukan, sudah kedaluwarsa, atau tidak sesuai tipe K.
+// def tidak disimpan ke dalam cache.
+//
+// Parameter:
+//   - key (string): Key unik yang digunakan untuk mencari nilai dalam store.
+//   - def (K): Nilai pengganti jika key tidak ditemukan.
+//
+// Mengembalikan:
+//   - K: Nilai yang ditemukan, atau def.
+func GetOr[K store.Compare](key string, def K) K {
+	if value, ok := getValue[K](app, key); ok {
+		return value
+	}
+	return def
+}
+
+// GetWithTTL mengambil nilai beserta sisa masa berlakunya dalam satu kali
+// penguncian, misalnya untuk mengisi header Cache-Control tanpa risiko entri
+// berubah di antara dua pemanggilan. Config.Loader tidak dipanggil.
+//
+// Parameter:
+//   - key (string): Key unik yang digunakan untuk mencari nilai dalam store.
+//
+// Mengembalikan:
+//   - value (K): Nilai yang ditemukan, atau zero value.
+//   - ttl (uint64): Sisa masa berlaku dalam milidetik, atau 0 jika entri tidak pernah kedaluwarsa.
+//   - ok (bool): False jika key tidak ditemukan, sudah kedaluwarsa, atau tidak sesuai tipe K.
+func GetWithTTL[K store.Compare](key string) (value K, ttl uint64, ok bool) {
+	s := app.shardFor(key)
+	// PolicyLRU, PolicyLFU, dan SlidingTTL mengubah entri saat dibaca sehingga membutuhkan write lock
+	if app.writeOnRead() {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+	} else {
+		s.mu.RLock()
+		defer s.mu.RUnlock()
+	}
+	data, found := app.lookup(key)
+	app.recordLookup(found)
+	if !found {
+		return value, 0, false
+	}
+	app.touch(key) // touch dapat menggeser masa berlaku dengan SlidingTTL
+	result, err := decode[K](data)
+	if err != nil {
+		return value, 0, false
+	}
+	if data.MaxAge() > 0 {
+		expires, now := app.deadline(key, data), app.now()
+		// Entri dapat kedaluwarsa tepat setelah diperiksa oleh lookup
+		if expires <= now {
+			return value, 0, false
+		}
+		ttl = expires - now
+	}
+	return *result, ttl, true
+}
+
+// GetString mengambil nilai string dengan key yang diberikan, sama seperti Get[string].
+// Fungsi ini berguna untuk pemanggil yang tidak dapat memakai generic, misalnya reflection.
+//
+// Parameter:
+//   - key (string): Key unik yang digunakan untuk mencari nilai dalam store.
+//
+// Mengembalikan:
+//   - string: Nilai yang ditemukan, atau string kosong jika tidak ditemukan.
+//   - bool: True jika nilai ditemukan dan sesuai tipe.
+func (app *App) GetString(key string) (string, bool) {
+	return getValue[string](app, key)
+}
+
+// GetString menjalankan App.GetString pada instance global yang dibuat oleh New.
+func GetString(key string) (string, bool) {
+	return app.GetString(key)
+}
+
+// GetInt mengambil nilai int dengan key yang diberikan, sama seperti Get[int].
+//
+// Parameter:
+//   - key (string): Key unik yang digunakan untuk mencari nilai dalam store.
+//
+// Mengembalikan:
+//   - int: Nilai yang ditemukan, atau 0 jika tidak ditemukan.
+//   - bool: True jika nilai ditemukan dan sesuai tipe.
+func (app *App) GetInt(key string) (int, bool) {
+	return getValue[int](app, key)
+}
+
+// GetInt menjalankan App.GetInt pada instance global yang dibuat oleh New.
+func GetInt(key string) (int, bool) {
+	return app.GetInt(key)
+}
+
+// GetBool mengambil nilai bool dengan key yang diberikan, sama seperti Get[bool].
+//
+// Parameter:
+//   - key (string): Key unik yang digunakan untuk mencari nilai dalam store.
+//
+// Mengembalikan:
+//   - bool: Nilai yang ditemukan, atau false jika tidak ditemukan.
+//   - bool: True jika nilai ditemukan dan sesuai tipe.
+func (app *App) GetBool(key string) (bool, bool) {
+	return getValue[bool](app, key)
+}
+
+// GetFloat64 mengambil nilai float64 dengan key yang diberikan, sama seperti Get[float64].
+//
+// Parameter:
+//   - key (string): Key unik yang digunakan untuk mencari nilai dalam store.
+//
+// Mengembalikan:
+//   - float64: Nilai yang ditemukan, atau 0 jika tidak ditemukan.
+//   - bool: True jika nilai ditemukan dan sesuai tipe.
+func (app *App) GetFloat64(key string) (float64, bool) {
+	return getValue[float64](app, key)
+}
+
+// GetFloat64 menjalankan App.GetFloat64 pada instance global yang dibuat oleh New.
+func GetFloat64(key string) (float64, bool) {
+	return app.GetFloat64(key)
+}
+
+// GetAny mengambil store mentah dengan key yang diberikan. Tipe nilai asal
+// tidak disimpan di dalam store, sehingga nilai dikembalikan sebagai store.Store
+// yang dapat dikonversi dengan Text, Int, Bool, atau JSON.
+//
+// Parameter:
+//   - key (string): Key unik yang digunakan untuk mencari nilai dalam store.
+//
+// Mengembalikan:
+//   - any: store.Store yang tersimpan, atau nil jika tidak ditemukan.
+func (app *App) GetAny(key string) any {
+	s := app.shardFor(key)
+	if app.writeOnRead() {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+	} else {
+		s.mu.RLock()
+		defer s.mu.RUnlock()
+	}
+	_, ok := app.lookup(key)
+	app.recordLookup(ok)
+	if !ok {
+		return nil
+	}
+	app.touch(key)
+	return s.data[key]
+}
+
+// getErr adalah implementasi GetErr untuk instance app.
+func getErr[K store.Compare](app *App, key string) (*K, error) {
+	s := app.shardFor(key)
+	// PolicyLRU, PolicyLFU, dan SlidingTTL mengubah entri saat dibaca sehingga membutuhkan write lock
+	if app.writeOnRead() {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+	} else {
+		s.mu.RLock()
+		defer s.mu.RUnlock()
+	}
+
+	value, ok := app.lookup(key)
+	app.recordLookup(ok)
+	if !ok {
+		return nil, fmt.Errorf("key %q: %w", key, ErrNotFound)
+	}
+	app.touch(key)
+
+	result, err := decode[K](value)
+	if err != nil {
+		app.decodeErr.Add(1)
+		app.logf("cago: get %q: %v", key, err)
+		return nil, fmt.Errorf("key %q: %w: %w", key, ErrTypeMismatch, err)
+	}
+	return result, nil
+}
+
+// lookup mengambil entri yang belum kedaluwarsa untuk jalur baca. Entri yang
+// sudah kedaluwarsa dianggap tidak ada dan dicatat di app.stale agar dihapus
+// oleh sweep berikutnya, sehingga pembaca cukup memegang read lock dan tidak
+// perlu menunggu write lock hanya untuk menghapus entri.
+// Fungsi ini harus dipanggil ketika lock shard dari key sedang dipegang, minimal dengan read lock.
+//
+// Mengembalikan:
+//   - store.Store: Entri dengan key yang diberikan.
+//   - bool: False jika key tidak ada atau sudah kedaluwarsa.
+func (app *App) lookup(key string) (store.Store, bool) {
+	value, ok := app.shardFor(key).data[key]
+	// Entri tanpa MaxAge tidak perlu membaca jam sistem
+	if !ok || value.MaxAge() == 0 || !app.expiredEntry(key, value, app.now()) {
+		return value, ok
+	}
+	app.smu.Lock()
+	app.stale[key] = struct{}{}
+	app.smu.Unlock()
+	return nil, false
+}
+
+// DeserializeErrors mengembalikan jumlah kegagalan konversi data saat Get
+// sejak New dipanggil.
+//
+// Mengembalikan:
+//   - uint64: Jumlah kegagalan konversi.
+func DeserializeErrors() uint64 {
+	return app.decodeErr.Load()
+}
+
+// logf mencatat pesan melalui Config.Logger jika logger tersebut diatur.
+func (app *App) logf(format string, v ...any) {
+	if app.config.Logger != nil {
+		app.config.Logger.Printf(format, v...)
+	}
+}
+
+// GetBool mengambil nilai bool dari store berdasarkan key yang diberikan.
+// Fungsi ini merupakan jalan pintas untuk Get[bool] yang umum dipakai sebagai feature flag.
+//
+// Parameter:
+//   - key (string): Key unik yang digunakan untuk mencari nilai dalam store.
+//
+// Mengembalikan:
+//   - bool: Nilai yang diambil dari store.
+//   - bool: True jika nilai ditemukan dan tersimpan sebagai bool.
+func GetBool(key string) (bool, bool) {
+	return app.GetBool(key)
 }
 
 // Exist memeriksa apakah nilai dengan key yang diberikan ada dalam store.
 // Fungsi ini mengembalikan true jika key ditemukan, dan false jika tidak.
+// Entri yang sudah kedaluwarsa dianggap tidak ada walaupun belum dihapus
+// oleh pemeriksaan latar belakang.
 //
 // Parameter:
 //   - key (string): Key unik yang digunakan untuk memeriksa keberadaan nilai
@@ -489,13 +1583,19 @@ func Get[K store.Compare](key string) *K {
 //
 // Mengembalikan:
 // - bool: True jika nilai dengan key ditemukan; False jika tidak ditemukan.
-func Exist(key string) bool {
-	app.mu.Lock()
-	defer app.mu.Unlock()
-	_, ok := app.data[key]
+func (app *App) Exist(key string) bool {
+	s := app.shardFor(key)
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	_, ok := app.lookup(key)
 	return ok
 }
 
+// Exist menjalankan App.Exist pada instance global yang dibuat oleh New.
+func Exist(key string) bool {
+	return app.Exist(key)
+}
+
 // Put menggantikan atau membuat nilai baru ke dalam store dengan key yang diberikan.
 // Jika key sudah ada, nilai yang lama akan digantikan dengan nilai baru.
 // Fungsi ini juga dapat menerima parameter opsional untuk menentukan maxAge.
@@ -509,192 +1609,385 @@ func Exist(key string) bool {
 //
 // Mengembalikan:
 // - error: Kesalahan jika terjadi selama proses penggantian atau penyimpanan data.
-func Put(key string, value store.Compare, maxAge ...uint64) error {
-	app.mu.Lock()
-	defer app.mu.Unlock()
+func (app *App) Put(key string, value store.Compare, maxAge ...uint64) error {
+	if err := app.checkKey(key); err != nil {
+		return err
+	}
+	data, err := app.encode(key, value, maxAge)
+	if err != nil {
+		return err
+	}
+
+	defer app.dispatch()
+	s := app.shardFor(key)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	// Tanpa maxAge, masa berlaku nilai lama dipertahankan
 	if len(maxAge) == 0 {
-		old, ok := app.data[key]
-		if ok {
-			maxAge = append(maxAge, old.MaxAge())
+		if old, ok := s.data[key]; ok {
+			data = data.SetMaxAge(old.MaxAge())
 		}
 	}
-	switch v := any(value).(type) {
-	case string:
-		data := store.NewStore([]byte(v), maxAge...)
-		if app.db != nil {
-			app.db.InsertOrUpdate(key, data)
-		}
-		app.data[key] = data
-		if app.db != nil {
-			if err := app.db.InsertOrUpdate(key, data); err != nil {
-				return err
-			}
-		}
-	case int:
-		data := store.NewStore(lib.Int64ToByte(int64(v)), maxAge...)
-		if app.db != nil {
-			app.db.InsertOrUpdate(key, data)
-		}
-		app.data[key] = data
-		if app.db != nil {
-			if err := app.db.InsertOrUpdate(key, data); err != nil {
-				return err
-			}
-		}
-	case int8:
-		data := store.NewStore(lib.Int8ToByte(int8(v)), maxAge...)
-		if app.db != nil {
-			app.db.InsertOrUpdate(key, data)
-		}
-		app.data[key] = data
-		if app.db != nil {
-			if err := app.db.InsertOrUpdate(key, data); err != nil {
-				return err
-			}
-		}
-	case int16:
-		data := store.NewStore(lib.Int16ToByte(int16(v)), maxAge...)
-		if app.db != nil {
-			app.db.InsertOrUpdate(key, data)
-		}
-		app.data[key] = data
-		if app.db != nil {
-			if err := app.db.InsertOrUpdate(key, data); err != nil {
-				return err
-			}
-		}
-	case int32:
-		data := store.NewStore(lib.Int32ToByte(int32(v)), maxAge...)
-		if app.db != nil {
-			app.db.InsertOrUpdate(key, data)
-		}
-		app.data[key] = data
-		if app.db != nil {
-			if err := app.db.InsertOrUpdate(key, data); err != nil {
-				return err
-			}
-		}
-	case int64:
-		data := store.NewStore(lib.Int64ToByte(int64(v)), maxAge...)
-		if app.db != nil {
-			app.db.InsertOrUpdate(key, data)
-		}
-		app.data[key] = data
-		if app.db != nil {
-			if err := app.db.InsertOrUpdate(key, data); err != nil {
-				return err
-			}
-		}
-	case uint:
-		data := store.NewStore(lib.Uint64ToByte(uint64(v)), maxAge...)
-		if app.db != nil {
-			app.db.InsertOrUpdate(key, data)
-		}
-		app.data[key] = data
-		if app.db != nil {
-			if err := app.db.InsertOrUpdate(key, data); err != nil {
-				return err
-			}
-		}
-	case uint8:
-		data := store.NewStore(lib.Uint8ToByte(uint8(v)), maxAge...)
-		if app.db != nil {
-			app.db.InsertOrUpdate(key, data)
-		}
-		app.data[key] = data
-		if app.db != nil {
-			if err := app.db.InsertOrUpdate(key, data); err != nil {
-				return err
-			}
-		}
-	case uint16:
-		data := store.NewStore(lib.Uint16ToByte(uint16(v)), maxAge...)
-		if app.db != nil {
-			app.db.InsertOrUpdate(key, data)
-		}
-		app.data[key] = data
-		if app.db != nil {
-			if err := app.db.InsertOrUpdate(key, data); err != nil {
-				return err
-			}
-		}
-	case uint32:
-		data := store.NewStore(lib.Uint32ToByte(uint32(v)), maxAge...)
-		if app.db != nil {
-			app.db.InsertOrUpdate(key, data)
+	return app.persist(key, data)
+}
+
+// Put menjalankan App.Put pada instance global yang dibuat oleh New.
+func Put(key string, value store.Compare, maxAge ...uint64) error {
+	return app.Put(key, value, maxAge...)
+}
+
+// Remove menghapus nilai yang terkait dengan key yang diberikan dari store.
+// Fungsi ini juga menghapus data dari database jika ada.
+//
+// Parameter:
+//   - key (string): Key unik yang digunakan untuk menghapus nilai dalam store.
+//
+// Mengembalikan:
+// - bool: True jika key berhasil dihapus; False jika key tidak ditemukan.
+func (app *App) Remove(key string) bool {
+	defer app.dispatch()
+	s := app.shardFor(key)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	ok := app.deleteEntry(key, EvictRemoved)
+	if app.db != nil {
+		if err := app.db.RemoveByKey(key); err != nil {
+			app.logf("cago: remove %q: %v", key, err)
 		}
-		app.data[key] = data
-		if app.db != nil {
-			if err := app.db.InsertOrUpdate(key, data); err != nil {
-				return err
+	}
+	return ok
+}
+
+// Remove menjalankan App.Remove pada instance global yang dibuat oleh New.
+func Remove(key string) bool {
+	return app.Remove(key)
+}
+
+// RemovePrefix menghapus semua nilai dengan key yang diawali prefix dari store
+// dan database. Prefix kosong cocok dengan semua key, sama seperti Clear.
+//
+// Parameter:
+//   - prefix (string): Awalan key yang akan dihapus, misalnya "user:123:".
+//
+// Mengembalikan:
+//   - int: Jumlah key yang dihapus.
+func (app *App) RemovePrefix(prefix string) int {
+	defer app.dispatch()
+	app.lockAll()
+	defer app.unlockAll()
+	count := 0
+	for _, s := range app.shards {
+		for key := range s.data {
+			if !strings.HasPrefix(key, prefix) {
+				continue
 			}
-		}
-	case uint64:
-		data := store.NewStore(lib.Uint64ToByte(uint64(v)), maxAge...)
-		if app.db != nil {
-			app.db.InsertOrUpdate(key, data)
-		}
-		app.data[key] = data
-		if app.db != nil {
-			if err := app.db.InsertOrUpdate(key, data); err != nil {
-				return err
+			app.deleteEntry(key, EvictRemoved)
+			count++
+			if app.db != nil {
+				if err := app.db.RemoveByKey(key); err != nil {
+					app.logf("cago: remove %q: %v", key, err)
+				}
 			}
 		}
-	case float32, float64:
-		by, err := json.Marshal(value)
-		if err != nil {
-			return err
-		}
-		data := store.NewStore(by, maxAge...)
-		if app.db != nil {
-			app.db.InsertOrUpdate(key, data)
+	}
+	return count
+}
+
+// RemovePrefix menjalankan App.RemovePrefix pada instance global yang dibuat oleh New.
+func RemovePrefix(prefix string) int {
+	return app.RemovePrefix(prefix)
+}
+
+// GetAndRemove mengambil nilai dengan key yang diberikan lalu menghapusnya dalam satu operasi atomik.
+// Fungsi ini berguna untuk pola antrean kerja di mana sebuah nilai hanya boleh diambil sekali.
+// Jika isi store tidak sesuai dengan tipe K, entri tidak akan dihapus.
+//
+// Parameter:
+//   - key (string): Key unik yang digunakan untuk mencari nilai dalam store.
+//
+// Mengembalikan:
+//   - K: Nilai yang diambil dari store, atau zero value jika tidak ditemukan.
+//   - bool: True jika nilai ditemukan, belum kedaluwarsa, sesuai tipe, dan telah dihapus.
+func GetAndRemove[K store.Compare](key string) (K, bool) {
+	defer app.dispatch()
+	s := app.shardFor(key)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var zero K
+	value, ok := s.data[key]
+	ok = ok && !app.expiredEntry(key, value, app.now())
+	app.recordLookup(ok)
+	if !ok {
+		return zero, false
+	}
+	result, err := decode[K](value)
+	if err != nil {
+		return zero, false
+	}
+	app.deleteEntry(key, EvictRemoved)
+	if app.db != nil {
+		if err := app.db.RemoveByKey(key); err != nil {
+			app.logf("cago: get and remove %q: %v", key, err)
 		}
-		app.data[key] = data
-		if app.db != nil {
-			if err := app.db.InsertOrUpdate(key, data); err != nil {
-				return err
-			}
+	}
+	return *result, true
+}
+
+// GetAndTouch mengambil nilai dengan key yang diberikan sekaligus memperpanjang
+// masa berlakunya menjadi maxAge milidetik sejak saat ini, dalam satu operasi atomik.
+// Fungsi ini cocok untuk membaca sesi yang masa berlakunya bergeser setiap diakses.
+// Jika isi store tidak sesuai dengan tipe K, masa berlaku tidak diubah.
+// Store yang diperbarui disimpan seperti Put, sehingga watcher menerima OpPut.
+//
+// Parameter:
+//   - key (string): Key unik yang digunakan untuk mencari nilai dalam store.
+//   - maxAge (uint64): Masa berlaku baru dalam milidetik, dihitung dari saat ini.
+//
+// Mengembalikan:
+//   - K: Nilai yang diambil dari store, atau zero value jika tidak ditemukan.
+//   - bool: True jika nilai ditemukan, belum kedaluwarsa, dan sesuai tipe.
+func GetAndTouch[K store.Compare](key string, maxAge uint64) (K, bool) {
+	defer app.dispatch()
+	s := app.shardFor(key)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var zero K
+	now := app.now()
+	value, ok := s.data[key]
+	ok = ok && !app.expiredEntry(key, value, now)
+	app.recordLookup(ok)
+	if !ok {
+		return zero, false
+	}
+	result, err := decode[K](value)
+	if err != nil {
+		return zero, false
+	}
+
+	app.touch(key)
+	touched := value.SetMaxAge(now - value.CreateAt() + maxAge).SetUpdateAt(now)
+	if app.config.SlidingTTL {
+		// maxAge menjadi masa berlaku baru yang digeser oleh pembacaan berikutnya
+		touched = value.SetMaxAge(maxAge).SetUpdateAt(now)
+	}
+	if err := app.persist(key, touched); err != nil {
+		app.logf("cago: get and touch %q: %v", key, err)
+		return *result, true
+	}
+	if app.config.SlidingTTL {
+		s.access[key] = now
+	}
+	return *result, true
+}
+
+// SetIfAbsent menyimpan nilai hanya jika key belum ada atau sudah kedaluwarsa,
+// seperti pola SETNX. Berbeda dengan Set, fungsi ini mengembalikan bool
+// sehingga pemanggil tidak perlu membandingkan kesalahan dengan ErrKeyExists.
+//
+// Parameter:
+//   - key (string): Key unik yang digunakan untuk mengidentifikasi nilai dalam store.
+//   - value (T): Nilai yang akan disimpan.
+//   - maxAge (uint64): Waktu maksimal dalam milidetik selama nilai akan disimpan.
+//     Nilai 0 berarti tidak pernah kedaluwarsa.
+//
+// Mengembalikan:
+//   - bool: True jika nilai disimpan, false jika key masih berlaku atau nilai gagal disimpan.
+func SetIfAbsent[T store.Compare](key string, value T, maxAge uint64) bool {
+	if err := app.checkKey(key); err != nil {
+		app.logf("cago: set if absent: %v", err)
+		return false
+	}
+	data, err := app.encode(key, value, []uint64{maxAge})
+	if err != nil {
+		app.logf("cago: set if absent %q: %v", key, err)
+		return false
+	}
+
+	defer app.dispatch()
+	s := app.shardFor(key)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if old, ok := s.data[key]; ok && !app.expiredEntry(key, old, app.now()) {
+		return false
+	}
+	if err := app.persist(key, data); err != nil {
+		app.logf("cago: set if absent %q: %v", key, err)
+		return false
+	}
+	return true
+}
+
+// Swap mengganti nilai dengan key yang diberikan dalam satu operasi atomik dan
+// mengembalikan nilai sebelumnya. Fungsi ini berguna misalnya untuk merotasi
+// token, di mana token lama masih dibutuhkan untuk dicabut.
+// Nilai baru selalu disimpan, kecuali jika key ditolak, value gagal dikonversi,
+// atau melampaui MaxValueSize maupun MAX_MEM. Dalam kasus tersebut nilai lama
+// tidak diubah dan Swap mengembalikan zero value dan false.
+//
+// Parameter:
+//   - key (string): Key unik yang digunakan untuk mengidentifikasi nilai dalam store.
+//   - value (T): Nilai baru yang akan disimpan.
+//   - maxAge (uint64): Waktu maksimal dalam milidetik untuk nilai baru.
+//     Nilai 0 berarti tidak pernah kedaluwarsa.
+//
+// Mengembalikan:
+//   - T: Nilai sebelumnya, atau zero value jika tidak ada.
+//   - bool: True jika nilai baru disimpan dan nilai sebelumnya ada, belum
+//     kedaluwarsa, dan sesuai tipe T.
+func Swap[T store.Compare](key string, value T, maxAge uint64) (T, bool) {
+	var zero T
+	if err := app.checkKey(key); err != nil {
+		app.logf("cago: swap: %v", err)
+		return zero, false
+	}
+	data, err := app.encode(key, value, []uint64{maxAge})
+	if err != nil {
+		app.logf("cago: swap %q: %v", key, err)
+		return zero, false
+	}
+
+	defer app.dispatch()
+	s := app.shardFor(key)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	previous, ok := zero, false
+	if old, found := s.data[key]; found && !app.expiredEntry(key, old, app.now()) {
+		if result, err := decode[T](old); err == nil {
+			previous, ok = *result, true
 		}
-	case any:
-		by, err := json.Marshal(value)
+	}
+	if err := app.persist(key, data); err != nil {
+		app.logf("cago: swap %q: %v", key, err)
+		return zero, false
+	}
+	return previous, ok
+}
+
+// Update mengubah nilai dengan key yang diberikan melalui fn dalam satu operasi
+// atomik, misalnya untuk menambahkan elemen ke slice atau map tanpa lock tambahan.
+// fn menerima nilai saat ini, atau zero value jika key tidak ada atau sudah
+// kedaluwarsa, lalu mengembalikan nilai baru beserta maxAge-nya. fn dipanggil
+// ketika write lock dipegang, sehingga fn tidak boleh memanggil fungsi cago lainnya.
+//
+// Parameter:
+//   - key (string): Key unik yang digunakan untuk mengidentifikasi nilai dalam store.
+//   - fn (func(old T, existed bool) (T, uint64)): Fungsi yang menghitung nilai baru
+//     dan maxAge dalam milidetik. Nilai maxAge 0 berarti tidak pernah kedaluwarsa.
+//
+// Mengembalikan:
+//   - error: Kesalahan jika nilai saat ini tidak sesuai tipe T (fn tidak dipanggil),
+//     nilai baru gagal dikonversi, atau penyimpanan gagal.
+func Update[T store.Compare](key string, fn func(old T, existed bool) (T, uint64)) error {
+	if err := app.checkKey(key); err != nil {
+		return err
+	}
+	defer app.dispatch()
+	s := app.shardFor(key)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var current T
+	old, existed := s.data[key]
+	if existed && app.expiredEntry(key, old, app.now()) {
+		existed = false
+	}
+	if existed {
+		result, err := decode[T](old)
 		if err != nil {
-			return err
+			return fmt.Errorf("key %q: %w: %w", key, ErrTypeMismatch, err)
 		}
-		data := store.NewStore(by, maxAge...)
-		if app.db != nil {
-			app.db.InsertOrUpdate(key, data)
-		}
-		app.data[key] = data
-		if app.db != nil {
-			if err := app.db.InsertOrUpdate(key, data); err != nil {
-				return err
-			}
-		}
-	default:
-		return fmt.Errorf("unsupported type: %T", value)
+		current = *result
 	}
-	return nil
+
+	value, maxAge := fn(current, existed)
+	data, err := app.encode(key, value, []uint64{maxAge})
+	if err != nil {
+		return err
+	}
+	return app.persist(key, data)
 }
 
-// Remove menghapus nilai yang terkait dengan key yang diberikan dari store.
-// Fungsi ini juga menghapus data dari database jika ada.
+// GetMeta mengembalikan waktu pembuatan, pembaruan terakhir, dan kedaluwarsa
+// dari entri dengan key yang diberikan. Jika entri belum pernah diperbarui,
+// waktu pembaruan sama dengan waktu pembuatan. Entri yang tidak pernah
+// kedaluwarsa mengembalikan expires bernilai zero time. Semua waktu
+// dinyatakan dalam zona waktu Config.Timezone.
 //
 // Parameter:
-//   - key (string): Key unik yang digunakan untuk menghapus nilai dalam store.
+//   - key (string): Key unik yang digunakan untuk mencari nilai dalam store.
 //
 // Mengembalikan:
-// - bool: True jika key berhasil dihapus; False jika key tidak ditemukan.
-func Remove(key string) bool {
-	app.mu.Lock()
-	defer app.mu.Unlock()
-	_, ok := app.data[key]
-	delete(app.data, key)
-	if app.db != nil {
-		if err := app.db.RemoveByKey(key); err != nil {
-			fmt.Println(err.Error())
+//   - created (time.Time): Waktu pembuatan entri.
+//   - updated (time.Time): Waktu pembaruan terakhir entri.
+//   - expires (time.Time): Waktu entri kedaluwarsa.
+//   - ok (bool): False jika key tidak ditemukan atau sudah kedaluwarsa.
+func (app *App) GetMeta(key string) (created, updated, expires time.Time, ok bool) {
+	s := app.shardFor(key)
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	value, found := s.data[key]
+	if !found || app.expiredEntry(key, value, app.now()) {
+		return
+	}
+
+	created, updated, expires = app.times(key, value)
+	return created, updated, expires, true
+}
+
+// GetMeta menjalankan App.GetMeta pada instance global yang dibuat oleh New.
+func GetMeta(key string) (created, updated, expires time.Time, ok bool) {
+	return app.GetMeta(key)
+}
+
+// ExpiringSoon mengembalikan key yang akan kedaluwarsa dalam within milidetik
+// dari sekarang, diurutkan dari yang paling cepat kedaluwarsa. Key yang sudah
+// kedaluwarsa dan key tanpa MaxAge tidak disertakan. Fungsi ini berguna untuk
+// memperbarui nilai sebelum kedaluwarsa.
+//
+// Parameter:
+//   - within (uint64): Rentang waktu dalam milidetik.
+//
+// Mengembalikan:
+//   - []string: Key yang akan kedaluwarsa dalam rentang waktu tersebut.
+func (app *App) ExpiringSoon(within uint64) []string {
+	now := app.now()
+	type expiring struct {
+		key string
+		at  uint64
+	}
+	var found []expiring
+	for _, s := range app.shards {
+		s.mu.RLock()
+		for key, value := range s.data {
+			if value.MaxAge() == 0 || app.expiredEntry(key, value, now) {
+				continue
+			}
+			if at := app.deadline(key, value); at-now <= within {
+				found = append(found, expiring{key: key, at: at})
+			}
 		}
+		s.mu.RUnlock()
 	}
-	return ok
+
+	sort.Slice(found, func(i, j int) bool {
+		if found[i].at != found[j].at {
+			return found[i].at < found[j].at
+		}
+		return found[i].key < found[j].key
+	})
+	keys := make([]string, len(found))
+	for i, e := range found {
+		keys[i] = e.key
+	}
+	return keys
+}
+
+// ExpiringSoon menjalankan App.ExpiringSoon pada instance global yang dibuat oleh New.
+func ExpiringSoon(within uint64) []string {
+	return app.ExpiringSoon(within)
 }
 
 // Clear menghapus semua nilai yang tersimpan dalam store dan database.
@@ -703,12 +1996,26 @@ func Remove(key string) bool {
 //
 // Mengembalikan:
 // - error: Kesalahan jika terjadi selama proses penghapusan data dari database.
-func Clear() error {
-	app.mu.Lock()
-	defer app.mu.Unlock()
-	app.data = make(map[string]store.Store)
+func (app *App) Clear() error {
+	defer app.dispatch()
+	app.lockAll()
+	defer app.unlockAll()
+	// Subscriber menerima satu OpClear, bukan OpRemove untuk setiap key
+	app.clearing = true
+	for _, s := range app.shards {
+		for key := range s.data {
+			app.deleteEntry(key, EvictRemoved)
+		}
+	}
+	app.clearing = false
+	app.publish(Event{Op: OpClear})
 	if app.db != nil {
 		return app.db.RemoveAll()
 	}
 	return nil
 }
+
+// Clear menjalankan App.Clear pada instance global yang dibuat oleh New.
+func Clear() error {
+	return app.Clear()
+}
@@ -7,12 +7,24 @@ package cago_test
 
 import (
 	"bytes"
+	"errors"
 	"fmt"
+	"io"
+	"log"
+	"math"
+	"net"
+	"net/url"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
 	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 
 	"github.com/jasakode/cago"
+	"github.com/jasakode/cago/store"
 )
 
 func BenchmarkCompareString(b *testing.B) {
@@ -35,6 +47,68 @@ func BenchmarkCompareByte(b *testing.B) {
 	}
 }
 
+// BenchmarkReadHeavy mengukur Get dan Exist paralel dengan satu penulisan
+// setiap 100 operasi, sementara pemeriksaan kedaluwarsa berjalan setiap milidetik.
+func BenchmarkReadHeavy(b *testing.B) {
+	if err := cago.New(cago.Config{TimeoutCheck: 10}); err != nil {
+		b.Fatal(err)
+	}
+	keys := make([]string, 1000)
+	for i := range keys {
+		keys[i] = fmt.Sprint("key-", i)
+		cago.Put(keys[i], i)
+	}
+	b.ResetTimer()
+	b.RunParallel(func(pb *testing.PB) {
+		i := 0
+		for pb.Next() {
+			key := keys[i%len(keys)]
+			switch {
+			case i%100 == 0:
+				cago.Put(key, i)
+			case i%2 == 0:
+				cago.Exist(key)
+			default:
+				cago.Get[int](key)
+			}
+			i++
+		}
+	})
+}
+
+// BenchmarkMixed mengukur Get dan Put paralel dalam jumlah yang sama
+// pada 1000 key, dengan satu lock untuk seluruh cache dan dengan 16 shard,
+// untuk membandingkan pertentangan lock.
+func BenchmarkMixed(b *testing.B) {
+	for _, shards := range []int{1, 16} {
+		b.Run(fmt.Sprint("shards-", shards), func(b *testing.B) {
+			c, err := cago.NewCache(cago.Config{TimeoutCheck: 10, Shards: shards})
+			if err != nil {
+				b.Fatal(err)
+			}
+			defer c.Close()
+			keys := make([]string, 1000)
+			for i := range keys {
+				keys[i] = fmt.Sprint("key-", i)
+				c.Put(keys[i], i)
+			}
+			b.ResetTimer()
+			b.RunParallel(func(pb *testing.PB) {
+				i := 0
+				for pb.Next() {
+					key := keys[i%len(keys)]
+					if i%2 == 0 {
+						c.Put(key, i)
+					} else {
+						cago.GetFrom[int](c, key)
+					}
+					i++
+				}
+			})
+		})
+	}
+}
+
 type Person struct {
 	Name string `json:"name"`
 	Age  int64  `json:"age"`
@@ -84,3 +158,1131 @@ func TestApp(t *testing.T) {
 	// fmt.Println(cago.Size())
 	// fmt.Println(cago.Get[string]("hello"), cago.Get[string]("jhon"))
 }
+
+func TestGetAndRemove(t *testing.T) {
+	if err := cago.New(); err != nil {
+		t.Fatal(err)
+	}
+	cago.Set("job", "payload")
+	cago.Set("count", 10)
+
+	// Nilai diambil lalu dihapus
+	v, ok := cago.GetAndRemove[string]("job")
+	if !ok || v != "payload" {
+		t.Errorf("expected %q, got %q (ok=%v)", "payload", v, ok)
+	}
+	if cago.Exist("job") {
+		t.Error("expected job to be removed")
+	}
+
+	// Key yang tidak ada
+	if _, ok := cago.GetAndRemove[string]("job"); ok {
+		t.Error("expected missing key to report false")
+	}
+
+	// Tipe yang tidak sesuai tidak menghapus entri
+	if _, ok := cago.GetAndRemove[Person]("count"); ok {
+		t.Error("expected type mismatch to report false")
+	}
+	if !cago.Exist("count") {
+		t.Error("expected count to survive a type mismatch")
+	}
+}
+
+func TestBool(t *testing.T) {
+	if err := cago.New(); err != nil {
+		t.Fatal(err)
+	}
+	cago.Set("flag", true)
+	cago.Set("off", false)
+	cago.Set("name", "Jhon Doe")
+
+	if rs := cago.Get[bool]("flag"); rs == nil || !*rs {
+		t.Errorf("expected Get[bool] to return true, got %v", rs)
+	}
+	if v, ok := cago.GetBool("flag"); !ok || !v {
+		t.Errorf("expected GetBool to return true, got %v (ok=%v)", v, ok)
+	}
+	if v, ok := cago.GetBool("off"); !ok || v {
+		t.Errorf("expected GetBool to return false, got %v (ok=%v)", v, ok)
+	}
+
+	// Nilai yang bukan bool dilaporkan sebagai ketidaksesuaian tipe
+	if v, ok := cago.GetBool("name"); ok {
+		t.Errorf("expected mismatch for non-bool value, got %v", v)
+	}
+}
+
+func TestSmallIntegers(t *testing.T) {
+	if err := cago.New(); err != nil {
+		t.Fatal(err)
+	}
+	cago.Set("port", uint16(8080))
+	cago.Set("retries", int8(3))
+	if rs := cago.Get[uint16]("port"); rs == nil || *rs != 8080 {
+		t.Errorf("expected 8080, got %v", rs)
+	}
+	if rs := cago.Get[int8]("retries"); rs == nil || *rs != 3 {
+		t.Errorf("expected 3, got %v", rs)
+	}
+	// Nilai sempit juga dapat dibaca sebagai tipe yang lebih lebar
+	if rs := cago.Get[int]("port"); rs == nil || *rs != 8080 {
+		t.Errorf("expected 8080 as int, got %v", rs)
+	}
+}
+
+// roundTrip menyimpan setiap nilai dengan Set lalu membacanya kembali dengan Get[T].
+func roundTrip[T int | int8 | int16 | int32 | int64 | uint | uint8 | uint16 | uint32 | uint64](t *testing.T, values ...T) {
+	t.Helper()
+	for _, v := range values {
+		key := fmt.Sprintf("%T:%d", v, v)
+		if err := cago.Set(key, v); err != nil {
+			t.Fatal(err)
+		}
+		if rs := cago.Get[T](key); rs == nil || *rs != v {
+			t.Errorf("%T: expected %d, got %v", v, v, rs)
+		}
+		if n := cago.MGet(key)[key].Length(); n != 8 {
+			t.Errorf("%T: expected 8-byte payload, got %d", v, n)
+		}
+	}
+}
+
+func TestIntegerRoundTrip(t *testing.T) {
+	if err := cago.New(); err != nil {
+		t.Fatal(err)
+	}
+	roundTrip[int8](t, math.MinInt8, -5, 0, math.MaxInt8)
+	roundTrip[int16](t, math.MinInt16, -5, 0, math.MaxInt16)
+	roundTrip[int32](t, math.MinInt32, -5, 0, math.MaxInt32)
+	roundTrip[int64](t, math.MinInt64, -5, 0, math.MaxInt64)
+	roundTrip[int](t, math.MinInt, -5, 0, math.MaxInt)
+	roundTrip[uint8](t, 0, math.MaxUint8)
+	roundTrip[uint16](t, 0, math.MaxUint16)
+	roundTrip[uint32](t, 0, math.MaxUint32)
+	roundTrip[uint64](t, 0, math.MaxUint64)
+	roundTrip[uint](t, 0, math.MaxUint)
+
+	// Nilai yang tidak muat di tipe yang lebih sempit tidak dipotong diam-diam
+	cago.Set("wide", int64(40000))
+	if rs := cago.Get[int16]("wide"); rs != nil {
+		t.Errorf("expected nil for value out of int16 range, got %d", *rs)
+	}
+	// Lebar yang seragam membuat Increment bekerja untuk semua tipe integer
+	cago.Set("small", int16(-5))
+	if n, err := cago.Increment("small", 1); err != nil || n != -4 {
+		t.Errorf("expected -4, got %d (%v)", n, err)
+	}
+}
+
+func TestTypeTag(t *testing.T) {
+	if err := cago.New(); err != nil {
+		t.Fatal(err)
+	}
+	cago.Set("float", 3.5)
+	cago.Set("string", "hello")
+	cago.Set("int", -7)
+	cago.Set("uint", uint64(math.MaxUint64))
+	cago.Set("bool", true)
+	cago.Set("time", time.Unix(1_700_000_000, 0))
+	cago.Set("person", Person{Name: "Alice"})
+
+	kinds := map[string]store.StoreKind{
+		"float": store.KindFloat, "string": store.KindString, "int": store.KindInt,
+		"uint": store.KindUint, "bool": store.KindBool, "time": store.KindTime, "person": store.KindJSON,
+	}
+	for key, kind := range kinds {
+		if got := cago.MGet(key)[key].Kind(); got != kind {
+			t.Errorf("%s: expected kind %v, got %v", key, kind, got)
+		}
+	}
+
+	// Setiap pembacaan dengan tipe yang tidak sesuai ditolak
+	mismatch := func(name string, err error) {
+		t.Helper()
+		if !errors.Is(err, cago.ErrTypeMismatch) {
+			t.Errorf("%s: expected ErrTypeMismatch, got %v", name, err)
+		}
+	}
+	_, err := cago.GetErr[int]("float")
+	mismatch("float as int", err)
+	_, err = cago.GetErr[int]("string")
+	mismatch("string as int", err)
+	_, err = cago.GetErr[string]("int")
+	mismatch("int as string", err)
+	_, err = cago.GetErr[float64]("int")
+	mismatch("int as float64", err)
+	_, err = cago.GetErr[bool]("time")
+	mismatch("time as bool", err)
+	_, err = cago.GetErr[time.Time]("bool")
+	mismatch("bool as time.Time", err)
+	_, err = cago.GetErr[Person]("string")
+	mismatch("string as Person", err)
+	_, err = cago.GetErr[int64]("uint")
+	mismatch("MaxUint64 as int64", err)
+	_, err = cago.GetErr[uint]("int")
+	mismatch("negative int as uint", err)
+
+	// Integer tetap dapat dibaca lintas tanda selama nilainya muat, dan
+	// teks JSON serta byte mentah tetap dapat dibaca
+	cago.Set("small", uint8(9))
+	if rs := cago.Get[int]("small"); rs == nil || *rs != 9 {
+		t.Errorf("expected 9, got %v", rs)
+	}
+	if rs := cago.Get[string]("person"); rs == nil || !strings.Contains(*rs, "Alice") {
+		t.Errorf("expected JSON text, got %v", rs)
+	}
+	if rs := cago.Get[[]byte]("int"); rs == nil || len(*rs) != 8 {
+		t.Errorf("expected raw bytes, got %v", rs)
+	}
+}
+
+func TestURLAndIP(t *testing.T) {
+	if err := cago.New(); err != nil {
+		t.Fatal(err)
+	}
+	u, err := url.Parse("https://example.com/search?q=cago&lang=id#hasil")
+	if err != nil {
+		t.Fatal(err)
+	}
+	ip := net.ParseIP("2001:db8::68")
+	if err := cago.Set("url", u); err != nil {
+		t.Fatal(err)
+	}
+	if err := cago.Set("ip", ip); err != nil {
+		t.Fatal(err)
+	}
+
+	if rs := cago.Get[*url.URL]("url"); rs == nil || (*rs).String() != u.String() {
+		t.Errorf("expected %s, got %v", u, rs)
+	}
+	if rs := cago.Get[net.IP]("ip"); rs == nil || !rs.Equal(ip) {
+		t.Errorf("expected %s, got %v", ip, rs)
+	}
+}
+
+func TestBeforeNew(t *testing.T) {
+	// Pengujian dijalankan di proses baru karena pengujian lain sudah memanggil New
+	if os.Getenv("CAGO_BEFORE_NEW") != "1" {
+		cmd := exec.Command(os.Args[0], "-test.run=^TestBeforeNew$")
+		cmd.Env = append(os.Environ(), "CAGO_BEFORE_NEW=1")
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("child process failed: %v\n%s", err, out)
+		}
+		return
+	}
+
+	if rs := cago.Get[string]("greeting"); rs != nil {
+		t.Errorf("expected nil before New, got %v", *rs)
+	}
+	if _, err := cago.GetErr[string]("greeting"); !errors.Is(err, cago.ErrNotFound) {
+		t.Errorf("expected ErrNotFound before New, got %v", err)
+	}
+	if err := cago.Set("greeting", "hello"); err != nil {
+		t.Fatal(err)
+	}
+	if rs := cago.Get[string]("greeting"); rs == nil || *rs != "hello" {
+		t.Errorf("expected default instance to store values, got %v", rs)
+	}
+}
+
+func TestGetErr(t *testing.T) {
+	var buf bytes.Buffer
+	if err := cago.New(cago.Config{Logger: log.New(&buf, "", 0)}); err != nil {
+		t.Fatal(err)
+	}
+	cago.Set("person", "not a json object")
+
+	// Menangkap stdout untuk memastikan tidak ada yang dicetak
+	stdout := os.Stdout
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	os.Stdout = w
+	rs := cago.Get[Person]("person")
+	_, getErr := cago.GetErr[Person]("person")
+	w.Close()
+	os.Stdout = stdout
+	printed, _ := io.ReadAll(r)
+
+	if rs != nil {
+		t.Errorf("expected nil, got %v", rs)
+	}
+	if getErr == nil {
+		t.Error("expected GetErr to return an error")
+	}
+	if n := cago.DeserializeErrors(); n != 2 {
+		t.Errorf("expected 2 deserialize errors, got %d", n)
+	}
+	if len(printed) != 0 {
+		t.Errorf("expected nothing printed to stdout, got %q", printed)
+	}
+	if !strings.Contains(buf.String(), `"person"`) {
+		t.Errorf("expected error to be logged, got %q", buf.String())
+	}
+}
+
+func TestMaxEntries(t *testing.T) {
+	var now atomic.Int64
+	now.Store(1_700_000_000_000)
+	if err := cago.New(cago.Config{MaxEntries: 3, Clock: now.Load}); err != nil {
+		t.Fatal(err)
+	}
+	cago.Set("a", 1)
+	cago.Set("b", 2)
+	cago.Set("c", 3)
+	now.Add(2)
+	// Put membuat ulang "a" sehingga "a" menjadi entri terbaru
+	cago.Put("a", 10)
+	now.Add(2)
+	cago.Set("d", 4)
+
+	// Entri paling lama ("b") dihapus untuk memberi ruang bagi "d"
+	if cago.Exist("b") {
+		t.Error("expected b to be evicted")
+	}
+	for _, key := range []string{"a", "c", "d"} {
+		if !cago.Exist(key) {
+			t.Errorf("expected %s to be kept", key)
+		}
+	}
+}
+
+func TestGetAndTouch(t *testing.T) {
+	var now atomic.Int64
+	now.Store(1_700_000_000_000)
+	if err := cago.New(cago.Config{TimeoutCheck: 60000, Clock: now.Load}); err != nil {
+		t.Fatal(err)
+	}
+	cago.Set("session", "token", 100)
+	now.Add(50)
+
+	v, ok := cago.GetAndTouch[string]("session", 500)
+	if !ok || v != "token" {
+		t.Fatalf("expected %q, got %q (ok=%v)", "token", v, ok)
+	}
+
+	// Key tetap ada setelah melewati masa berlaku awal
+	now.Add(150)
+	cago.RemoveExpired()
+	if rs := cago.Get[string]("session"); rs == nil || *rs != "token" {
+		t.Errorf("expected session to survive its original max age, got %v", rs)
+	}
+
+	if _, ok := cago.GetAndTouch[string]("missing", 500); ok {
+		t.Error("expected missing key to report false")
+	}
+}
+
+func TestGetAndTouchPersists(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "touch.db")
+	if err := cago.New(cago.Config{Path: path, WriteThrough: true}); err != nil {
+		t.Fatal(err)
+	}
+	cago.Put("session", "token", 1000)
+	events, unwatch, err := cago.WatchKey("session")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer unwatch()
+
+	if _, ok := cago.GetAndTouch[string]("session", 3600000); !ok {
+		t.Fatal("expected session to be found")
+	}
+	select {
+	case e := <-events:
+		if e.Op != cago.OpPut || e.Value.MaxAge() < 3600000 {
+			t.Errorf("expected OpPut with the new max age, got %v %d", e.Op, e.Value.MaxAge())
+		}
+	case <-time.After(time.Second):
+		t.Error("expected the touch to notify watchers")
+	}
+
+	// Masa berlaku baru juga ditulis ke database
+	if err := cago.New(cago.Config{Path: path}); err != nil {
+		t.Fatal(err)
+	}
+	defer cago.Close()
+	if rs := cago.MGet("session"); rs["session"] == nil || rs["session"].MaxAge() < 3600000 {
+		t.Errorf("expected the touched max age after reload, got %v", rs["session"])
+	}
+}
+
+func TestMaxAgeMilliseconds(t *testing.T) {
+	var now atomic.Int64
+	now.Store(1_700_000_000_000)
+	if err := cago.New(cago.Config{TimeoutCheck: 60000, Clock: now.Load}); err != nil {
+		t.Fatal(err)
+	}
+	cago.Set("short", "value", 50)
+
+	now.Add(49)
+	if !cago.Exist("short") {
+		t.Fatal("expected key to exist before 50ms")
+	}
+	// maxAge 50 berarti 50 milidetik, bukan 50 detik
+	now.Add(1)
+	if cago.Exist("short") {
+		t.Error("expected key to expire after 50ms")
+	}
+}
+
+func TestGetOr(t *testing.T) {
+	var now atomic.Int64
+	now.Store(1_700_000_000_000)
+	if err := cago.New(cago.Config{TimeoutCheck: 60000, Clock: now.Load}); err != nil {
+		t.Fatal(err)
+	}
+	cago.Set("port", 8080)
+	cago.Set("short", 1, 1)
+	cago.Set("name", "cago")
+	now.Add(5)
+
+	if got := cago.GetOr("port", 80); got != 8080 {
+		t.Errorf("expected stored 8080, got %d", got)
+	}
+	if got := cago.GetOr("missing", 80); got != 80 {
+		t.Errorf("expected default for missing key, got %d", got)
+	}
+	if got := cago.GetOr("short", 80); got != 80 {
+		t.Errorf("expected default for expired key, got %d", got)
+	}
+	if got := cago.GetOr("name", []string{"default"}); len(got) != 1 || got[0] != "default" {
+		t.Errorf("expected default for type mismatch, got %v", got)
+	}
+	if cago.Exist("missing") {
+		t.Error("expected default not to be stored")
+	}
+}
+
+func TestGetWithTTL(t *testing.T) {
+	var now atomic.Int64
+	now.Store(1_700_000_000_000)
+	if err := cago.New(cago.Config{TimeoutCheck: 60000, Clock: now.Load}); err != nil {
+		t.Fatal(err)
+	}
+	cago.Set("page", "<html>", 60000)
+	cago.Set("forever", "x")
+	cago.Set("short", "x", 1)
+	now.Add(5)
+
+	value, ttl, ok := cago.GetWithTTL[string]("page")
+	if !ok || value != "<html>" || ttl != 60000-5 {
+		t.Errorf("expected value with ttl 59995ms, got %q %d %v", value, ttl, ok)
+	}
+	if value, ttl, ok := cago.GetWithTTL[string]("forever"); !ok || value != "x" || ttl != 0 {
+		t.Errorf("expected zero ttl for non-expiring key, got %q %d %v", value, ttl, ok)
+	}
+	if value, ttl, ok := cago.GetWithTTL[string]("short"); ok || value != "" || ttl != 0 {
+		t.Errorf("expected miss for expired key, got %q %d %v", value, ttl, ok)
+	}
+	if _, _, ok := cago.GetWithTTL[string]("missing"); ok {
+		t.Error("expected miss for missing key")
+	}
+}
+
+func TestTypedGetters(t *testing.T) {
+	if err := cago.New(); err != nil {
+		t.Fatal(err)
+	}
+	cago.Set("string", "hello")
+	cago.Set("int", 42)
+	cago.Set("bool", true)
+	cago.Set("float", 3.5)
+
+	if v, ok := cago.GetString("string"); !ok || v != "hello" {
+		t.Errorf("GetString: expected hello, got %q (ok=%v)", v, ok)
+	}
+	if v, ok := cago.GetInt("int"); !ok || v != 42 {
+		t.Errorf("GetInt: expected 42, got %d (ok=%v)", v, ok)
+	}
+	if v, ok := cago.GetBool("bool"); !ok || !v {
+		t.Errorf("GetBool: expected true, got %v (ok=%v)", v, ok)
+	}
+	if v, ok := cago.GetFloat64("float"); !ok || v != 3.5 {
+		t.Errorf("GetFloat64: expected 3.5, got %v (ok=%v)", v, ok)
+	}
+
+	// Tipe yang tidak sesuai dan key yang tidak ada mengembalikan zero value dan false
+	if v, ok := cago.GetBool("string"); ok || v {
+		t.Errorf("GetBool on string: expected false, got %v (ok=%v)", v, ok)
+	}
+	if v, ok := cago.GetInt("missing"); ok || v != 0 {
+		t.Errorf("GetInt on missing key: expected 0, got %d (ok=%v)", v, ok)
+	}
+}
+
+func TestSetIfAbsent(t *testing.T) {
+	var now atomic.Int64
+	now.Store(1_700_000_000_000)
+	if err := cago.New(cago.Config{Clock: now.Load}); err != nil {
+		t.Fatal(err)
+	}
+
+	if !cago.SetIfAbsent("lock", "owner-1", 1) {
+		t.Fatal("expected the first SetIfAbsent to store the value")
+	}
+	if cago.SetIfAbsent("lock", "owner-2", 0) {
+		t.Error("expected SetIfAbsent to refuse a live key")
+	}
+	if rs := cago.Get[string]("lock"); rs == nil || *rs != "owner-1" {
+		t.Errorf("expected owner-1 to be kept, got %v", rs)
+	}
+
+	// Key yang sudah kedaluwarsa dianggap tidak ada
+	now.Add(1)
+	if !cago.SetIfAbsent("lock", "owner-3", 0) {
+		t.Error("expected SetIfAbsent to replace an expired key")
+	}
+	if rs := cago.Get[string]("lock"); rs == nil || *rs != "owner-3" {
+		t.Errorf("expected owner-3, got %v", rs)
+	}
+
+	// Nilai yang melebihi MaxValueSize ditolak seperti pada Set
+	if err := cago.New(cago.Config{MaxValueSize: 8}); err != nil {
+		t.Fatal(err)
+	}
+	if cago.SetIfAbsent("lock", strings.Repeat("x", 16), 0) {
+		t.Error("expected SetIfAbsent to reject a value over MaxValueSize")
+	}
+}
+
+func TestSwap(t *testing.T) {
+	var now atomic.Int64
+	now.Store(1_700_000_000_000)
+	if err := cago.New(cago.Config{Clock: now.Load}); err != nil {
+		t.Fatal(err)
+	}
+
+	if v, ok := cago.Swap("token", "first", 0); ok || v != "" {
+		t.Errorf("expected no previous value, got %q (ok=%v)", v, ok)
+	}
+	if v, ok := cago.Swap("token", "second", 60000); !ok || v != "first" {
+		t.Errorf("expected %q, got %q (ok=%v)", "first", v, ok)
+	}
+	if rs := cago.Get[string]("token"); rs == nil || *rs != "second" {
+		t.Errorf("expected new value second, got %v", rs)
+	}
+	if _, _, expires, _ := cago.GetMeta("token"); expires.IsZero() {
+		t.Error("expected max age to apply to the new value")
+	}
+
+	// Nilai lama yang sudah kedaluwarsa tidak dikembalikan
+	cago.Swap("token", "third", 1)
+	now.Add(1)
+	if v, ok := cago.Swap("token", "fourth", 0); ok {
+		t.Errorf("expected expired value not to be returned, got %q", v)
+	}
+	if rs := cago.Get[string]("token"); rs == nil || *rs != "fourth" {
+		t.Errorf("expected new value fourth, got %v", rs)
+	}
+}
+
+func TestSwapRejected(t *testing.T) {
+	if err := cago.New(cago.Config{MaxKeyLength: 8, MaxValueSize: 16}); err != nil {
+		t.Fatal(err)
+	}
+
+	cago.Swap("token", "first", 0)
+	if v, ok := cago.Swap("token", strings.Repeat("x", 32), 0); ok || v != "" {
+		t.Errorf("expected an oversized value to be rejected, got %q (ok=%v)", v, ok)
+	}
+	if rs := cago.Get[string]("token"); rs == nil || *rs != "first" {
+		t.Errorf("expected the old value to be kept, got %v", rs)
+	}
+	if _, ok := cago.Swap("too-long-key", "value", 0); ok {
+		t.Error("expected a key over MaxKeyLength to be rejected")
+	}
+	if rs := cago.Get[string]("too-long-key"); rs != nil {
+		t.Error("expected the rejected key not to be stored")
+	}
+}
+
+func TestUpdate(t *testing.T) {
+	if err := cago.New(); err != nil {
+		t.Fatal(err)
+	}
+
+	appendItem := func(item string) func([]string, bool) ([]string, uint64) {
+		return func(old []string, existed bool) ([]string, uint64) {
+			return append(old, item), 60000
+		}
+	}
+	var wg sync.WaitGroup
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			if err := cago.Update("list", appendItem(fmt.Sprint(i))); err != nil {
+				t.Error(err)
+			}
+		}(i)
+	}
+	wg.Wait()
+	if rs := cago.Get[[]string]("list"); rs == nil || len(*rs) != 50 {
+		t.Errorf("expected 50 appended items, got %v", rs)
+	}
+	if _, _, expires, _ := cago.GetMeta("list"); expires.IsZero() {
+		t.Error("expected maxAge from fn to apply")
+	}
+
+	// Nilai yang tidak sesuai tipe T tidak diteruskan ke fn
+	cago.Set("text", "not a number")
+	called := false
+	err := cago.Update("text", func(old []string, existed bool) ([]string, uint64) {
+		called = true
+		return old, 0
+	})
+	if err == nil || called {
+		t.Errorf("expected type mismatch error without calling fn, got %v (called=%v)", err, called)
+	}
+
+	// Key yang belum ada diteruskan sebagai zero value dengan existed false
+	err = cago.Update("counter", func(old int, existed bool) (int, uint64) {
+		if existed || old != 0 {
+			t.Errorf("expected missing key, got %d (existed=%v)", old, existed)
+		}
+		return 41, 0
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n, _ := cago.GetInt("counter"); n != 41 {
+		t.Errorf("expected 41, got %d", n)
+	}
+}
+
+func TestMaxMem(t *testing.T) {
+	value := strings.Repeat("x", 50) // 1 + 32 + 50 = 83 byte per entri
+
+	// Tanpa EvictOldestOnMaxMem, Set yang melampaui batas mengembalikan error
+	if err := cago.New(cago.Config{MAX_MEM: 200 * 8}); err != nil {
+		t.Fatal(err)
+	}
+	cago.Set("a", value)
+	cago.Set("b", value)
+	if err := cago.Set("c", value); !errors.Is(err, cago.ErrMemoryLimit) {
+		t.Errorf("expected ErrMemoryLimit, got %v", err)
+	}
+	if cago.Exist("c") {
+		t.Error("expected c not to be stored")
+	}
+
+	// Dengan EvictOldestOnMaxMem, entri paling lama dihapus sampai cukup
+	var now atomic.Int64
+	now.Store(1_700_000_000_000)
+	if err := cago.New(cago.Config{MAX_MEM: 200 * 8, EvictOldestOnMaxMem: true, Clock: now.Load}); err != nil {
+		t.Fatal(err)
+	}
+	cago.Set("a", value)
+	now.Add(2)
+	cago.Set("b", value)
+	now.Add(2)
+	if err := cago.Set("c", value); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if cago.Exist("a") || !cago.Exist("b") || !cago.Exist("c") {
+		t.Error("expected only the oldest entry to be evicted")
+	}
+	if size := cago.Size(); size != 166 {
+		t.Errorf("expected size 166, got %d", size)
+	}
+}
+
+func TestSize(t *testing.T) {
+	var now atomic.Int64
+	now.Store(1_700_000_000_000)
+	c, err := cago.NewCache(cago.Config{TimeoutCheck: 60000, Clock: now.Load})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer c.Close()
+	if size := c.Size(); size != 0 {
+		t.Errorf("expected empty cache to have size 0, got %d", size)
+	}
+
+	// Setiap entri berukuran panjang key + 32 byte header + payload
+	c.Set("text", strings.Repeat("x", 100)) // 4 + 32 + 100
+	c.Set("num", 42)                        // 3 + 32 + 8
+	c.Set("json", []int{1, 2, 3})           // 4 + 32 + 7
+	if size := c.Size(); size != 136+43+43 {
+		t.Errorf("expected size 222, got %d", size)
+	}
+
+	c.Put("text", "short") // 4 + 32 + 5
+	c.Set("gone", "x", 1)
+	now.Add(1)
+	if size := c.Size(); size != 41+43+43 {
+		t.Errorf("expected expired entries to be excluded, got %d", size)
+	}
+	c.Remove("num")
+	if size := c.Size(); size != 41+43 {
+		t.Errorf("expected size 84 after Remove, got %d", size)
+	}
+}
+
+func TestNewCache(t *testing.T) {
+	a, err := cago.NewCache()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer a.Close()
+	b, err := cago.NewCache(cago.Config{MaxEntries: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer b.Close()
+
+	a.Set("tenant", "a")
+	b.Set("tenant", "b")
+	if rs := cago.GetFrom[string](a, "tenant"); rs == nil || *rs != "a" {
+		t.Errorf("expected a, got %v", rs)
+	}
+	if rs := cago.GetFrom[string](b, "tenant"); rs == nil || *rs != "b" {
+		t.Errorf("expected b, got %v", rs)
+	}
+	if v, ok := a.GetAny("tenant").(store.Store); !ok || v.Text() != "a" {
+		t.Errorf("expected GetAny to return the stored value, got %v", v)
+	}
+
+	// Konfigurasi dan penghapusan pada satu instance tidak memengaruhi instance lain
+	a.Put("other", 1)
+	b.Put("other", 1)
+	if !a.Exist("tenant") || b.Exist("tenant") {
+		t.Error("expected MaxEntries to apply only to b")
+	}
+	a.Remove("tenant")
+	if a.Exist("tenant") {
+		t.Error("expected tenant to be removed from a")
+	}
+	if err := a.Clear(); err != nil {
+		t.Fatal(err)
+	}
+	if a.Exist("other") || !b.Exist("other") {
+		t.Error("expected Clear to affect only a")
+	}
+}
+
+func TestGetMeta(t *testing.T) {
+	var now atomic.Int64
+	now.Store(1_700_000_000_000)
+	if err := cago.New(cago.Config{Clock: now.Load}); err != nil {
+		t.Fatal(err)
+	}
+	cago.Set("session", "abc", 60000)
+	cago.Set("forever", "x")
+
+	created, updated, expires, ok := cago.GetMeta("session")
+	if !ok {
+		t.Fatal("expected session to exist")
+	}
+	if created.UnixMilli() != 1_700_000_000_000 || !updated.Equal(created) {
+		t.Errorf("unexpected timestamps: created %v, updated %v", created, updated)
+	}
+	if got := expires.Sub(created); got != time.Minute {
+		t.Errorf("expected expiry one minute after creation, got %v", got)
+	}
+
+	now.Add(5)
+	cago.GetAndTouch[string]("session", 60000)
+	if _, updated, _, _ := cago.GetMeta("session"); updated.Sub(created) != 5*time.Millisecond {
+		t.Errorf("expected updated to move forward, got %v", updated)
+	}
+
+	if _, _, expires, ok := cago.GetMeta("forever"); !ok || !expires.IsZero() {
+		t.Errorf("expected zero expiry for non-expiring key, got %v", expires)
+	}
+	if _, _, _, ok := cago.GetMeta("missing"); ok {
+		t.Error("expected ok=false for missing key")
+	}
+}
+
+func TestExpiringSoon(t *testing.T) {
+	var now atomic.Int64
+	now.Store(1_700_000_000_000)
+	if err := cago.New(cago.Config{TimeoutCheck: 60000, Clock: now.Load}); err != nil {
+		t.Fatal(err)
+	}
+	cago.Set("gone", "x", 1)
+	cago.Set("later", "x", 500)
+	cago.Set("soon", "x", 200)
+	cago.Set("far", "x", 60000)
+	cago.Set("forever", "x")
+	now.Add(5)
+
+	got := cago.ExpiringSoon(1000)
+	if len(got) != 2 || got[0] != "soon" || got[1] != "later" {
+		t.Errorf("expected [soon later], got %v", got)
+	}
+	if got := cago.ExpiringSoon(0); len(got) != 0 {
+		t.Errorf("expected no keys within 0ms, got %v", got)
+	}
+}
+
+func TestReadExpired(t *testing.T) {
+	var now atomic.Int64
+	now.Store(1_700_000_000_000)
+	c, err := cago.NewCache(cago.Config{TimeoutCheck: 60000, Clock: now.Load, CleanStrategy: cago.CleanSampled, CleanSampleSize: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer c.Close()
+	for i := 0; i < 100; i++ {
+		c.Put(fmt.Sprint("long-", i), i, 60000)
+	}
+	c.Put("short", "x", 1)
+	now.Add(1)
+
+	// Entri kedaluwarsa tidak dikembalikan walaupun belum dihapus
+	if cago.GetFrom[string](c, "short") != nil || c.GetAny("short") != nil || c.Exist("short") {
+		t.Error("expected expired entry to be reported as missing")
+	}
+	// Key yang ditemukan kedaluwarsa saat dibaca dihapus oleh pemeriksaan berikutnya,
+	// walaupun tidak terpilih sebagai sampel
+	if n := c.Sweep(); n != 1 {
+		t.Errorf("expected 1 expired entry, got %d", n)
+	}
+	if n := c.Stats().Entries; n != 100 {
+		t.Errorf("expected 100 entries left, got %d", n)
+	}
+}
+
+func TestClock(t *testing.T) {
+	var now atomic.Int64
+	now.Store(1_700_000_000_000)
+	c, err := cago.NewCache(cago.Config{TimeoutCheck: 60000, Clock: now.Load})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer c.Close()
+	c.Put("session", "token", 1000)
+	c.Put("forever", "x")
+	if created, _, expires, _ := c.GetMeta("session"); created.UnixMilli() != 1_700_000_000_000 || expires.Sub(created) != time.Second {
+		t.Errorf("expected times from the fake clock, got %v and %v", created, expires)
+	}
+
+	// Jam palsu dimajukan tanpa menunggu
+	now.Add(999)
+	if !c.Exist("session") {
+		t.Error("expected session to be kept before its max age")
+	}
+	now.Add(1)
+	if cago.GetFrom[string](c, "session") != nil {
+		t.Error("expected session to expire on the fake clock")
+	}
+	if n := c.RemoveExpired(); n != 1 {
+		t.Errorf("expected 1 expired entry, got %d", n)
+	}
+	if !c.Exist("forever") {
+		t.Error("expected entry without max age to be kept")
+	}
+}
+
+func TestSlidingTTL(t *testing.T) {
+	var now atomic.Int64
+	now.Store(1_700_000_000_000)
+	c, err := cago.NewCache(cago.Config{TimeoutCheck: 60000, Clock: now.Load, SlidingTTL: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer c.Close()
+	c.Put("session", "token", 1000)
+	c.Put("idle", "token", 1000)
+
+	// Setiap pembacaan menggeser masa berlaku sejauh maxAge aslinya
+	for i := 0; i < 5; i++ {
+		now.Add(800)
+		if cago.GetFrom[string](c, "session") == nil {
+			t.Fatalf("expected session to be alive after %d reads", i)
+		}
+	}
+	if c.Exist("idle") {
+		t.Error("expected idle entry to expire after its base max age")
+	}
+	if _, _, expires, _ := c.GetMeta("session"); expires.UnixMilli() != now.Load()+1000 {
+		t.Errorf("expected expiry 1s after the last read, got %v", expires)
+	}
+
+	// Tanpa akses, entri kedaluwarsa seperti biasa
+	now.Add(1000)
+	if cago.GetFrom[string](c, "session") != nil {
+		t.Error("expected session to expire once it is no longer read")
+	}
+}
+
+func TestSlidingTTLKeepsMetadata(t *testing.T) {
+	var now atomic.Int64
+	now.Store(1_700_000_000_000)
+	if err := cago.New(cago.Config{TimeoutCheck: 60000, Clock: now.Load, SlidingTTL: true}); err != nil {
+		t.Fatal(err)
+	}
+	cago.Put("session", "token", 1000)
+	created := now.Load()
+	now.Add(500)
+	since := uint64(now.Load())
+	now.Add(300)
+	if cago.Get[string]("session") == nil {
+		t.Fatal("expected session to be alive")
+	}
+
+	// Pembacaan tidak mengubah CreateAt dan tidak dilaporkan sebagai perubahan
+	got, _, expires, _ := cago.GetMeta("session")
+	if got.UnixMilli() != created {
+		t.Errorf("expected CreateAt %d to be kept, got %d", created, got.UnixMilli())
+	}
+	if expires.UnixMilli() != now.Load()+1000 {
+		t.Errorf("expected expiry 1s after the read, got %v", expires)
+	}
+	if records, err := cago.DiffSince(since); err != nil || len(records) != 0 {
+		t.Errorf("expected reads not to show up in DiffSince, got %v (%v)", records, err)
+	}
+}
+
+func TestTTLJitter(t *testing.T) {
+	c, err := cago.NewCache(cago.Config{TTLJitter: 10000})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer c.Close()
+	lowest, highest := time.Duration(1<<62), time.Duration(0)
+	for i := 0; i < 100; i++ {
+		key := fmt.Sprint("key-", i)
+		if err := c.Set(key, i, 60000); err != nil {
+			t.Fatal(err)
+		}
+		created, _, expires, _ := c.GetMeta(key)
+		ttl := expires.Sub(created)
+		if ttl < time.Minute || ttl > time.Minute+10*time.Second {
+			t.Fatalf("expected ttl within [1m, 1m10s], got %v", ttl)
+		}
+		lowest, highest = min(lowest, ttl), max(highest, ttl)
+
+		// Jitter disimpan sekali dan tidak berubah ketika dibaca ulang
+		if _, _, again, _ := c.GetMeta(key); !again.Equal(expires) {
+			t.Fatalf("expected stable expiry, got %v and %v", expires, again)
+		}
+	}
+	if highest-lowest < 5*time.Second {
+		t.Errorf("expected expiries spread across the jitter window, got %v..%v", lowest, highest)
+	}
+
+	if err := c.Set("forever", "x"); err != nil {
+		t.Fatal(err)
+	}
+	if _, _, expires, _ := c.GetMeta("forever"); !expires.IsZero() {
+		t.Errorf("expected no jitter without maxAge, got %v", expires)
+	}
+}
+
+func TestKeyValidation(t *testing.T) {
+	errNull := errors.New("key contains a null byte")
+	err := cago.New(cago.Config{
+		MaxKeyLength: 8,
+		KeyValidator: func(key string) error {
+			if key == "" {
+				return errors.New("key is empty")
+			}
+			if strings.ContainsRune(key, 0) {
+				return errNull
+			}
+			return nil
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := cago.Set("12345678", "ok"); err != nil {
+		t.Errorf("expected 8 byte key to be accepted, got %v", err)
+	}
+	if err := cago.Set("123456789", "x"); !errors.Is(err, cago.ErrInvalidKey) {
+		t.Errorf("expected ErrInvalidKey for long key, got %v", err)
+	}
+	if err := cago.Put("a\x00b", "x"); !errors.Is(err, cago.ErrInvalidKey) || !errors.Is(err, errNull) {
+		t.Errorf("expected validator error, got %v", err)
+	}
+	if err := cago.Put("", "x"); !errors.Is(err, cago.ErrInvalidKey) {
+		t.Errorf("expected empty key to be rejected, got %v", err)
+	}
+	if cago.SetIfAbsent("123456789", "x", 0) {
+		t.Error("expected SetIfAbsent to reject long key")
+	}
+	if cago.Exist("123456789") || cago.Exist("a\x00b") || cago.Exist("") {
+		t.Error("expected invalid keys not to be stored")
+	}
+}
+
+func TestMaxValueSize(t *testing.T) {
+	c, err := cago.NewCache(cago.Config{MaxValueSize: 10})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer c.Close()
+	if err := c.Set("fits", strings.Repeat("x", 10)); err != nil {
+		t.Fatalf("expected 10 bytes to fit, got %v", err)
+	}
+	if err := c.Set("big", strings.Repeat("x", 11)); !errors.Is(err, cago.ErrValueTooLarge) {
+		t.Errorf("expected ErrValueTooLarge, got %v", err)
+	}
+	if c.Exist("big") {
+		t.Error("expected oversized value not to be stored")
+	}
+
+	// Tipe lain diukur dari panjang JSON-nya: {"name":"abc"} berukuran 14 byte
+	type user struct {
+		Name string `json:"name"`
+	}
+	if err := c.Put("fits", user{Name: "abc"}); !errors.Is(err, cago.ErrValueTooLarge) {
+		t.Errorf("expected ErrValueTooLarge for JSON value, got %v", err)
+	}
+	if got, _ := c.GetString("fits"); got != strings.Repeat("x", 10) {
+		t.Errorf("expected previous value to be kept, got %q", got)
+	}
+}
+
+func TestTimezone(t *testing.T) {
+	c, err := cago.NewCache(cago.Config{Timezone: cago.TimezoneTokyo})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer c.Close()
+	before := time.Now()
+	if err := c.Put("key", "value", 60000); err != nil {
+		t.Fatal(err)
+	}
+
+	created, _, expires, ok := c.GetMeta("key")
+	if !ok {
+		t.Fatal("expected key to exist")
+	}
+	if name, offset := created.Zone(); offset != 9*60*60 {
+		t.Errorf("expected UTC+9, got %s (%d)", name, offset)
+	}
+	if created.Before(before.Truncate(time.Millisecond)) || expires.Sub(created) != time.Minute {
+		t.Errorf("expected the same instant in another zone, got %v and %v", created, expires)
+	}
+
+	if _, err := cago.NewCache(cago.Config{Timezone: "Mars/Olympus"}); err == nil {
+		t.Error("expected unknown timezone to fail")
+	}
+}
+
+func TestConfigValidation(t *testing.T) {
+	invalid := map[string]cago.Config{
+		"write-behind without path":  {WriteBehind: true},
+		"write-through without path": {WriteThrough: true},
+		"wal without path":           {SQLiteWAL: true},
+		"vacuum without path":        {VacuumOnClose: true},
+		"unknown policy":             {EvictionPolicy: cago.EvictionPolicy(99)},
+		"unknown strategy":           {CleanStrategy: cago.CleanStrategy(-1)},
+		"ratio above one":            {CleanExpiredRatio: 1.5},
+		"min above max":              {MAX_MEM: 8 * 1024, MIN_MEM_ALLOCATION: 8 * 2048},
+		"jitter too large":           {TTLJitter: math.MaxUint64},
+		"check interval too small":   {TimeoutCheck: 1},
+		"negative shards":            {Shards: -1},
+		"negative clean workers":     {CleanWorkers: -1},
+	}
+	for name, config := range invalid {
+		if _, err := cago.NewCache(config); !errors.Is(err, cago.ErrInvalidConfig) {
+			t.Errorf("%s: expected ErrInvalidConfig, got %v", name, err)
+		}
+	}
+
+	// Semua masalah dilaporkan sekaligus, termasuk jenis kesalahan yang sudah ada
+	err := cago.New(cago.Config{WriteThrough: true, WriteBehind: true, Timezone: "Mars/Olympus"})
+	if !errors.Is(err, cago.ErrWriteMode) || !errors.Is(err, cago.ErrInvalidConfig) || !strings.Contains(err.Error(), "Mars/Olympus") {
+		t.Errorf("expected every problem to be reported, got %v", err)
+	}
+
+	// Konfigurasi yang valid tetap diterima, termasuk LRU dengan MAX_MEM
+	c, err := cago.NewCache(cago.Config{EvictionPolicy: cago.PolicyLRU, MAX_MEM: 8 * 4096, CleanExpiredRatio: 1})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	c.Close()
+}
+
+func TestRemovePrefix(t *testing.T) {
+	if err := cago.New(); err != nil {
+		t.Fatal(err)
+	}
+	cago.Set("user:1:profile", "a")
+	cago.Set("user:1:settings", "b")
+	cago.Set("user:10:profile", "c")
+	cago.Set("order:1", "d")
+
+	if n := cago.RemovePrefix("user:1:"); n != 2 {
+		t.Errorf("expected 2 keys removed, got %d", n)
+	}
+	if cago.Exist("user:1:profile") || !cago.Exist("user:10:profile") {
+		t.Error("expected only keys under user:1: to be removed")
+	}
+	if n := cago.RemovePrefix(""); n != 2 {
+		t.Errorf("expected empty prefix to remove the remaining 2 keys, got %d", n)
+	}
+}
+
+func TestFloat(t *testing.T) {
+	if err := cago.New(); err != nil {
+		t.Fatal(err)
+	}
+	cago.Set("pi", 3.14)
+	cago.Set("e", float32(2.71))
+
+	if rs := cago.Get[float64]("pi"); rs == nil || *rs != 3.14 {
+		t.Errorf("expected 3.14, got %v", rs)
+	}
+	if rs := cago.Get[float32]("pi"); rs == nil || *rs != float32(3.14) {
+		t.Errorf("expected 3.14, got %v", rs)
+	}
+	if rs := cago.Get[float32]("e"); rs == nil || *rs != float32(2.71) {
+		t.Errorf("expected 2.71, got %v", rs)
+	}
+}
+
+func TestTime(t *testing.T) {
+	if err := cago.New(); err != nil {
+		t.Fatal(err)
+	}
+	jakarta := time.FixedZone("WIB", 7*60*60)
+	tests := map[string]time.Time{
+		"now":    time.Now(),
+		"nanos":  time.Date(2024, 5, 17, 10, 30, 0, 123456789, jakarta),
+		"zero":   {},
+		"before": time.Date(1901, 1, 1, 0, 0, 0, 1, time.UTC),
+		"far":    time.Date(9999, 12, 31, 23, 59, 59, 999999999, time.UTC),
+	}
+	for key, want := range tests {
+		if err := cago.Set(key, want); err != nil {
+			t.Fatal(err)
+		}
+		got := cago.Get[time.Time](key)
+		if got == nil || !got.Equal(want) {
+			t.Errorf("%s: expected %v, got %v", key, want, got)
+		}
+	}
+	if got := cago.Get[time.Time]("zero"); got == nil || !got.IsZero() {
+		t.Errorf("expected zero time, got %v", got)
+	}
+	if got := cago.Get[time.Time]("nanos"); got == nil || got.Nanosecond() != 123456789 {
+		t.Errorf("expected nanosecond fidelity, got %v", got)
+	}
+}
+
+func TestBytes(t *testing.T) {
+	if err := cago.New(); err != nil {
+		t.Fatal(err)
+	}
+	raw := []byte{0x00, 0xff, 0x10}
+	if err := cago.Set("raw", raw); err != nil {
+		t.Fatal(err)
+	}
+	if rs := cago.Get[[]byte]("raw"); rs == nil || !bytes.Equal(*rs, raw) {
+		t.Errorf("expected %v, got %v", raw, rs)
+	}
+	// Data disimpan apa adanya, bukan sebagai JSON base64
+	if v := cago.MGet("raw")["raw"]; v.Length() != uint64(len(raw)) {
+		t.Errorf("expected raw payload of %d bytes, got %d", len(raw), v.Length())
+	}
+}
@@ -0,0 +1,105 @@
+// Copyright (c) 2024, Jasakode Authors.
+// All rights reserved.
+// Use of this source code is governed by a BSD 3-Clause
+// license that can be found in the LICENSE file.
+
+package cago
+
+import (
+	"errors"
+	"fmt"
+	"math"
+
+	"github.com/jasakode/cago/lib"
+	"github.com/jasakode/cago/store"
+)
+
+// ErrNotInteger dikembalikan oleh Increment ketika nilai yang ada bukan integer 64-bit.
+var ErrNotInteger = errors.New("value is not a 64-bit integer")
+
+// ErrOverflow dikembalikan oleh Increment ketika hasil penjumlahan melampaui batas int64.
+var ErrOverflow = errors.New("integer overflow")
+
+// Increment menambahkan delta ke nilai integer dengan key yang diberikan dalam
+// satu operasi atomik. Key yang belum ada atau sudah kedaluwarsa dimulai dari 0
+// tanpa MaxAge, sedangkan key yang ada mempertahankan CreateAt dan MaxAge-nya.
+// Nilai baru disimpan ke database lebih dahulu dalam operasi yang sama; jika
+// penyimpanan gagal, nilai di memori tidak diubah.
+//
+// Parameter:
+//   - key (string): Key unik yang digunakan untuk mengidentifikasi nilai dalam store.
+//   - delta (int64): Nilai yang ditambahkan, boleh negatif.
+//
+// Mengembalikan:
+//   - int64: Nilai setelah ditambahkan.
+//   - error: ErrNotInteger, ErrOverflow, atau kesalahan saat menyimpan nilai.
+func (app *App) Increment(key string, delta int64) (int64, error) {
+	defer app.dispatch()
+	s := app.shardFor(key)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	old, exists := s.data[key]
+	if exists && app.expiredEntry(key, old, app.now()) {
+		exists = false
+	}
+	var n int64
+	var data store.Store
+	if exists {
+		p := old.Bytes()
+		if len(p) != 8 {
+			return 0, fmt.Errorf("key %q: %w", key, ErrNotInteger)
+		}
+		n, _ = lib.ByteToInt64(p)
+	}
+	if (delta > 0 && n > math.MaxInt64-delta) || (delta < 0 && n < math.MinInt64-delta) {
+		return 0, fmt.Errorf("key %q: %w", key, ErrOverflow)
+	}
+	n += delta
+	if exists {
+		data = old.SetDataAt(lib.Int64ToByte(n), app.now())
+	} else {
+		data = store.NewStoreAt(lib.Int64ToByte(n), app.now())
+	}
+	data[store.KindIndex] = uint8(store.KindInt) // data baru dibuat di atas dan belum dibagikan
+
+	// Database ditulis lebih dahulu, sehingga kegagalannya tidak mengubah memori
+	// dan setEntry tidak menghapus entri lain untuk nilai yang tidak tersimpan
+	if app.db != nil {
+		if err := app.writeThrough(key, data); err != nil {
+			return 0, err
+		}
+		return n, nil
+	}
+	if err := app.setEntry(key, data); err != nil {
+		return 0, err
+	}
+	return n, nil
+}
+
+// Increment menjalankan App.Increment pada instance global yang dibuat oleh New.
+func Increment(key string, delta int64) (int64, error) {
+	return app.Increment(key, delta)
+}
+
+// Decrement mengurangi nilai integer dengan key yang diberikan sebesar delta,
+// dengan aturan yang sama seperti Increment.
+//
+// Parameter:
+//   - key (string): Key unik yang digunakan untuk mengidentifikasi nilai dalam store.
+//   - delta (int64): Nilai yang dikurangkan.
+//
+// Mengembalikan:
+//   - int64: Nilai setelah dikurangi.
+//   - error: ErrNotInteger, ErrOverflow, atau kesalahan saat menyimpan nilai.
+func (app *App) Decrement(key string, delta int64) (int64, error) {
+	if delta == math.MinInt64 {
+		return 0, fmt.Errorf("key %q: %w", key, ErrOverflow)
+	}
+	return app.Increment(key, -delta)
+}
+
+// Decrement menjalankan App.Decrement pada instance global yang dibuat oleh New.
+func Decrement(key string, delta int64) (int64, error) {
+	return app.Decrement(key, delta)
+}
@@ -0,0 +1,119 @@
+// Copyright (c) 2024, Jasakode Authors.
+// All rights reserved.
+// Use of this source code is governed by a BSD 3-Clause
+// license that can be found in the LICENSE file.
+
+package cago_test
+
+import (
+	"database/sql"
+	"errors"
+	"math"
+	"path/filepath"
+	"testing"
+
+	"github.com/jasakode/cago"
+)
+
+func TestIncrement(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "counter.db")
+	if err := cago.New(cago.Config{Path: path}); err != nil {
+		t.Fatal(err)
+	}
+	for i := 1; i <= 3; i++ {
+		if n, err := cago.Increment("hits", 1); err != nil || n != int64(i) {
+			t.Fatalf("expected %d, got %d (%v)", i, n, err)
+		}
+	}
+	if n, err := cago.Decrement("hits", 5); err != nil || n != -2 {
+		t.Errorf("expected -2, got %d (%v)", n, err)
+	}
+	if n, err := cago.Increment("hits", 10); err != nil || n != 8 {
+		t.Errorf("expected 8, got %d (%v)", n, err)
+	}
+
+	// Nilai yang bukan integer 64-bit dan hasil yang melampaui batas ditolak
+	cago.Set("name", "cago")
+	if _, err := cago.Increment("name", 1); !errors.Is(err, cago.ErrNotInteger) {
+		t.Errorf("expected ErrNotInteger, got %v", err)
+	}
+	cago.Set("max", int64(math.MaxInt64))
+	if _, err := cago.Increment("max", 1); !errors.Is(err, cago.ErrOverflow) {
+		t.Errorf("expected ErrOverflow, got %v", err)
+	}
+
+	// Counter dilanjutkan dari nilai yang tersimpan di database
+	if err := cago.New(cago.Config{Path: path}); err != nil {
+		t.Fatal(err)
+	}
+	defer cago.Close()
+	if rs := cago.Get[int64]("hits"); rs == nil || *rs != 8 {
+		t.Fatalf("expected 8 after reload, got %v", rs)
+	}
+	if n, err := cago.Increment("hits", 1); err != nil || n != 9 {
+		t.Errorf("expected 9, got %d (%v)", n, err)
+	}
+}
+
+func TestIncrementRollback(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "counter.db")
+	if err := cago.New(cago.Config{Path: path}); err != nil {
+		t.Fatal(err)
+	}
+	defer cago.Close()
+	cago.Increment("hits", 5)
+
+	// Trigger SQLite menolak setiap penulisan sehingga Increment harus dibatalkan
+	db, err := sql.Open("sqlite3", path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+	for _, q := range []string{
+		`CREATE TRIGGER reject_update BEFORE UPDATE ON cagos BEGIN SELECT RAISE(ABORT, 'rejected'); END;`,
+		`CREATE TRIGGER reject_insert BEFORE INSERT ON cagos BEGIN SELECT RAISE(ABORT, 'rejected'); END;`,
+	} {
+		if _, err := db.Exec(q); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if _, err := cago.Increment("hits", 1); err == nil {
+		t.Fatal("expected Increment to fail")
+	}
+	if rs := cago.Get[int64]("hits"); rs == nil || *rs != 5 {
+		t.Errorf("expected in-memory value to be rolled back to 5, got %v", rs)
+	}
+	if _, err := cago.Increment("fresh", 1); err == nil {
+		t.Fatal("expected Increment to fail")
+	}
+	if cago.Exist("fresh") {
+		t.Error("expected fresh key to be removed after the failed write")
+	}
+}
+
+func TestIncrementRollbackKeepsEvicted(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "counter.db")
+	if err := cago.New(cago.Config{Path: path, MaxEntries: 1}); err != nil {
+		t.Fatal(err)
+	}
+	defer cago.Close()
+	cago.Set("old", "value")
+
+	db, err := sql.Open("sqlite3", path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+	if _, err := db.Exec(`CREATE TRIGGER reject_insert BEFORE INSERT ON cagos BEGIN SELECT RAISE(ABORT, 'rejected'); END;`); err != nil {
+		t.Fatal(err)
+	}
+
+	// Penulisan yang gagal tidak boleh mengeluarkan entri lain dari memori
+	if _, err := cago.Increment("hits", 1); err == nil {
+		t.Fatal("expected Increment to fail")
+	}
+	if !cago.Exist("old") || cago.Exist("hits") {
+		t.Error("expected old to stay cached and hits to be absent")
+	}
+}
@@ -7,9 +7,17 @@ package cago
 
 import (
 	"database/sql"
+	"errors"
 	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
 	"sync"
+	"time"
 
+	"github.com/jasakode/cago/store"
 	_ "github.com/mattn/go-sqlite3"
 )
 
@@ -21,9 +29,15 @@ import (
 //   - sqldb: Pointer ke objek sql.DB yang merepresentasikan koneksi database SQLite.
 //   - tableName: Nama tabel yang digunakan dalam operasi database.
 type database struct {
-	mu        sync.Mutex // Mutex untuk menghindari race condition.
-	sqldb     *sql.DB    // Koneksi ke database SQLite.
-	tableName string     // Nama tabel yang digunakan dalam query.
+	mu        sync.Mutex   // Mutex untuk menghindari race condition.
+	sqldb     *sql.DB      // Koneksi ke database SQLite.
+	tableName string       // Nama tabel yang digunakan dalam query.
+	wb        *writeBehind // Antrean penulisan asinkron, nil jika Config.WriteBehind tidak aktif.
+}
+
+// execer adalah bagian dari *sql.DB dan *sql.Tx yang digunakan untuk menulis data.
+type execer interface {
+	Exec(query string, args ...any) (sql.Result, error)
 }
 
 // Struktur `model` merepresentasikan entitas data yang disimpan dalam tabel database.
@@ -33,46 +47,116 @@ type database struct {
 //   - ID: ID unik dari setiap entri dalam tabel, yang di-auto-increment oleh database.
 //   - Key: Kunci (key) untuk setiap entri yang bertipe string.
 //   - Value: Nilai (value) yang disimpan dalam bentuk byte array.
+//   - MaxAge, CreateAt, UpdateAt: Salinan metadata dari header store untuk penyaringan dengan SQL.
 type model struct {
-	ID    uint64 `json:"id"`    // ID unik dari setiap entri, di-generate otomatis.
-	Key   string `json:"key"`   // Kunci untuk mengidentifikasi entri.
-	Value []byte `json:"value"` // Nilai data yang disimpan dalam format byte.
+	ID       uint64 `json:"id"`        // ID unik dari setiap entri, di-generate otomatis.
+	Key      string `json:"key"`       // Kunci untuk mengidentifikasi entri.
+	Value    []byte `json:"value"`     // Nilai data yang disimpan dalam format byte.
+	MaxAge   uint64 `json:"max_age"`   // Usia maksimum data dalam milidetik, 0 berarti tidak kedaluwarsa.
+	CreateAt uint64 `json:"create_at"` // Waktu pembuatan data dalam milidetik.
+	UpdateAt uint64 `json:"update_at"` // Waktu pembaruan terakhir data dalam milidetik.
 }
 
+// ErrInvalidTableName dikembalikan ketika Config.TableName bukan identifier SQL yang aman.
+var ErrInvalidTableName = errors.New("invalid table name")
+
+// tableNamePattern membatasi nama tabel karena nama tersebut disisipkan langsung ke dalam query.
+var tableNamePattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
 // InitializeDB menginisialisasi koneksi database SQLite dan menyimpannya dalam aplikasi.
 // Fungsi ini menetapkan nama tabel yang digunakan, membuka koneksi ke database,
 // dan menyimpan objek database ke dalam field aplikasi.
 //
 // Langkah-langkah:
 //  1. Membuat objek database baru dengan nama tabel yang ditentukan.
-//  2. Membuka koneksi ke SQLite menggunakan jalur database dari konfigurasi aplikasi.
+//  2. Memastikan direktori database ada, lalu membuka koneksi ke SQLite dan memeriksanya dengan Ping.
 //  3. Menyimpan koneksi database ke dalam aplikasi dengan penguncian untuk memastikan thread safety.
 //
 // Mengembalikan:
-//   - error: Kesalahan jika koneksi database gagal dibuka.
+//   - error: ErrInvalidTableName jika Config.TableName tidak valid, atau kesalahan jika koneksi database gagal dibuka.
 func (app *App) InitializeDB() error {
 	// Membuat instance baru dari struct database dan menetapkan nama tabel.
+	if !tableNamePattern.MatchString(app.config.TableName) {
+		return fmt.Errorf("table name %q: %w", app.config.TableName, ErrInvalidTableName)
+	}
 	db := database{}
-	db.tableName = "cagos"
+	db.tableName = app.config.TableName
+
+	if err := app.prepareDir(); err != nil {
+		return err
+	}
 
 	// Membuka koneksi ke SQLite menggunakan path yang disimpan dalam konfigurasi aplikasi.
-	d, err := sql.Open("sqlite3", app.config.Path)
+	d, err := sql.Open("sqlite3", app.dsn())
 	if err != nil {
-		return err // Mengembalikan kesalahan jika koneksi gagal.
+		return fmt.Errorf("%w: opening database %q: %w", ErrBackend, app.config.Path, err)
+	}
+	// Semua query sudah diserialkan oleh db.mu, sehingga satu koneksi sudah cukup
+	// dan menghindari kesalahan "database is locked" antar koneksi di dalam pool.
+	d.SetMaxOpenConns(1)
+	// sql.Open tidak membuka file, sehingga path yang salah baru terdeteksi oleh Ping
+	if err := d.Ping(); err != nil {
+		d.Close()
+		return fmt.Errorf("%w: opening database %q: %w", ErrBackend, app.config.Path, err)
 	}
 
-	// Mengunci akses ke aplikasi untuk mencegah race condition saat menginisialisasi database.
-	app.mu.Lock()
-	defer app.mu.Unlock()
+	// Mengunci semua shard untuk mencegah race condition saat menginisialisasi database.
+	app.lockAll()
+	defer app.unlockAll()
 
 	// Menetapkan koneksi database ke objek database.
 	db.sqldb = d
+	// Menjalankan antrean penulisan asinkron jika diaktifkan
+	if app.config.WriteBehind {
+		db.startWriteBehind(time.Duration(app.config.FlushInterval)*time.Millisecond, app.config.FlushSize)
+	}
 	// Menyimpan objek database ke dalam aplikasi.
 	app.db = &db
 
 	return nil // Mengembalikan nil jika inisialisasi berhasil.
 }
 
+// prepareDir memastikan direktori induk dari Config.Path ada, dan membuatnya
+// jika Config.CreateDir aktif. Path berupa URI "file:" atau ":memory:" dilewati.
+func (app *App) prepareDir() error {
+	path := app.config.Path
+	if strings.HasPrefix(path, "file:") || strings.HasPrefix(path, ":memory:") {
+		return nil
+	}
+	dir := filepath.Dir(path)
+	info, err := os.Stat(dir)
+	if err == nil {
+		if !info.IsDir() {
+			return fmt.Errorf("database directory %q is not a directory", dir)
+		}
+		return nil
+	}
+	if !errors.Is(err, fs.ErrNotExist) {
+		return fmt.Errorf("database directory %q: %w", dir, err)
+	}
+	if !app.config.CreateDir {
+		return fmt.Errorf("database directory %q does not exist", dir)
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return fmt.Errorf("creating database directory %q: %w", dir, err)
+	}
+	return nil
+}
+
+// dsn membangun data source name SQLite dari Config.Path beserta pragma
+// busy_timeout dan journal_mode. Pragma diberikan melalui DSN agar berlaku
+// untuk setiap koneksi yang dibuka oleh pool.
+func (app *App) dsn() string {
+	params := fmt.Sprintf("_busy_timeout=%d", app.config.SQLiteBusyTimeout)
+	if app.config.SQLiteWAL {
+		params += "&_journal_mode=WAL"
+	}
+	if strings.Contains(app.config.Path, "?") {
+		return app.config.Path + "&" + params
+	}
+	return app.config.Path + "?" + params
+}
+
 // CreateTableIfNotExist membuat tabel baru jika tabel dengan nama yang sama belum ada di database.
 // Fungsi ini digunakan untuk memastikan tabel tersedia sebelum melakukan operasi lain.
 //
@@ -80,6 +164,10 @@ func (app *App) InitializeDB() error {
 //   - id: Kunci utama (autoincrement).
 //   - key: Teks unik yang tidak boleh NULL.
 //   - value: Data dalam bentuk BLOB.
+//   - max_age, create_at, update_at: Metadata dari header store.
+//
+// Tabel lama yang hanya memiliki kolom key dan value akan ditambahkan kolom
+// metadata dengan nilai 0, sehingga datanya tetap dimuat.
 //
 // Mengembalikan:
 //   - error: Kesalahan jika terjadi kegagalan dalam eksekusi query.
@@ -89,7 +177,10 @@ func (db *database) CreateTableIfNotExist() error {
     CREATE TABLE IF NOT EXISTS %s (
         id INTEGER PRIMARY KEY AUTOINCREMENT,
         key TEXT NOT NULL UNIQUE,
-        value BLOB
+        value BLOB,
+        max_age INTEGER NOT NULL DEFAULT 0,
+        create_at INTEGER NOT NULL DEFAULT 0,
+        update_at INTEGER NOT NULL DEFAULT 0
     );`
 
 	// Mengunci akses database untuk mencegah race condition saat membuat tabel.
@@ -99,10 +190,46 @@ func (db *database) CreateTableIfNotExist() error {
 	// Menjalankan query untuk membuat tabel.
 	_, err := db.sqldb.Exec(fmt.Sprintf(createTableQuery, db.tableName))
 	if err != nil {
-		return err // Mengembalikan kesalahan jika query gagal.
+		return backendErr(err) // Mengembalikan kesalahan jika query gagal.
 	}
 
-	return nil // Mengembalikan nil jika tabel berhasil dibuat atau sudah ada.
+	return backendErr(db.migrate())
+}
+
+// migrate menambahkan kolom metadata ke tabel yang dibuat oleh versi lama.
+// Fungsi ini harus dipanggil ketika db.mu sedang dipegang.
+//
+// Mengembalikan:
+//   - error: Kesalahan jika struktur tabel gagal dibaca atau diubah.
+func (db *database) migrate() error {
+	rows, err := db.sqldb.Query(fmt.Sprintf(`SELECT name FROM pragma_table_info('%s');`, db.tableName))
+	if err != nil {
+		return err
+	}
+	columns := map[string]bool{}
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			rows.Close()
+			return err
+		}
+		columns[name] = true
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	for _, column := range []string{"max_age", "create_at", "update_at"} {
+		if columns[column] {
+			continue
+		}
+		alterQuery := `ALTER TABLE %s ADD COLUMN %s INTEGER NOT NULL DEFAULT 0;`
+		if _, err := db.sqldb.Exec(fmt.Sprintf(alterQuery, db.tableName, column)); err != nil {
+			return err
+		}
+	}
+	return nil
 }
 
 // Update memperbarui nilai (value) yang terkait dengan key tertentu dalam tabel.
@@ -129,7 +256,7 @@ func (db *database) Update(key string, data []byte) error {
 	// Menjalankan query untuk memperbarui data.
 	_, err := db.sqldb.Exec(fmt.Sprintf(updateQuery, db.tableName), data, key)
 	if err != nil {
-		return err // Mengembalikan kesalahan jika query gagal.
+		return backendErr(err) // Mengembalikan kesalahan jika query gagal.
 	}
 
 	return nil // Mengembalikan nil jika data berhasil diperbarui.
@@ -137,6 +264,7 @@ func (db *database) Update(key string, data []byte) error {
 
 // InsertOrUpdate menambahkan data baru atau memperbarui data yang sudah ada berdasarkan key.
 // Fungsi ini menggunakan ON CONFLICT untuk menangani situasi di mana key yang sama sudah ada dalam tabel.
+// Kolom max_age, create_at, dan update_at diisi dari header store.
 //
 // Parameter:
 //   - key: Kunci unik yang digunakan untuk mengidentifikasi data.
@@ -145,20 +273,38 @@ func (db *database) Update(key string, data []byte) error {
 // Mengembalikan:
 //   - error: Kesalahan yang terjadi selama proses insert atau update.
 func (db *database) InsertOrUpdate(key string, data []byte) error {
+	// Dengan write-behind, data diantrekan dan ditulis oleh goroutine latar belakang
+	if db.wb != nil {
+		db.wb.enqueue(writeOp{key: key, data: data})
+		return nil
+	}
+
 	// Mengunci akses ke database untuk menghindari kondisi balapan (race condition).
 	db.mu.Lock()
 	defer db.mu.Unlock()
+	return backendErr(db.insertOrUpdate(db.sqldb, key, data))
+}
 
+// insertOrUpdate menjalankan query InsertOrUpdate menggunakan ex.
+// Fungsi ini harus dipanggil ketika db.mu sedang dipegang.
+func (db *database) insertOrUpdate(ex execer, key string, data []byte) error {
 	// Query untuk melakukan insert jika key belum ada, atau update jika key sudah ada.
 	insertOrUpdateQuery := `
-		INSERT INTO %s (key, value) 
-		VALUES (?, ?)
+		INSERT INTO %s (key, value, max_age, create_at, update_at) 
+		VALUES (?, ?, ?, ?, ?)
 		ON CONFLICT(key) 
-		DO UPDATE SET value = excluded.value;
+		DO UPDATE SET value = excluded.value, max_age = excluded.max_age,
+			create_at = excluded.create_at, update_at = excluded.update_at;
 	`
 
+	// Metadata dibaca dari header store, data yang bukan store disimpan dengan metadata 0
+	var maxAge, createAt, updateAt uint64
+	if s := store.ParseStore(data); len(s) > 0 {
+		maxAge, createAt, updateAt = s.MaxAge(), s.CreateAt(), s.UpdateAt()
+	}
+
 	// Menjalankan query insert atau update dengan parameter key dan data.
-	_, err := db.sqldb.Exec(fmt.Sprintf(insertOrUpdateQuery, db.tableName), key, data)
+	_, err := ex.Exec(fmt.Sprintf(insertOrUpdateQuery, db.tableName), key, data, maxAge, createAt, updateAt)
 	if err != nil {
 		return err // Mengembalikan kesalahan jika eksekusi query gagal.
 	}
@@ -174,17 +320,56 @@ func (db *database) InsertOrUpdate(key string, data []byte) error {
 //   - *[]model: Slice dari objek model yang berisi data dari tabel.
 //   - error: Kesalahan jika ada masalah saat mengeksekusi query atau mengakses data.
 func (db *database) FindALL() (*[]model, error) {
+	return db.find(`SELECT id, key, value, max_age, create_at, update_at FROM %s;`)
+}
+
+// FindUnexpired mengambil paling banyak limit baris yang belum kedaluwarsa pada
+// waktu now berdasarkan kolom max_age dan create_at, dengan id lebih besar dari
+// after dan diurutkan berdasarkan id. Halaman berikutnya diambil dengan memberikan
+// id terakhir sebagai after, sehingga seluruh tabel tidak perlu dimuat sekaligus.
+//
+// Parameter:
+//   - now (uint64): Waktu saat ini dalam milidetik.
+//   - after (uint64): Id terakhir dari halaman sebelumnya, atau 0 untuk halaman pertama.
+//   - limit (int): Jumlah baris maksimal dalam satu halaman.
+//
+// Mengembalikan:
+//   - *[]model: Slice dari objek model yang berisi data dari tabel.
+//   - error: Kesalahan jika ada masalah saat mengeksekusi query atau mengakses data.
+func (db *database) FindUnexpired(now, after uint64, limit int) (*[]model, error) {
+	return db.find(`
+		SELECT id, key, value, max_age, create_at, update_at FROM %s
+		WHERE id > ? AND (max_age = 0 OR create_at + max_age > ?)
+		ORDER BY id LIMIT ?;
+	`, after, now, limit)
+}
+
+// FindByKey mengambil baris dengan key yang diberikan.
+//
+// Parameter:
+//   - key (string): Kunci dari entri yang dicari.
+//
+// Mengembalikan:
+//   - *model: Baris yang ditemukan, atau nil jika key tidak ada di tabel.
+//   - error: Kesalahan jika ada masalah saat mengeksekusi query atau mengakses data.
+func (db *database) FindByKey(key string) (*model, error) {
+	rows, err := db.find(`SELECT id, key, value, max_age, create_at, update_at FROM %s WHERE key = ?;`, key)
+	if err != nil || len(*rows) == 0 {
+		return nil, err
+	}
+	return &(*rows)[0], nil
+}
+
+// find menjalankan query SELECT dan memindai hasilnya ke dalam model.
+func (db *database) find(selectQuery string, args ...any) (*[]model, error) {
 	// Mengunci database untuk mencegah kondisi balapan (race condition) selama pengaksesan.
 	db.mu.Lock()
 	defer db.mu.Unlock()
 
-	// Menyiapkan query untuk mengambil semua data dari tabel.
-	selectQuery := `SELECT id, key, value FROM %s;`
-
 	// Menjalankan query SELECT untuk mendapatkan semua baris dari tabel yang ditentukan.
-	rows, err := db.sqldb.Query(fmt.Sprintf(selectQuery, db.tableName))
+	rows, err := db.sqldb.Query(fmt.Sprintf(selectQuery, db.tableName), args...)
 	if err != nil {
-		return nil, err // Mengembalikan kesalahan jika query gagal dieksekusi.
+		return nil, backendErr(err) // Mengembalikan kesalahan jika query gagal dieksekusi.
 	}
 	defer rows.Close() // Menutup hasil setelah selesai digunakan.
 
@@ -195,9 +380,9 @@ func (db *database) FindALL() (*[]model, error) {
 	for rows.Next() {
 		r := model{} // Inisialisasi objek model untuk setiap baris.
 		// Memindai kolom hasil ke dalam objek model.
-		err := rows.Scan(&r.ID, &r.Key, &r.Value)
+		err := rows.Scan(&r.ID, &r.Key, &r.Value, &r.MaxAge, &r.CreateAt, &r.UpdateAt)
 		if err != nil {
-			return nil, err // Mengembalikan kesalahan jika proses pemindaian gagal.
+			return nil, backendErr(err) // Mengembalikan kesalahan jika proses pemindaian gagal.
 		}
 		// Menambahkan hasil pemindaian ke slice result.
 		result = append(result, r)
@@ -218,15 +403,25 @@ func (db *database) FindALL() (*[]model, error) {
 // Mengembalikan:
 //   - error: Kesalahan jika terjadi selama proses penghapusan.
 func (db *database) RemoveByKey(key string) error {
+	if db.wb != nil {
+		db.wb.enqueue(writeOp{key: key, remove: true})
+		return nil
+	}
+
 	db.mu.Lock()
 	defer db.mu.Unlock()
+	return backendErr(db.removeByKey(db.sqldb, key))
+}
 
+// removeByKey menjalankan query RemoveByKey menggunakan ex.
+// Fungsi ini harus dipanggil ketika db.mu sedang dipegang.
+func (db *database) removeByKey(ex execer, key string) error {
 	// Menyiapkan query untuk menghapus entri berdasarkan kunci
 	removeQuery := `
 		DELETE FROM %s 
 		WHERE key = ?;
 	`
-	_, err := db.sqldb.Exec(fmt.Sprintf(removeQuery, db.tableName), key)
+	_, err := ex.Exec(fmt.Sprintf(removeQuery, db.tableName), key)
 	if err != nil {
 		return err
 	}
@@ -241,16 +436,109 @@ func (db *database) RemoveByKey(key string) error {
 // Mengembalikan:
 //   - error: Kesalahan jika terjadi selama proses penghapusan.
 func (db *database) RemoveAll() error {
+	if db.wb != nil {
+		db.wb.enqueue(writeOp{all: true})
+		return nil
+	}
+
 	db.mu.Lock()
 	defer db.mu.Unlock()
+	return backendErr(db.removeAll(db.sqldb))
+}
 
+// removeAll menjalankan query RemoveAll menggunakan ex.
+// Fungsi ini harus dipanggil ketika db.mu sedang dipegang.
+func (db *database) removeAll(ex execer) error {
 	// Menyiapkan query untuk menghapus semua entri dari tabel
 	removeAllQuery := `
 		DELETE FROM %s;
 	`
-	_, err := db.sqldb.Exec(fmt.Sprintf(removeAllQuery, db.tableName))
+	_, err := ex.Exec(fmt.Sprintf(removeAllQuery, db.tableName))
 	if err != nil {
 		return err
 	}
 	return nil
 }
+
+// WithTx menjalankan fn di dalam satu transaksi database. Jika fn mengembalikan
+// kesalahan, transaksi dibatalkan sehingga tabel tetap seperti sebelum fn dipanggil.
+//
+// Parameter:
+//   - fn (func(*sql.Tx) error): Fungsi yang menjalankan query melalui transaksi.
+//
+// Mengembalikan:
+//   - error: Kesalahan dari fn, atau kesalahan jika transaksi gagal dimulai atau di-commit.
+func (db *database) WithTx(fn func(*sql.Tx) error) error {
+	db.mu.Lock()
+	defer db.mu.Unlock()
+
+	tx, err := db.sqldb.Begin()
+	if err != nil {
+		return backendErr(err)
+	}
+	if err := fn(tx); err != nil {
+		tx.Rollback()
+		return backendErr(err)
+	}
+	return backendErr(tx.Commit())
+}
+
+// backendErr membungkus kesalahan dari SQLite dengan ErrBackend agar pemanggil
+// dapat membedakannya dari kesalahan cache dengan errors.Is.
+//
+// Mengembalikan:
+//   - error: Kesalahan yang membungkus ErrBackend dan err, atau nil jika err nil.
+func backendErr(err error) error {
+	if err == nil {
+		return nil
+	}
+	return fmt.Errorf("%w: %w", ErrBackend, err)
+}
+
+// Compact menjalankan VACUUM pada database agar ruang dari data yang dihapus
+// dikembalikan ke sistem berkas. Perubahan yang diantrekan oleh Config.WriteBehind
+// ditulis terlebih dahulu. VACUUM menulis ulang seluruh file database, sehingga
+// Compact sebaiknya dipanggil di luar jam sibuk. Tanpa database, Compact tidak
+// melakukan apa pun.
+//
+// Mengembalikan:
+//   - error: Kesalahan yang membungkus ErrBackend jika VACUUM gagal.
+func (app *App) Compact() error {
+	// Close mengunci semua shard, sehingga read lock satu shard sudah
+	// mencegah Close menutup database selama Compact berjalan
+	s := app.shards[0]
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return app.compact()
+}
+
+// Compact menjalankan App.Compact pada instance global yang dibuat oleh New.
+func Compact() error {
+	return app.Compact()
+}
+
+// compact adalah implementasi Compact. Fungsi ini harus dipanggil ketika
+// lock salah satu shard sedang dipegang, minimal dengan read lock.
+func (app *App) compact() error {
+	if app.db == nil {
+		return nil
+	}
+	if app.db.wb != nil {
+		if err := app.db.wb.flush(); err != nil {
+			return err
+		}
+	}
+	return app.db.vacuum()
+}
+
+// vacuum menjalankan VACUUM langsung pada koneksi database, karena VACUUM
+// tidak dapat dijalankan di dalam transaksi.
+//
+// Mengembalikan:
+//   - error: Kesalahan jika VACUUM gagal.
+func (db *database) vacuum() error {
+	db.mu.Lock()
+	defer db.mu.Unlock()
+	_, err := db.sqldb.Exec(`VACUUM;`)
+	return backendErr(err)
+}
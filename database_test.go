@@ -6,11 +6,22 @@
 package cago_test
 
 import (
+	"bytes"
+	"database/sql"
+	"errors"
 	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 
 	"github.com/jasakode/cago"
+	"github.com/jasakode/cago/store"
+	_ "github.com/mattn/go-sqlite3"
 )
 
 func TestDbConnection(t *testing.T) {
@@ -46,3 +57,616 @@ func TestDbConnection(t *testing.T) {
 		fmt.Println("Data Not Found !!!")
 	}
 }
+
+// seedRaw menyimpan blob apa adanya langsung ke tabel database,
+// seolah-olah data tersebut ditulis oleh versi cago sebelumnya atau rusak di disk.
+func seedRaw(t *testing.T, path string, key string, blob []byte) {
+	t.Helper()
+	if err := cago.New(cago.Config{Path: path}); err != nil {
+		t.Fatal(err)
+	}
+	db, err := sql.Open("sqlite3", path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+	if _, err := db.Exec(`INSERT INTO cagos (key, value) VALUES (?, ?);`, key, blob); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// seedLegacy menyimpan store dengan format versi lama langsung ke tabel database.
+// Format versi lama tidak memiliki byte versi, flag, maupun checksum.
+func seedLegacy(t *testing.T, path string, key string, value string) {
+	t.Helper()
+	legacy := store.NewStore([]byte(value))
+	legacy[store.VersionIndex] = 0
+	legacy[store.FlagsIndex] = 0
+	copy(legacy[store.ChecksumIndex:store.LengthIndex], make([]byte, 4))
+	seedRaw(t, path, key, legacy)
+}
+
+func TestLoadLegacyVersion(t *testing.T) {
+	// Mode lenient: store versi lama di-upgrade saat dimuat
+	path := filepath.Join(t.TempDir(), "lenient.db")
+	seedLegacy(t, path, "legacy", "old value")
+	if err := cago.New(cago.Config{Path: path}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	rs := cago.Get[string]("legacy")
+	if rs == nil || *rs != "old value" {
+		t.Errorf("expected %q, got %v", "old value", rs)
+	}
+
+	// Mode strict: store versi lama ditolak
+	path = filepath.Join(t.TempDir(), "strict.db")
+	seedLegacy(t, path, "legacy", "old value")
+	err := cago.New(cago.Config{Path: path, StrictVersion: true})
+	if !errors.Is(err, cago.ErrUnsupportedVersion) {
+		t.Errorf("expected ErrUnsupportedVersion, got %v", err)
+	}
+
+	// Store dari versi yang lebih baru selalu ditolak, bahkan dalam mode lenient
+	path = filepath.Join(t.TempDir(), "future.db")
+	future := store.NewStore([]byte("new value"))
+	future[store.VersionIndex] = store.CurrentVersion + 1
+	seedRaw(t, path, "future", future)
+	err = cago.New(cago.Config{Path: path})
+	if !errors.Is(err, cago.ErrUnsupportedVersion) {
+		t.Errorf("expected ErrUnsupportedVersion, got %v", err)
+	}
+}
+
+func TestLoadCorrupted(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "corrupted.db")
+	flipped := store.NewStore([]byte("payload"))
+	flipped[store.DataStartIndex] ^= 0xff
+	seedRaw(t, path, "flipped", flipped)
+	seedRaw(t, path, "truncated", store.NewStore([]byte("payload"))[:store.DataStartIndex+3])
+	seedRaw(t, path, "valid", store.NewStore([]byte("payload")))
+
+	var buf bytes.Buffer
+	if err := cago.New(cago.Config{Path: path, Logger: log.New(&buf, "", 0)}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if cago.Exist("flipped") || cago.Exist("truncated") {
+		t.Error("expected corrupted blobs to be skipped")
+	}
+	if rs := cago.Get[string]("valid"); rs == nil || *rs != "payload" {
+		t.Errorf("expected valid blob to load, got %v", rs)
+	}
+	if !strings.Contains(buf.String(), "flipped") || !strings.Contains(buf.String(), "truncated") {
+		t.Errorf("expected corrupted keys to be logged, got %q", buf.String())
+	}
+}
+
+func TestSchemaMigration(t *testing.T) {
+	// Membuat tabel dengan skema lama yang hanya memiliki key dan value
+	path := filepath.Join(t.TempDir(), "migrate.db")
+	db, err := sql.Open("sqlite3", path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+	if _, err := db.Exec(`CREATE TABLE cagos (id INTEGER PRIMARY KEY AUTOINCREMENT, key TEXT NOT NULL UNIQUE, value BLOB);`); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := db.Exec(`INSERT INTO cagos (key, value) VALUES (?, ?);`, "old", []byte(store.NewStore([]byte("kept")))); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := cago.New(cago.Config{Path: path}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if rs := cago.Get[string]("old"); rs == nil || *rs != "kept" {
+		t.Errorf("expected row from old schema to load, got %v", rs)
+	}
+
+	// Metadata store disalin ke kolom baru
+	if err := cago.Set("session", "abc", 60000); err != nil {
+		t.Fatal(err)
+	}
+	var maxAge, createAt uint64
+	row := db.QueryRow(`SELECT max_age, create_at FROM cagos WHERE key = ?;`, "session")
+	if err := row.Scan(&maxAge, &createAt); err != nil {
+		t.Fatal(err)
+	}
+	if maxAge != 60000 || createAt == 0 {
+		t.Errorf("expected max_age 60000 and create_at set, got %d and %d", maxAge, createAt)
+	}
+
+	// Baris yang sudah kedaluwarsa menurut kolom metadata tidak dimuat
+	if _, err := db.Exec(`UPDATE cagos SET create_at = 1 WHERE key = ?;`, "session"); err != nil {
+		t.Fatal(err)
+	}
+	if err := cago.New(cago.Config{Path: path}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if cago.Exist("session") {
+		t.Error("expected expired row to be filtered on load")
+	}
+}
+
+func TestLoadSkipsExpired(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "expired.db")
+	seedRaw(t, path, "expired", store.NewStoreAt([]byte("stale"), 1_700_000_000_000, 1))
+	seedRaw(t, path, "live", store.NewStoreAt([]byte("fresh"), 1_700_000_000_000, 60000))
+
+	var now atomic.Int64
+	now.Store(1_700_000_000_001)
+	if err := cago.New(cago.Config{Path: path, Clock: now.Load}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if cago.Exist("expired") {
+		t.Error("expected expired blob not to be loaded")
+	}
+	if !cago.Exist("live") {
+		t.Error("expected live blob to be loaded")
+	}
+
+	// Baris yang kedaluwarsa juga dihapus dari database
+	db, err := sql.Open("sqlite3", path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+	var count int
+	if err := db.QueryRow(`SELECT COUNT(*) FROM cagos WHERE key = ?;`, "expired").Scan(&count); err != nil {
+		t.Fatal(err)
+	}
+	if count != 0 {
+		t.Error("expected expired row to be deleted from the database")
+	}
+}
+
+func TestGetFresh(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "fresh.db")
+	var now atomic.Int64
+	now.Store(1_700_000_000_000)
+	if err := cago.New(cago.Config{Path: path, Clock: now.Load}); err != nil {
+		t.Fatal(err)
+	}
+	defer cago.Close()
+
+	// Koneksi kedua mewakili proses lain yang berbagi file yang sama
+	db, err := sql.Open("sqlite3", path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+	insert := func(key string, blob store.Store) {
+		t.Helper()
+		if _, err := db.Exec(`INSERT INTO cagos (key, value, max_age, create_at) VALUES (?, ?, ?, ?);`,
+			key, []byte(blob), blob.MaxAge(), blob.CreateAt()); err != nil {
+			t.Fatal(err)
+		}
+	}
+	insert("shared", store.NewStoreAt([]byte("from another process"), uint64(now.Load()), 60000).SetKind(store.KindString))
+	insert("stale", store.NewStoreAt([]byte("old"), uint64(now.Load()), 1))
+	now.Add(1)
+
+	if cago.Exist("shared") {
+		t.Fatal("expected shared to be missing from memory")
+	}
+	if v, ok := cago.GetFresh[string]("shared"); !ok || v != "from another process" {
+		t.Errorf("expected row from the database, got %q (ok=%v)", v, ok)
+	}
+	// Baris yang ditemukan dimuat ke memori sehingga Get biasa dapat membacanya
+	if rs := cago.Get[string]("shared"); rs == nil || *rs != "from another process" {
+		t.Errorf("expected shared to be cached, got %v", rs)
+	}
+	if _, ok := cago.GetFresh[string]("stale"); ok || cago.Exist("stale") {
+		t.Error("expected expired row not to be loaded")
+	}
+	if _, ok := cago.GetFresh[string]("missing"); ok {
+		t.Error("expected missing key not to be found")
+	}
+	if _, ok := cago.GetFresh[int]("shared"); ok {
+		t.Error("expected type mismatch not to be found")
+	}
+}
+
+func TestLoadPaged(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "paged.db")
+	seedRaw(t, path, "key-0", store.NewStore([]byte("value")))
+	db, err := sql.Open("sqlite3", path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+	tx, err := db.Begin()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 1; i < 5000; i++ {
+		if _, err := tx.Exec(`INSERT INTO cagos (key, value) VALUES (?, ?);`, fmt.Sprint("key-", i), store.NewStore([]byte("value"))); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		t.Fatal(err)
+	}
+
+	// Tanpa batas, seluruh halaman dimuat
+	c, err := cago.NewCache(cago.Config{Path: path})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n := c.Stats().Entries; n != 5000 {
+		t.Errorf("expected 5000 entries, got %d", n)
+	}
+	c.Close()
+
+	// Dengan MaxEntries, pemuatan berhenti tanpa menghapus baris yang tersisa
+	c, err = cago.NewCache(cago.Config{Path: path, MaxEntries: 1500})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n := c.Stats().Entries; n != 1500 {
+		t.Errorf("expected 1500 entries, got %d", n)
+	}
+	if n := c.Stats().Evictions; n != 0 {
+		t.Errorf("expected no evictions during load, got %d", n)
+	}
+	c.Close()
+
+	// MAX_MEM juga dipatuhi saat memuat, walaupun EvictOldestOnMaxMem tidak aktif
+	entry := len("key-0") + len(store.NewStore([]byte("value")))
+	c, err = cago.NewCache(cago.Config{Path: path, MAX_MEM: uint(100*entry) * 8})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n := c.Stats().Entries; n == 0 || n > 100 {
+		t.Errorf("expected at most 100 entries, got %d", n)
+	}
+	c.Close()
+
+	var count int
+	if err := db.QueryRow(`SELECT COUNT(*) FROM cagos;`).Scan(&count); err != nil {
+		t.Fatal(err)
+	}
+	if count != 5000 {
+		t.Errorf("expected 5000 rows to be kept in the database, got %d", count)
+	}
+}
+
+func TestSinglePersistPerWrite(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "count.db")
+	if err := cago.New(cago.Config{Path: path}); err != nil {
+		t.Fatal(err)
+	}
+
+	// Trigger SQLite menghitung setiap penulisan ke tabel cagos
+	db, err := sql.Open("sqlite3", path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+	for _, q := range []string{
+		`CREATE TABLE writes (n INTEGER);`,
+		`INSERT INTO writes VALUES (0);`,
+		`CREATE TRIGGER count_insert AFTER INSERT ON cagos BEGIN UPDATE writes SET n = n + 1; END;`,
+		`CREATE TRIGGER count_update AFTER UPDATE ON cagos BEGIN UPDATE writes SET n = n + 1; END;`,
+	} {
+		if _, err := db.Exec(q); err != nil {
+			t.Fatal(err)
+		}
+	}
+	writes := func() int {
+		var n int
+		if err := db.QueryRow(`SELECT n FROM writes;`).Scan(&n); err != nil {
+			t.Fatal(err)
+		}
+		return n
+	}
+
+	values := []store.Compare{"text", 42, int8(-1), uint64(7), true, 3.14, []byte{1, 2}, map[string]int{"a": 1}}
+	for i, value := range values {
+		before := writes()
+		if err := cago.Set(fmt.Sprintf("set-%d", i), value); err != nil {
+			t.Fatal(err)
+		}
+		if n := writes() - before; n != 1 {
+			t.Errorf("Set(%T) wrote %d times; expected 1", value, n)
+		}
+		before = writes()
+		if err := cago.Put(fmt.Sprintf("set-%d", i), value); err != nil {
+			t.Fatal(err)
+		}
+		if n := writes() - before; n != 1 {
+			t.Errorf("Put(%T) wrote %d times; expected 1", value, n)
+		}
+	}
+}
+
+func TestSQLiteWAL(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "wal.db")
+	c, err := cago.NewCache(cago.Config{Path: path, SQLiteWAL: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer c.Close()
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			for j := 0; j < 50; j++ {
+				if err := c.Put(fmt.Sprintf("key-%d-%d", i, j), j); err != nil {
+					t.Error(err)
+					return
+				}
+			}
+		}(i)
+	}
+
+	// Koneksi lain ke file yang sama menulis bersamaan dengan cache
+	db, err := sql.Open("sqlite3", path+"?_busy_timeout=5000")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+	var mode string
+	if err := db.QueryRow(`PRAGMA journal_mode;`).Scan(&mode); err != nil {
+		t.Fatal(err)
+	}
+	if mode != "wal" {
+		t.Errorf("expected journal_mode wal, got %s", mode)
+	}
+	for j := 0; j < 50; j++ {
+		if _, err := db.Exec(`INSERT INTO cagos (key, value) VALUES (?, ?);`, fmt.Sprintf("external-%d", j), []byte{}); err != nil {
+			t.Error(err)
+			break
+		}
+	}
+	wg.Wait()
+}
+
+func TestTableName(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "tables.db")
+	a, err := cago.NewCache(cago.Config{Path: path, TableName: "cache_a"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, err := cago.NewCache(cago.Config{Path: path, TableName: "cache_b"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := a.Put("key", "from a"); err != nil {
+		t.Fatal(err)
+	}
+	if err := b.Put("key", "from b"); err != nil {
+		t.Fatal(err)
+	}
+	if err := b.Put("only-b", "b"); err != nil {
+		t.Fatal(err)
+	}
+	a.Close()
+	b.Close()
+
+	a, err = cago.NewCache(cago.Config{Path: path, TableName: "cache_a"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer a.Close()
+	b, err = cago.NewCache(cago.Config{Path: path, TableName: "cache_b"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer b.Close()
+
+	if v := cago.GetFrom[string](a, "key"); v == nil || *v != "from a" {
+		t.Errorf("expected from a, got %v", v)
+	}
+	if v := cago.GetFrom[string](b, "key"); v == nil || *v != "from b" {
+		t.Errorf("expected from b, got %v", v)
+	}
+	if a.Exist("only-b") {
+		t.Error("expected only-b to be absent from cache_a")
+	}
+
+	for _, name := range []string{"cagos; DROP TABLE cagos", "1abc", "a-b", `a"b`} {
+		_, err := cago.NewCache(cago.Config{Path: path, TableName: name})
+		if !errors.Is(err, cago.ErrInvalidTableName) {
+			t.Errorf("table name %q: expected ErrInvalidTableName, got %v", name, err)
+		}
+	}
+}
+
+func TestBatchWriteRollback(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "rollback.db")
+	if err := cago.New(cago.Config{Path: path}); err != nil {
+		t.Fatal(err)
+	}
+	defer cago.Close()
+	if err := cago.Put("keep", "original"); err != nil {
+		t.Fatal(err)
+	}
+
+	// Trigger SQLite menggagalkan penulisan key "bad" di tengah batch
+	db, err := sql.Open("sqlite3", path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+	if _, err := db.Exec(`CREATE TRIGGER reject_bad BEFORE INSERT ON cagos WHEN NEW.key = 'bad' BEGIN SELECT RAISE(ABORT, 'rejected'); END;`); err != nil {
+		t.Fatal(err)
+	}
+
+	items := map[string]store.Compare{"bad": "x"}
+	for i := 0; i < 20; i++ {
+		items[fmt.Sprintf("key-%d", i)] = i
+	}
+	if err := cago.MSet(items); err == nil {
+		t.Fatal("expected MSet to fail")
+	}
+	if n := countRows(t, path); n != 1 {
+		t.Errorf("expected table to keep 1 row after rollback, got %d", n)
+	}
+	var count int
+	if err := db.QueryRow(`SELECT COUNT(*) FROM cagos WHERE key = 'keep';`).Scan(&count); err != nil {
+		t.Fatal(err)
+	}
+	if count != 1 {
+		t.Error("expected key keep to survive the rollback")
+	}
+}
+
+func TestInvalidPath(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "missing", "nested")
+	path := filepath.Join(dir, "cache.db")
+
+	_, err := cago.NewCache(cago.Config{Path: path})
+	if err == nil || !strings.Contains(err.Error(), dir) {
+		t.Errorf("expected an error naming %s, got %v", dir, err)
+	}
+
+	c, err := cago.NewCache(cago.Config{Path: path, CreateDir: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer c.Close()
+	if err := c.Put("key", "value"); err != nil {
+		t.Fatal(err)
+	}
+	if n := countRows(t, path); n != 1 {
+		t.Errorf("expected 1 row in the new database, got %d", n)
+	}
+}
+
+func TestWriteThrough(t *testing.T) {
+	for _, writeThrough := range []bool{false, true} {
+		path := filepath.Join(t.TempDir(), "through.db")
+		c, err := cago.NewCache(cago.Config{Path: path, WriteThrough: writeThrough})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := c.Put("existing", "old"); err != nil {
+			t.Fatal(err)
+		}
+
+		// Trigger SQLite menolak setiap penulisan
+		db, err := sql.Open("sqlite3", path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, q := range []string{
+			`CREATE TRIGGER reject_update BEFORE UPDATE ON cagos BEGIN SELECT RAISE(ABORT, 'rejected'); END;`,
+			`CREATE TRIGGER reject_insert BEFORE INSERT ON cagos BEGIN SELECT RAISE(ABORT, 'rejected'); END;`,
+		} {
+			if _, err := db.Exec(q); err != nil {
+				t.Fatal(err)
+			}
+		}
+		db.Close()
+
+		if err := c.Put("fresh", "value"); err == nil {
+			t.Fatal("expected Put to fail")
+		}
+		if err := c.Put("existing", "new"); err == nil {
+			t.Fatal("expected Put to fail")
+		}
+		if writeThrough {
+			if c.Exist("fresh") {
+				t.Error("expected fresh to be absent after a failed write-through Put")
+			}
+			if v := cago.GetFrom[string](c, "existing"); v == nil || *v != "old" {
+				t.Errorf("expected existing to keep old, got %v", v)
+			}
+		} else if !c.Exist("fresh") {
+			t.Error("expected memory-first Put to keep fresh in memory")
+		}
+		c.Close()
+	}
+
+	_, err := cago.NewCache(cago.Config{WriteThrough: true, WriteBehind: true})
+	if !errors.Is(err, cago.ErrWriteMode) {
+		t.Errorf("expected ErrWriteMode, got %v", err)
+	}
+}
+
+func TestErrorKinds(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "errors.db")
+	c, err := cago.NewCache(cago.Config{Path: path})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer c.Close()
+	if err := cago.New(); err != nil {
+		t.Fatal(err)
+	}
+
+	// Key yang tidak ada dan isi yang tidak sesuai tipe dapat dibedakan
+	if _, err := cago.GetErr[string]("missing"); !errors.Is(err, cago.ErrNotFound) {
+		t.Errorf("expected ErrNotFound, got %v", err)
+	}
+	cago.Set("person", "not a json object")
+	if _, err := cago.GetErr[Person]("person"); !errors.Is(err, cago.ErrTypeMismatch) || errors.Is(err, cago.ErrNotFound) {
+		t.Errorf("expected ErrTypeMismatch, got %v", err)
+	}
+	cago.Set("count", 1)
+	if err := cago.Update("count", func(old []string, existed bool) ([]string, uint64) { return old, 0 }); !errors.Is(err, cago.ErrTypeMismatch) {
+		t.Errorf("expected ErrTypeMismatch from Update, got %v", err)
+	}
+
+	// Kegagalan SQLite dibungkus dengan ErrBackend
+	db, err := sql.Open("sqlite3", path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+	if _, err := db.Exec(`DROP TABLE cagos;`); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.Put("key", "value"); !errors.Is(err, cago.ErrBackend) {
+		t.Errorf("expected ErrBackend, got %v", err)
+	}
+	if _, err := cago.NewCache(cago.Config{Path: t.TempDir()}); !errors.Is(err, cago.ErrBackend) {
+		t.Errorf("expected ErrBackend when the path is a directory, got %v", err)
+	}
+}
+
+func TestCompact(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "compact.db")
+	c, err := cago.NewCache(cago.Config{Path: path, VacuumOnClose: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	value := strings.Repeat("x", 4096)
+	for i := 0; i < 200; i++ {
+		if err := c.Put(fmt.Sprint("key-", i), value); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if n := c.RemovePrefix("key-"); n != 200 {
+		t.Fatalf("expected 200 removed, got %d", n)
+	}
+	before, err := os.Stat(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := c.Compact(); err != nil {
+		t.Errorf("unexpected error from Compact: %v", err)
+	}
+	after, err := os.Stat(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if after.Size() >= before.Size() {
+		t.Errorf("expected file to shrink, got %d bytes before and %d after", before.Size(), after.Size())
+	}
+	if err := c.Close(); err != nil {
+		t.Errorf("unexpected error from Close with VacuumOnClose: %v", err)
+	}
+
+	// Tanpa database, Compact tidak melakukan apa pun
+	memory, err := cago.NewCache()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer memory.Close()
+	if err := memory.Compact(); err != nil {
+		t.Errorf("expected no error without a database, got %v", err)
+	}
+}
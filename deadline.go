@@ -0,0 +1,96 @@
+// Copyright (c) 2024, Jasakode Authors.
+// All rights reserved.
+// Use of this source code is governed by a BSD 3-Clause
+// license that can be found in the LICENSE file.
+
+package cago
+
+import (
+	"time"
+
+	"github.com/jasakode/cago/store"
+)
+
+// SetAt menyimpan nilai baru seperti Set, tetapi masa berlakunya ditentukan oleh
+// waktu tenggat dan bukan oleh durasi, misalnya untuk nilai yang harus kedaluwarsa
+// pada akhir hari. Config.TTLJitter tidak diterapkan agar tenggat tetap tepat.
+// Tenggat yang tidak lebih lambat dari saat ini berarti nilai langsung kedaluwarsa,
+// sehingga nilai tidak disimpan dan SetAt mengembalikan nil.
+//
+// Parameter:
+//   - key (string): Key unik yang digunakan untuk mengidentifikasi nilai dalam store.
+//   - value (store.Compare): Nilai yang akan disimpan.
+//   - deadline (time.Time): Waktu ketika nilai kedaluwarsa.
+//
+// Mengembalikan:
+//   - error: ErrKeyExists jika key sudah ada dan belum kedaluwarsa, atau kesalahan lain seperti pada Set.
+func (app *App) SetAt(key string, value store.Compare, deadline time.Time) error {
+	return app.storeAt(key, value, deadline, false)
+}
+
+// SetAt menjalankan App.SetAt pada instance global yang dibuat oleh New.
+func SetAt(key string, value store.Compare, deadline time.Time) error {
+	return app.SetAt(key, value, deadline)
+}
+
+// PutAt menyimpan atau menimpa nilai seperti Put, dengan masa berlaku yang
+// ditentukan oleh waktu tenggat seperti SetAt. Tenggat yang tidak lebih lambat
+// dari saat ini berarti nilai langsung kedaluwarsa, sehingga nilai lama dengan
+// key yang sama dihapus dan tidak ada nilai baru yang disimpan.
+//
+// Parameter:
+//   - key (string): Key unik yang digunakan untuk mengidentifikasi nilai dalam store.
+//   - value (store.Compare): Nilai yang akan disimpan.
+//   - deadline (time.Time): Waktu ketika nilai kedaluwarsa.
+//
+// Mengembalikan:
+//   - error: Kesalahan seperti pada Put.
+func (app *App) PutAt(key string, value store.Compare, deadline time.Time) error {
+	return app.storeAt(key, value, deadline, true)
+}
+
+// PutAt menjalankan App.PutAt pada instance global yang dibuat oleh New.
+func PutAt(key string, value store.Compare, deadline time.Time) error {
+	return app.PutAt(key, value, deadline)
+}
+
+// storeAt adalah implementasi SetAt dan PutAt. maxAge dihitung dari waktu
+// yang sama dengan CreateAt sehingga CreateAt + maxAge tepat sama dengan tenggat.
+func (app *App) storeAt(key string, value store.Compare, deadline time.Time, overwrite bool) error {
+	if err := app.checkKey(key); err != nil {
+		return err
+	}
+	now := app.now()
+	at := deadline.UnixMilli()
+	maxAge := uint64(0)
+	if at > int64(now) {
+		maxAge = uint64(at) - now
+	}
+	// Nilai tetap dikonversi agar kesalahan tipe dan ukuran dilaporkan seperti Set
+	data, err := app.encodeAt(key, value, now, []uint64{maxAge})
+	if err != nil {
+		return err
+	}
+
+	defer app.dispatch()
+	s := app.shardFor(key)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	old, ok := s.data[key]
+	stale := ok && app.expiredEntry(key, old, app.now())
+	if ok && !stale && !overwrite {
+		return ErrKeyExists
+	}
+	if maxAge == 0 {
+		reason := EvictRemoved
+		if stale {
+			reason = EvictExpired
+		}
+		app.deleteEntry(key, reason)
+		if app.db != nil {
+			return app.db.RemoveByKey(key)
+		}
+		return nil
+	}
+	return app.persist(key, data)
+}
@@ -0,0 +1,75 @@
+// Copyright (c) 2024, Jasakode Authors.
+// All rights reserved.
+// Use of this source code is governed by a BSD 3-Clause
+// license that can be found in the LICENSE file.
+
+package cago_test
+
+import (
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/jasakode/cago"
+)
+
+func TestSetAt(t *testing.T) {
+	var now atomic.Int64
+	now.Store(time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC).UnixMilli())
+	c, err := cago.NewCache(cago.Config{TimeoutCheck: 60000, Clock: now.Load, TTLJitter: 10000})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer c.Close()
+
+	// Tenggat di masa depan dipakai apa adanya, tanpa jitter
+	endOfDay := time.Date(2024, 5, 2, 0, 0, 0, 0, time.UTC)
+	if err := c.SetAt("report", "daily", endOfDay); err != nil {
+		t.Fatal(err)
+	}
+	if _, _, expires, _ := c.GetMeta("report"); !expires.Equal(endOfDay) {
+		t.Errorf("expected expiry at %v, got %v", endOfDay, expires)
+	}
+	if err := c.SetAt("report", "again", endOfDay); !errors.Is(err, cago.ErrKeyExists) {
+		t.Errorf("expected ErrKeyExists, got %v", err)
+	}
+	now.Store(endOfDay.UnixMilli() - 1)
+	if !c.Exist("report") {
+		t.Error("expected report to be kept before its deadline")
+	}
+	now.Store(endOfDay.UnixMilli())
+	if c.Exist("report") {
+		t.Error("expected report to expire at its deadline")
+	}
+
+	// Entri yang kedaluwarsa tetapi belum dibersihkan dianggap tidak ada
+	tomorrow := endOfDay.Add(24 * time.Hour)
+	if err := c.SetAt("report", "next", tomorrow); err != nil {
+		t.Errorf("expected SetAt to replace an expired entry, got %v", err)
+	}
+	if v, _ := c.GetString("report"); v != "next" {
+		t.Errorf("expected next, got %q", v)
+	}
+	c.Put("stale", "old", 1)
+	now.Add(20000) // Lebih lama dari maxAge ditambah TTLJitter
+
+	if err := c.Set("stale", "new"); err != nil {
+		t.Errorf("expected Set to replace an expired entry, got %v", err)
+	}
+
+	// Tenggat yang sudah lewat tidak menyimpan apa pun, dan PutAt menghapus nilai lama
+	if err := c.SetAt("late", "x", endOfDay.Add(-time.Hour)); err != nil {
+		t.Fatal(err)
+	}
+	if c.Exist("late") {
+		t.Error("expected value with a past deadline not to be stored")
+	}
+	c.Put("session", "token")
+	if err := c.PutAt("session", "token", endOfDay); err != nil {
+		t.Fatal(err)
+	}
+	if c.Exist("session") {
+		t.Error("expected PutAt with a past deadline to remove the old value")
+	}
+}
@@ -0,0 +1,113 @@
+// Copyright (c) 2024, Jasakode Authors.
+// All rights reserved.
+// Use of this source code is governed by a BSD 3-Clause
+// license that can be found in the LICENSE file.
+
+package cago
+
+import (
+	"errors"
+
+	"github.com/jasakode/cago/store"
+)
+
+// ErrDiffTooOld dikembalikan oleh DiffSince ketika catatan penghapusan sejak
+// waktu yang diminta sudah dibuang karena melewati DiffWindow.
+var ErrDiffTooOld = errors.New("diff window exceeded")
+
+// ChangeRecord merepresentasikan satu perubahan pada cache.
+//
+// Field-field:
+//   - Key: Key yang berubah.
+//   - Value: Store terbaru dari key, bernilai nil jika key dihapus.
+//   - Removed: True jika key telah dihapus.
+type ChangeRecord struct {
+	Key     string      `json:"key"`     // Key yang berubah.
+	Value   store.Store `json:"value"`   // Store terbaru, nil jika key dihapus.
+	Removed bool        `json:"removed"` // Menandakan key telah dihapus.
+}
+
+// DiffSince mengembalikan semua perubahan sejak waktu since (dalam milidetik).
+// Entri yang dibuat atau diperbarui sejak since dikembalikan bersama store-nya,
+// sedangkan key yang dihapus sejak since dikembalikan sebagai tombstone.
+// Hasilnya dapat diterapkan ke cache lain dengan ApplyDiff.
+//
+// Parameter:
+//   - since (uint64): Unix timestamp dalam milidetik.
+//
+// Mengembalikan:
+//   - []ChangeRecord: Daftar perubahan sejak since.
+//   - error: ErrDiffTooOld jika catatan penghapusan sejak since sudah tidak lengkap.
+func DiffSince(since uint64) ([]ChangeRecord, error) {
+	app.lockAll()
+	defer app.unlockAll()
+
+	now := app.now()
+	for _, s := range app.shards {
+		app.pruneRemoved(s, now)
+		if s.pruned > 0 && since <= s.pruned {
+			return nil, ErrDiffTooOld
+		}
+	}
+
+	records := []ChangeRecord{}
+	for _, s := range app.shards {
+		for key, data := range s.data {
+			if app.expiredEntry(key, data, now) {
+				continue
+			}
+			if data.CreateAt() >= since || data.UpdateAt() >= since {
+				records = append(records, ChangeRecord{Key: key, Value: data})
+			}
+		}
+		for key, at := range s.removed {
+			if at >= since {
+				records = append(records, ChangeRecord{Key: key, Removed: true})
+			}
+		}
+	}
+	return records, nil
+}
+
+// ApplyDiff menerapkan perubahan hasil DiffSince ke cache.
+// Store diterapkan apa adanya sehingga metadata seperti CreateAt dan MaxAge
+// tetap sama dengan cache sumber.
+//
+// Parameter:
+//   - records ([]ChangeRecord): Daftar perubahan yang akan diterapkan.
+//
+// Mengembalikan:
+//   - error: Kesalahan jika perubahan gagal disimpan ke database.
+func ApplyDiff(records []ChangeRecord) error {
+	defer app.dispatch()
+	app.lockAll()
+	defer app.unlockAll()
+
+	for _, r := range records {
+		if r.Removed {
+			app.deleteEntry(r.Key, EvictRemoved)
+			continue
+		}
+		data := store.ParseStore(r.Value)
+		if len(data) == 0 {
+			continue
+		}
+		if err := app.setEntry(r.Key, append(store.Store{}, data...)); err != nil {
+			return err
+		}
+	}
+	if app.db != nil {
+		for _, r := range records {
+			if r.Removed {
+				if err := app.db.RemoveByKey(r.Key); err != nil {
+					return err
+				}
+			} else if data, ok := app.shardFor(r.Key).data[r.Key]; ok {
+				if err := app.db.InsertOrUpdate(r.Key, data); err != nil {
+					return err
+				}
+			}
+		}
+	}
+	return nil
+}
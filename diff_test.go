@@ -0,0 +1,112 @@
+// Copyright (c) 2024, Jasakode Authors.
+// All rights reserved.
+// Use of this source code is governed by a BSD 3-Clause
+// license that can be found in the LICENSE file.
+
+package cago_test
+
+import (
+	"errors"
+	"sync/atomic"
+	"testing"
+
+	"github.com/jasakode/cago"
+)
+
+func TestDiffSince(t *testing.T) {
+	// Cache sumber
+	var now atomic.Int64
+	now.Store(1_700_000_000_000)
+	if err := cago.New(cago.Config{Clock: now.Load}); err != nil {
+		t.Fatal(err)
+	}
+	cago.Set("unchanged", "same")
+	cago.Set("removed", "gone")
+	// Salin kondisi awal sebagai titik sinkronisasi
+	base, err := cago.DiffSince(0)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	now.Add(2)
+	since := uint64(now.Load())
+	cago.Put("changed", "new value")
+	cago.Remove("removed")
+
+	records, err := cago.DiffSince(since)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(records) != 2 {
+		t.Fatalf("expected 2 records, got %d", len(records))
+	}
+
+	// Cache tujuan menerima kondisi awal lalu diff
+	if err := cago.New(); err != nil {
+		t.Fatal(err)
+	}
+	if err := cago.ApplyDiff(base); err != nil {
+		t.Fatal(err)
+	}
+	if err := cago.ApplyDiff(records); err != nil {
+		t.Fatal(err)
+	}
+	if rs := cago.Get[string]("changed"); rs == nil || *rs != "new value" {
+		t.Errorf("expected changed to be synced, got %v", rs)
+	}
+	if rs := cago.Get[string]("unchanged"); rs == nil || *rs != "same" {
+		t.Errorf("expected unchanged to be kept, got %v", rs)
+	}
+	if cago.Exist("removed") {
+		t.Error("expected removed to be deleted")
+	}
+}
+
+func TestDiffSinceRecreated(t *testing.T) {
+	var now atomic.Int64
+	now.Store(1_700_000_000_000)
+	if err := cago.New(cago.Config{Clock: now.Load}); err != nil {
+		t.Fatal(err)
+	}
+	since := uint64(now.Load())
+	cago.Set("a", "first")
+	now.Add(1)
+	cago.Remove("a")
+	now.Add(1)
+	cago.Set("a", "second")
+
+	records, err := cago.DiffSince(since)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(records) != 1 || records[0].Removed {
+		t.Fatalf("expected only the live value of a, got %+v", records)
+	}
+
+	if err := cago.New(); err != nil {
+		t.Fatal(err)
+	}
+	if err := cago.ApplyDiff(records); err != nil {
+		t.Fatal(err)
+	}
+	if rs := cago.Get[string]("a"); rs == nil || *rs != "second" {
+		t.Errorf("expected a to be live after ApplyDiff, got %v", rs)
+	}
+}
+
+func TestDiffSinceWindow(t *testing.T) {
+	var now atomic.Int64
+	now.Store(1_700_000_000_000)
+	if err := cago.New(cago.Config{DiffWindow: 1, TimeoutCheck: 60000, Clock: now.Load}); err != nil {
+		t.Fatal(err)
+	}
+	since := uint64(now.Load())
+	cago.Set("key", "value")
+	cago.Remove("key")
+	now.Add(2)
+
+	// Catatan penghapusan sudah dibuang sehingga diff tidak lengkap
+	if _, err := cago.DiffSince(since); !errors.Is(err, cago.ErrDiffTooOld) {
+		t.Errorf("expected ErrDiffTooOld, got %v", err)
+	}
+}
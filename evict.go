@@ -0,0 +1,388 @@
+// Copyright (c) 2024, Jasakode Authors.
+// All rights reserved.
+// Use of this source code is governed by a BSD 3-Clause
+// license that can be found in the LICENSE file.
+
+package cago
+
+import (
+	"math/rand"
+	"sync"
+	"sync/atomic"
+
+	"github.com/jasakode/cago/store"
+)
+
+// EvictionPolicy menentukan entri mana yang dihapus ketika MaxEntries terlampaui.
+type EvictionPolicy int
+
+const (
+	// PolicyNone menghapus entri dengan CreateAt paling lama.
+	PolicyNone EvictionPolicy = iota
+	// PolicyLRU menghapus entri yang paling lama tidak diakses (least recently used).
+	PolicyLRU
+	// PolicyRandom menghapus entri acak dari sampel berukuran EvictionSampleSize,
+	// dengan peluang lebih besar bagi entri yang paling dekat dengan waktu kedaluwarsa.
+	// Kebijakan ini tidak membutuhkan pencatatan akses sehingga paling ringan.
+	PolicyRandom
+	// PolicyLFU menghapus entri yang paling jarang diakses (least frequently used),
+	// dan entri dengan CreateAt paling lama jika jumlah aksesnya sama. Jumlah akses
+	// berkurang setengah secara berkala agar key yang dulu populer tidak bertahan
+	// selamanya dan key baru tetap berkesempatan menetap, lihat lfuDecayEvery.
+	PolicyLFU
+)
+
+// lfuDecayEvery menentukan seberapa sering jumlah akses PolicyLFU dibagi dua:
+// setiap kali jumlah akses sejak peluruhan terakhir mencapai lfuDecayEvery
+// kali jumlah entri di dalam cache.
+const lfuDecayEvery = 16
+
+// victim memilih key di dalam shard s yang akan dihapus sesuai EvictionPolicy.
+// Fungsi ini harus dipanggil ketika write lock s sedang dipegang dan s tidak kosong.
+func (app *App) victim(s *shard) string {
+	switch app.config.EvictionPolicy {
+	case PolicyRandom:
+	case PolicyLFU:
+		return app.leastFrequent(s)
+	default:
+		return s.order.Front().Value.(string)
+	}
+
+	// Urutan iterasi map di Go sudah diacak, sehingga beberapa key pertama
+	// dapat digunakan sebagai sampel tanpa biaya tambahan.
+	now := app.now()
+	keys := make([]string, 0, app.config.EvictionSampleSize)
+	weights := make([]float64, 0, app.config.EvictionSampleSize)
+	total := float64(0)
+	for key, data := range s.data {
+		// Entri tanpa MaxAge memiliki bobot terkecil
+		weight := float64(1) / float64(^uint64(0))
+		if data.MaxAge() != 0 {
+			remaining := uint64(0)
+			if deadline := app.deadline(key, data); deadline > now {
+				remaining = deadline - now
+			}
+			weight = 1 / float64(remaining+1)
+		}
+		keys = append(keys, key)
+		weights = append(weights, weight)
+		total += weight
+		if len(keys) == app.config.EvictionSampleSize {
+			break
+		}
+	}
+
+	pick := rand.Float64() * total
+	for i, weight := range weights {
+		if pick < weight {
+			return keys[i]
+		}
+		pick -= weight
+	}
+	return keys[len(keys)-1]
+}
+
+// leastFrequent mencari key di dalam shard s dengan jumlah akses paling sedikit
+// untuk PolicyLFU, dengan CreateAt paling lama sebagai penentu jika jumlahnya
+// sama, lalu urutan penyimpanan jika CreateAt juga sama. Semua entri shard
+// diperiksa sehingga biayanya sebanding dengan jumlah entri di dalam shard.
+// Fungsi ini harus dipanggil ketika write lock s sedang dipegang dan s tidak kosong.
+func (app *App) leastFrequent(s *shard) string {
+	var (
+		victim  string
+		count   uint64
+		created uint64
+	)
+	for el := s.order.Front(); el != nil; el = el.Next() {
+		key := el.Value.(string)
+		n, at := s.freq[key], s.data[key].CreateAt()
+		if el == s.order.Front() || n < count || (n == count && at < created) {
+			victim, count, created = key, n, at
+		}
+	}
+	return victim
+}
+
+// touch menandai key sebagai entri yang paling baru diakses.
+// Fungsi ini hanya berpengaruh ketika EvictionPolicy bernilai PolicyLRU atau
+// PolicyLFU, atau SlidingTTL aktif, dan harus dipanggil ketika write lock
+// shard dari key sedang dipegang.
+func (app *App) touch(key string) {
+	s := app.shardFor(key)
+	if app.config.SlidingTTL {
+		app.slide(s, key)
+	}
+	switch app.config.EvictionPolicy {
+	case PolicyLRU:
+		if el, ok := s.elems[key]; ok {
+			s.order.MoveToBack(el)
+		}
+	case PolicyLFU:
+		if _, ok := s.data[key]; !ok {
+			return
+		}
+		s.freq[key]++
+		s.accesses++
+		if s.accesses >= lfuDecayEvery*uint64(len(s.data)) {
+			s.decay()
+		}
+	}
+}
+
+// decay membagi dua jumlah akses semua key di dalam shard untuk PolicyLFU.
+// Fungsi ini harus dipanggil ketika write lock shard sedang dipegang.
+func (s *shard) decay() {
+	for key, n := range s.freq {
+		s.freq[key] = n / 2
+	}
+	s.accesses = 0
+}
+
+// slide mencatat waktu akses terakhir entri di s.access, sehingga entri
+// kedaluwarsa maxAge milidetik setelah akses terakhir, lihat deadline.
+// Store tidak diubah, sehingga CreateAt, DiffSince, dan database tidak terpengaruh.
+// Fungsi ini harus dipanggil ketika write lock s sedang dipegang.
+func (app *App) slide(s *shard, key string) {
+	if data, ok := s.data[key]; ok && data.MaxAge() != 0 {
+		s.access[key] = app.now()
+	}
+}
+
+// deadline mengembalikan waktu kedaluwarsa entri dalam milidetik, atau 0 jika
+// entri tidak pernah kedaluwarsa. Dengan SlidingTTL, masa berlaku dihitung dari
+// akses terakhir jika akses tersebut lebih baru dari CreateAt.
+// Fungsi ini harus dipanggil ketika lock shard dari key sedang dipegang, minimal dengan read lock.
+func (app *App) deadline(key string, v store.Store) uint64 {
+	if v.MaxAge() == 0 {
+		return 0
+	}
+	from := v.CreateAt()
+	if !app.config.SlidingTTL {
+		return from + v.MaxAge()
+	}
+	if at, ok := app.shardFor(key).access[key]; ok && at > from {
+		from = at
+	}
+	return from + v.MaxAge()
+}
+
+// expiredEntry memeriksa apakah entri di dalam cache sudah kedaluwarsa pada
+// waktu now, dengan memperhitungkan akses terakhir SlidingTTL. Store yang
+// belum masuk ke cache diperiksa dengan expired.
+// Fungsi ini harus dipanggil ketika lock shard dari key sedang dipegang, minimal dengan read lock.
+func (app *App) expiredEntry(key string, v store.Store, now uint64) bool {
+	if !app.config.SlidingTTL {
+		return expired(v, now)
+	}
+	if _, ok := app.shardFor(key).access[key]; !ok {
+		return expired(v, now)
+	}
+	return now >= app.deadline(key, v)
+}
+
+// writeOnRead melaporkan apakah jalur baca mengubah entri atau urutannya,
+// sehingga pembacaan harus memegang write lock dan bukan read lock.
+func (app *App) writeOnRead() bool {
+	return app.config.EvictionPolicy == PolicyLRU || app.config.EvictionPolicy == PolicyLFU || app.config.SlidingTTL
+}
+
+// EvictReason menjelaskan alasan sebuah entri keluar dari cache.
+type EvictReason int
+
+const (
+	// EvictExpired berarti entri dihapus karena MaxAge-nya telah lewat.
+	EvictExpired EvictReason = iota
+	// EvictCapacity berarti entri dihapus untuk memenuhi MaxEntries atau MAX_MEM.
+	EvictCapacity
+	// EvictRemoved berarti entri dihapus secara manual, misalnya melalui Remove atau Clear.
+	EvictRemoved
+)
+
+// eviction menyimpan data entri yang dihapus sampai callback-nya dijalankan.
+type eviction struct {
+	key    string      // Key yang dihapus.
+	value  store.Store // Store terakhir dari key.
+	reason EvictReason // Alasan penghapusan.
+}
+
+// notifyEvict mengantrekan callback untuk entri yang dihapus.
+// Tidak ada yang diantrekan jika OnExpire dan OnEvict tidak diatur.
+func (app *App) notifyEvict(key string, value store.Store, reason EvictReason) {
+	if app.config.OnEvict == nil && (app.config.OnExpire == nil || reason != EvictExpired) {
+		return
+	}
+	app.evmu.Lock()
+	app.evicted = append(app.evicted, eviction{key: key, value: value, reason: reason})
+	app.evmu.Unlock()
+}
+
+// dispatch menjalankan semua callback penghapusan yang sedang mengantre.
+// Fungsi ini harus dipanggil setelah lock shard dilepas agar callback dapat
+// memanggil fungsi cago lainnya tanpa menyebabkan deadlock.
+func (app *App) dispatch() {
+	app.evmu.Lock()
+	pending := app.evicted
+	app.evicted = nil
+	app.evmu.Unlock()
+
+	for _, e := range pending {
+		if e.reason == EvictExpired && app.config.OnExpire != nil {
+			app.config.OnExpire(e.key, e.value)
+		}
+		if app.config.OnEvict != nil {
+			app.config.OnEvict(e.key, e.value, e.reason)
+		}
+	}
+}
+
+// CleanStrategy menentukan cara proses latar belakang mencari entri yang kedaluwarsa.
+type CleanStrategy int
+
+const (
+	// CleanFullScan memeriksa semua entri pada setiap pemeriksaan.
+	CleanFullScan CleanStrategy = iota
+	// CleanSampled memeriksa CleanSampleSize entri acak di setiap shard, dan
+	// mengulanginya selama rasio entri kedaluwarsa di dalam sampel melebihi CleanExpiredRatio,
+	// seperti penghapusan aktif pada Redis. Entri kedaluwarsa yang belum
+	// terpilih akan dihapus pada pemeriksaan berikutnya.
+	CleanSampled
+)
+
+// maxCleanRounds membatasi jumlah putaran CleanSampled dalam satu pemeriksaan.
+const maxCleanRounds = 16
+
+// sweep menjalankan satu pemeriksaan entri kedaluwarsa sesuai Config.CleanStrategy.
+// Setiap shard diperiksa secara terpisah dengan hanya memegang lock shard tersebut,
+// oleh paling banyak Config.CleanWorkers worker secara bersamaan.
+//
+// Mengembalikan:
+//   - int: Jumlah entri yang dihapus.
+func (app *App) sweep(now uint64) int {
+	stale := app.takeStale()
+	workers := min(app.config.CleanWorkers, len(app.shards))
+	if workers <= 1 {
+		removed := 0
+		for _, s := range app.shards {
+			removed += app.sweepShard(s, stale[s], now)
+		}
+		return removed
+	}
+
+	// Worker mengambil shard berikutnya dari antrean sampai semua shard diperiksa
+	var removed atomic.Int64
+	var wg sync.WaitGroup
+	next := make(chan *shard)
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for s := range next {
+				removed.Add(int64(app.sweepShard(s, stale[s], now)))
+			}
+		}()
+	}
+	for _, s := range app.shards {
+		next <- s
+	}
+	close(next)
+	wg.Wait()
+	return int(removed.Load())
+}
+
+// sweepShard menjalankan pemeriksaan entri kedaluwarsa untuk shard s sesuai
+// Config.CleanStrategy. Key kedaluwarsa yang ditemukan oleh pembaca dihapus
+// terlebih dahulu. Hanya lock s yang diambil.
+//
+// Mengembalikan:
+//   - int: Jumlah entri yang dihapus.
+func (app *App) sweepShard(s *shard, stale []string, now uint64) int {
+	removed := app.removeStale(s, stale, now)
+	if app.config.CleanStrategy != CleanSampled {
+		return removed + app.cleanShard(s, now)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for round := 0; round < maxCleanRounds; round++ {
+		// Urutan iterasi map di Go sudah diacak sehingga dapat dipakai sebagai sampel
+		sampled, count := 0, 0
+		for key, value := range s.data {
+			if sampled == app.config.CleanSampleSize {
+				break
+			}
+			sampled++
+			if app.expiredEntry(key, value, now) {
+				app.expireEntry(key)
+				count++
+			}
+		}
+		removed += count
+		if sampled == 0 || float64(count)/float64(sampled) <= app.config.CleanExpiredRatio {
+			break
+		}
+	}
+	app.pruneRemoved(s, now)
+	return removed
+}
+
+// takeStale mengambil key kedaluwarsa yang dicatat oleh lookup saat dibaca,
+// dikelompokkan berdasarkan shard-nya, lalu mengosongkan catatan tersebut.
+func (app *App) takeStale() map[*shard][]string {
+	app.smu.Lock()
+	stale := app.stale
+	app.stale = make(map[string]struct{})
+	app.smu.Unlock()
+
+	groups := make(map[*shard][]string)
+	for key := range stale {
+		s := app.shardFor(key)
+		groups[s] = append(groups[s], key)
+	}
+	return groups
+}
+
+// removeStale menghapus key kedaluwarsa dari shard s yang dicatat oleh lookup
+// saat dibaca. Setiap key diperiksa ulang karena dapat diperbarui setelah dicatat.
+//
+// Mengembalikan:
+//   - int: Jumlah entri yang dihapus.
+func (app *App) removeStale(s *shard, keys []string, now uint64) int {
+	if len(keys) == 0 {
+		return 0
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	removed := 0
+	for _, key := range keys {
+		if v, ok := s.data[key]; ok && app.expiredEntry(key, v, now) {
+			app.expireEntry(key)
+			removed++
+		}
+	}
+	return removed
+}
+
+// RemoveExpired langsung menghapus semua entri yang sudah kedaluwarsa dari cache
+// dan database, tanpa menunggu TimeoutCheck berikutnya, misalnya sebelum
+// mengambil snapshot. Berbeda dengan pemeriksaan latar belakang, semua entri selalu
+// diperiksa meskipun Config.CleanStrategy bernilai CleanSampled. OnExpire dan OnEvict
+// dipanggil untuk setiap entri yang dihapus sebelum RemoveExpired kembali.
+//
+// Mengembalikan:
+//   - int: Jumlah entri yang dihapus.
+func (app *App) RemoveExpired() int {
+	defer app.dispatch()
+	now := app.now()
+	stale := app.takeStale()
+	removed := 0
+	for _, s := range app.shards {
+		removed += app.removeStale(s, stale[s], now) + app.cleanShard(s, now)
+	}
+	return removed
+}
+
+// RemoveExpired menjalankan App.RemoveExpired pada instance global yang dibuat oleh New.
+func RemoveExpired() int {
+	return app.RemoveExpired()
+}
@@ -0,0 +1,298 @@
+// Copyright (c) 2024, Jasakode Authors.
+// All rights reserved.
+// Use of this source code is governed by a BSD 3-Clause
+// license that can be found in the LICENSE file.
+
+package cago_test
+
+import (
+	"fmt"
+	"strconv"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/jasakode/cago"
+	"github.com/jasakode/cago/store"
+)
+
+func TestPolicyLRU(t *testing.T) {
+	if err := cago.New(cago.Config{MaxEntries: 3, EvictionPolicy: cago.PolicyLRU}); err != nil {
+		t.Fatal(err)
+	}
+	cago.Set("a", 1)
+	cago.Set("b", 2)
+	cago.Set("c", 3)
+
+	// Mengakses "a" menjadikannya entri yang paling baru diakses
+	cago.Get[int]("a")
+	cago.Set("d", 4)
+	if cago.Exist("b") {
+		t.Error("expected b to be evicted as least recently used")
+	}
+
+	// Put juga menjadikan entri paling baru diakses
+	cago.Put("c", 30)
+	cago.Set("e", 5)
+	if cago.Exist("a") {
+		t.Error("expected a to be evicted as least recently used")
+	}
+	for _, key := range []string{"c", "d", "e"} {
+		if !cago.Exist(key) {
+			t.Errorf("expected %s to be kept", key)
+		}
+	}
+}
+
+func TestPolicyRandom(t *testing.T) {
+	if err := cago.New(cago.Config{MaxEntries: 10, EvictionPolicy: cago.PolicyRandom}); err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 100; i++ {
+		cago.Set(fmt.Sprintf("key-%d", i), i, uint64(1000+i*100))
+	}
+	count := 0
+	cago.Range(func(key string, value store.Store) bool {
+		count++
+		return true
+	})
+	if count != 10 {
+		t.Errorf("expected 10 entries, got %d", count)
+	}
+}
+
+func TestPolicyLFU(t *testing.T) {
+	if err := cago.New(cago.Config{MaxEntries: 5, EvictionPolicy: cago.PolicyLFU}); err != nil {
+		t.Fatal(err)
+	}
+	cago.Set("hot", 0)
+	for i := 0; i < 1000; i++ {
+		cago.Get[int]("hot")
+	}
+	for i := 0; i < 20; i++ {
+		cago.Set(fmt.Sprintf("cold-%d", i), i)
+	}
+	if !cago.Exist("hot") {
+		t.Error("expected the frequently read key to be kept")
+	}
+	// Key dingin dengan jumlah akses sama dihapus mulai dari yang paling lama
+	for i := 0; i < 16; i++ {
+		if key := fmt.Sprintf("cold-%d", i); cago.Exist(key) {
+			t.Errorf("expected %s to be evicted", key)
+		}
+	}
+	for i := 16; i < 20; i++ {
+		if key := fmt.Sprintf("cold-%d", i); !cago.Exist(key) {
+			t.Errorf("expected %s to be kept", key)
+		}
+	}
+
+	// Peluruhan membuat key baru yang sering dibaca dapat mengalahkan key lama yang sudah tidak dibaca
+	for i := 0; i < 2000; i++ {
+		cago.Get[int]("cold-19")
+	}
+	cago.Remove("cold-16")
+	cago.Remove("cold-17")
+	cago.Remove("cold-18")
+	cago.Set("new", 1)
+	for i := 0; i < 50; i++ {
+		cago.Get[int]("new")
+	}
+	cago.Set("a", 1)
+	cago.Set("b", 2)
+	cago.Set("c", 3)
+	if cago.Exist("hot") || !cago.Exist("cold-19") || !cago.Exist("new") {
+		t.Error("expected the stale hot key to be evicted after decay")
+	}
+}
+
+func benchmarkEviction(b *testing.B, policy cago.EvictionPolicy) {
+	if err := cago.New(cago.Config{MaxEntries: 1000, EvictionPolicy: policy}); err != nil {
+		b.Fatal(err)
+	}
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		cago.Put(strconv.Itoa(i), i, uint64(60000+i%1000))
+	}
+}
+
+func BenchmarkEvictLRU(b *testing.B) {
+	benchmarkEviction(b, cago.PolicyLRU)
+}
+
+func BenchmarkEvictRandom(b *testing.B) {
+	benchmarkEviction(b, cago.PolicyRandom)
+}
+
+func BenchmarkEvictLFU(b *testing.B) {
+	benchmarkEviction(b, cago.PolicyLFU)
+}
+
+func TestEvictCallbacks(t *testing.T) {
+	type event struct {
+		key    string
+		reason cago.EvictReason
+	}
+	events := make(chan event, 16)
+	expired := make(chan string, 16)
+	err := cago.New(cago.Config{
+		MaxEntries:   2,
+		TimeoutCheck: 10,
+		OnExpire: func(key string, value store.Store) {
+			expired <- key
+		},
+		OnEvict: func(key string, value store.Store, reason cago.EvictReason) {
+			// Callback boleh memanggil kembali cago tanpa deadlock
+			cago.Exist(key)
+			events <- event{key, reason}
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cago.Set("a", 1)
+	cago.Set("b", 2)
+	cago.Set("c", 3)
+	if e := <-events; e.key != "a" || e.reason != cago.EvictCapacity {
+		t.Errorf("expected a evicted for capacity, got %v", e)
+	}
+
+	cago.Remove("b")
+	if e := <-events; e.key != "b" || e.reason != cago.EvictRemoved {
+		t.Errorf("expected b removed, got %v", e)
+	}
+
+	cago.Put("c", 3, 20)
+	select {
+	case key := <-expired:
+		if key != "c" {
+			t.Errorf("expected c to expire, got %s", key)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("OnExpire was not called")
+	}
+	if e := <-events; e.key != "c" || e.reason != cago.EvictExpired {
+		t.Errorf("expected c evicted as expired, got %v", e)
+	}
+}
+
+func TestFlushExpiredOnClose(t *testing.T) {
+	for _, flush := range []bool{false, true} {
+		var expired []string
+		var now atomic.Int64
+		now.Store(1_700_000_000_000)
+		c, err := cago.NewCache(cago.Config{
+			TimeoutCheck:        60000,
+			Clock:               now.Load,
+			FlushExpiredOnClose: flush,
+			OnExpire: func(key string, value store.Store) {
+				expired = append(expired, key)
+			},
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		c.Put("short", "x", 1)
+		c.Put("long", "y", 60000)
+		now.Add(1)
+		if err := c.Close(); err != nil {
+			t.Fatal(err)
+		}
+
+		if flush && (len(expired) != 1 || expired[0] != "short") {
+			t.Errorf("expected OnExpire for short at Close, got %v", expired)
+		}
+		if !flush && len(expired) != 0 {
+			t.Errorf("expected no OnExpire without FlushExpiredOnClose, got %v", expired)
+		}
+	}
+}
+
+func TestCleanStrategy(t *testing.T) {
+	for _, strategy := range []cago.CleanStrategy{cago.CleanFullScan, cago.CleanSampled} {
+		var now atomic.Int64
+		now.Store(1_700_000_000_000)
+		c, err := cago.NewCache(cago.Config{TimeoutCheck: 60000, Clock: now.Load, CleanStrategy: strategy, CleanSampleSize: 10})
+		if err != nil {
+			t.Fatal(err)
+		}
+		for i := 0; i < 100; i++ {
+			c.Put(fmt.Sprint("short-", i), i, 1)
+		}
+		c.Put("long", "kept", 60000)
+		now.Add(1)
+
+		// Semua sampel kedaluwarsa, sehingga CleanSampled terus mengambil sampel baru
+		if n := c.Sweep(); n != 100 {
+			t.Errorf("strategy %d: expected 100 expired entries, got %d", strategy, n)
+		}
+		if !c.Exist("long") {
+			t.Errorf("strategy %d: expected long to be kept", strategy)
+		}
+		c.Close()
+	}
+}
+
+func TestRemoveExpired(t *testing.T) {
+	var expired atomic.Int32
+	var now atomic.Int64
+	now.Store(1_700_000_000_000)
+	err := cago.New(cago.Config{
+		TimeoutCheck:  60000, // Pemeriksaan latar belakang tidak berjalan selama tes
+		Clock:         now.Load,
+		CleanStrategy: cago.CleanSampled,
+		OnExpire: func(key string, value store.Store) {
+			expired.Add(1)
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 50; i++ {
+		cago.Put(fmt.Sprint("short-", i), i, 1)
+	}
+	cago.Put("long", "kept", 60000)
+	now.Add(1)
+
+	if n := cago.RemoveExpired(); n != 50 {
+		t.Errorf("expected 50 expired entries, got %d", n)
+	}
+	if n := expired.Load(); n != 50 {
+		t.Errorf("expected OnExpire for 50 entries, got %d", n)
+	}
+	var keys []string
+	cago.Range(func(key string, value store.Store) bool {
+		keys = append(keys, key)
+		return true
+	})
+	if len(keys) != 1 || keys[0] != "long" {
+		t.Errorf("expected only long to be kept, got %v", keys)
+	}
+	if n := cago.RemoveExpired(); n != 0 {
+		t.Errorf("expected nothing left to remove, got %d", n)
+	}
+}
+
+func benchmarkClean(b *testing.B, strategy cago.CleanStrategy) {
+	c, err := cago.NewCache(cago.Config{TimeoutCheck: 3600000, CleanStrategy: strategy})
+	if err != nil {
+		b.Fatal(err)
+	}
+	defer c.Close()
+	for i := 0; i < 100000; i++ {
+		c.Put(strconv.Itoa(i), i, 3600000)
+	}
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		c.Sweep()
+	}
+}
+
+func BenchmarkCleanFullScan(b *testing.B) {
+	benchmarkClean(b, cago.CleanFullScan)
+}
+
+func BenchmarkCleanSampled(b *testing.B) {
+	benchmarkClean(b, cago.CleanSampled)
+}
@@ -0,0 +1,17 @@
+// Copyright (c) 2024, Jasakode Authors.
+// All rights reserved.
+// Use of this source code is governed by a BSD 3-Clause
+// license that can be found in the LICENSE file.
+
+package cago
+
+// Sweep menjalankan satu pemeriksaan entri kedaluwarsa sesuai Config.CleanStrategy,
+// sama seperti pemeriksaan latar belakang, tanpa menunggu TimeoutCheck berikutnya.
+// Fungsi ini hanya tersedia untuk tes dan benchmark.
+//
+// Mengembalikan:
+//   - int: Jumlah entri yang dihapus.
+func (app *App) Sweep() int {
+	defer app.dispatch()
+	return app.sweep(app.now())
+}
@@ -0,0 +1,114 @@
+// Copyright (c) 2024, Jasakode Authors.
+// All rights reserved.
+// Use of this source code is governed by a BSD 3-Clause
+// license that can be found in the LICENSE file.
+
+package cago
+
+import (
+	"errors"
+
+	"github.com/jasakode/cago/store"
+)
+
+// GetFresh bekerja seperti Get, tetapi ketika key tidak ada di memori, baris
+// dengan key tersebut dicari langsung di database. Fungsi ini berguna ketika
+// file SQLite yang sama ditulis oleh proses lain: baris yang ditemukan, valid,
+// dan belum kedaluwarsa dimuat ke dalam cache lalu dikembalikan. Tanpa
+// Config.Path, GetFresh sama dengan Get.
+//
+// Parameter:
+//   - key (string): Key unik yang digunakan untuk mencari nilai.
+//
+// Tipe Parameter:
+//   - T (store.Compare): Tipe data yang diharapkan, dengan aturan konversi yang sama seperti Get.
+//
+// Mengembalikan:
+//   - T: Nilai yang ditemukan, atau nilai nol dari T.
+//   - bool: True jika nilai ditemukan di memori atau database dan sesuai dengan tipe T.
+func GetFresh[T store.Compare](key string) (T, bool) {
+	return getFresh[T](app, key)
+}
+
+// getFresh adalah implementasi GetFresh untuk instance app.
+func getFresh[T store.Compare](app *App, key string) (T, bool) {
+	var zero T
+	result, err := readThrough[T](app, key)
+	if err == nil {
+		return *result, true
+	}
+	if !errors.Is(err, ErrNotFound) {
+		return zero, false
+	}
+	data, ok := app.refresh(key)
+	if !ok {
+		return zero, false
+	}
+	result, err = decode[T](data)
+	if err != nil {
+		app.decodeErr.Add(1)
+		app.logf("cago: get %q: %v", key, err)
+		return zero, false
+	}
+	return *result, true
+}
+
+// refresh memuat satu key dari database ke dalam cache. Antrean write-behind
+// dikosongkan terlebih dahulu agar key yang baru dihapus di memori tidak
+// dimuat kembali dari baris yang belum sempat dihapus.
+//
+// Mengembalikan:
+//   - store.Store: Store yang dimuat, atau nil jika tidak ada.
+//   - bool: True jika baris ditemukan, valid, belum kedaluwarsa, dan berhasil dimuat.
+func (app *App) refresh(key string) (store.Store, bool) {
+	defer app.dispatch()
+	s := app.shardFor(key)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	// app.db dapat diganti oleh Close, sehingga dibaca ketika lock dipegang
+	if app.db == nil {
+		return nil, false
+	}
+	now := app.now()
+	// Pemanggil lain mungkin sudah memuat atau menyimpan key ini
+	if data, ok := s.data[key]; ok && !app.expiredEntry(key, data, now) {
+		return data, true
+	}
+	if app.db.wb != nil {
+		if err := app.db.wb.flush(); err != nil {
+			app.logf("cago: refresh %q: %v", key, err)
+			return nil, false
+		}
+	}
+	row, err := app.db.FindByKey(key)
+	if err != nil {
+		app.logf("cago: refresh %q: %v", key, err)
+		return nil, false
+	}
+	if row == nil {
+		return nil, false
+	}
+	parsed, err := store.ParseStoreChecked(row.Value)
+	if err != nil {
+		app.logf("cago: refresh %q: %v", key, err)
+		return nil, false
+	}
+	if expired(parsed, now) {
+		return nil, false
+	}
+	data, err := app.upgrade(key, parsed)
+	if err != nil {
+		app.logf("cago: refresh %q: %v", key, err)
+		return nil, false
+	}
+	if err := app.setEntry(key, data); err != nil {
+		app.logf("cago: refresh %q: %v", key, err)
+		return nil, false
+	}
+	if data.Version() != parsed.Version() {
+		if err := app.db.InsertOrUpdate(key, data); err != nil {
+			app.logf("cago: refresh %q: %v", key, err)
+		}
+	}
+	return data, true
+}
@@ -0,0 +1,176 @@
+// Copyright (c) 2024, Jasakode Authors.
+// All rights reserved.
+// Use of this source code is governed by a BSD 3-Clause
+// license that can be found in the LICENSE file.
+
+package cago
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/jasakode/cago/store"
+)
+
+// flight mewakili perhitungan GetOrSet yang sedang berjalan untuk satu key.
+// Pemanggil lain untuk key yang sama menunggu done lalu memakai hasilnya.
+type flight struct {
+	done  chan struct{} // Ditutup ketika perhitungan selesai.
+	value any           // Nilai hasil perhitungan.
+	err   error         // Kesalahan hasil perhitungan.
+}
+
+// GetOrSet mengambil nilai dengan key yang diberikan. Jika key tidak ditemukan,
+// nilai dihitung dengan fn lalu disimpan dengan masa berlaku maxAge.
+// Jika fn mengembalikan kesalahan, tidak ada yang disimpan. Pemanggil yang
+// bersamaan untuk key yang sama hanya menjalankan satu fn dan berbagi hasilnya.
+//
+// Parameter:
+//   - key (string): Key unik yang digunakan untuk mencari nilai dalam store.
+//   - maxAge (uint64): Waktu maksimal dalam milidetik untuk nilai yang baru dihitung.
+//     Nilai 0 berarti tidak pernah kedaluwarsa.
+//   - fn (func() (T, error)): Fungsi untuk menghitung nilai jika key tidak ditemukan.
+//
+// Mengembalikan:
+//   - T: Nilai dari cache atau hasil fn.
+//   - error: Kesalahan dari fn, atau kesalahan saat membaca maupun menyimpan nilai.
+func GetOrSet[T store.Compare](key string, maxAge uint64, fn func() (T, error)) (T, error) {
+	return getOrSet(app, context.Background(), key, func(context.Context) (T, uint64, error) {
+		value, err := fn()
+		return value, maxAge, err
+	})
+}
+
+// GetOrSetCtx bekerja seperti GetOrSet, tetapi meneruskan ctx ke fn sehingga
+// perhitungan dapat dibatalkan. Jika ctx sudah selesai, fungsi langsung kembali
+// tanpa menjalankan fn.
+//
+// Parameter:
+//   - ctx (context.Context): Context untuk membatalkan perhitungan.
+//   - key (string): Key unik yang digunakan untuk mencari nilai dalam store.
+//   - maxAge (uint64): Waktu maksimal dalam milidetik untuk nilai yang baru dihitung.
+//     Nilai 0 berarti tidak pernah kedaluwarsa.
+//   - fn (func(context.Context) (T, error)): Fungsi untuk menghitung nilai jika key tidak ditemukan.
+//
+// Mengembalikan:
+//   - T: Nilai dari cache atau hasil fn.
+//   - error: Kesalahan ctx atau fn, atau kesalahan saat membaca maupun menyimpan nilai.
+func GetOrSetCtx[T store.Compare](ctx context.Context, key string, maxAge uint64, fn func(context.Context) (T, error)) (T, error) {
+	return getOrSet(app, ctx, key, func(ctx context.Context) (T, uint64, error) {
+		value, err := fn(ctx)
+		return value, maxAge, err
+	})
+}
+
+// getOrSet adalah implementasi GetOrSetCtx untuk instance app. Selain nilai,
+// fn juga menentukan maxAge untuk nilai yang disimpan.
+func getOrSet[T store.Compare](app *App, ctx context.Context, key string, fn func(context.Context) (T, uint64, error)) (T, error) {
+	var zero T
+	for {
+		if err := ctx.Err(); err != nil {
+			return zero, err
+		}
+		if rs, err := getErr[T](app, key); err == nil {
+			return *rs, nil
+		} else if !errors.Is(err, ErrNotFound) {
+			return zero, err
+		}
+
+		s := app.shardFor(key)
+		s.mu.Lock()
+		// Menunggu perhitungan yang sedang berjalan untuk key yang sama
+		if f, ok := s.flights[key]; ok {
+			s.mu.Unlock()
+			select {
+			case <-ctx.Done():
+				return zero, ctx.Err()
+			case <-f.done:
+			}
+			if f.err != nil {
+				return zero, f.err
+			}
+			value, ok := f.value.(T)
+			if !ok {
+				return zero, fmt.Errorf("key %q: computed value is %T, not %T", key, f.value, zero)
+			}
+			return value, nil
+		}
+		// Key dapat tersimpan sejak pemeriksaan di atas, sehingga diperiksa ulang.
+		// Entri kedaluwarsa yang belum dihapus dianggap tidak ada seperti pada getErr.
+		if old, ok := s.data[key]; ok && !app.expiredEntry(key, old, app.now()) {
+			s.mu.Unlock()
+			continue
+		}
+		f := &flight{done: make(chan struct{})}
+		s.flights[key] = f
+		s.mu.Unlock()
+
+		value, err := app.compute(ctx, key, f, func(ctx context.Context) (store.Compare, uint64, error) {
+			return fn(ctx)
+		})
+		if err != nil {
+			return zero, err
+		}
+		return value.(T), nil
+	}
+}
+
+// compute menjalankan fn untuk flight f lalu menyimpan hasilnya. Flight selalu
+// dilepas dari shard milik key dan done selalu ditutup, termasuk ketika fn
+// panik, agar pemanggil lain tidak menunggu selamanya.
+func (app *App) compute(ctx context.Context, key string, f *flight, fn func(context.Context) (store.Compare, uint64, error)) (value any, err error) {
+	finished := false
+	defer func() {
+		if !finished {
+			f.err = fmt.Errorf("key %q: computation panicked", key)
+		}
+		s := app.shardFor(key)
+		s.mu.Lock()
+		delete(s.flights, key)
+		s.mu.Unlock()
+		close(f.done)
+	}()
+
+	value, maxAge, err := fn(ctx)
+	if err == nil {
+		err = app.Put(key, value, maxAge)
+	}
+	if err != nil {
+		value = nil
+	}
+	f.value, f.err = value, err
+	finished = true
+	return value, err
+}
+
+// readThrough bekerja seperti getErr, tetapi ketika key tidak ditemukan dan
+// Config.Loader diatur, nilai dimuat dengan Loader lalu disimpan. Pemanggilan
+// Loader untuk key yang sama dijalankan sekali melalui mekanisme yang sama dengan GetOrSet.
+func readThrough[K store.Compare](app *App, key string) (*K, error) {
+	rs, err := getErr[K](app, key)
+	if !errors.Is(err, ErrNotFound) || app.config.Loader == nil {
+		return rs, err
+	}
+	value, err := getOrSet(app, context.Background(), key, func(context.Context) (K, uint64, error) {
+		var zero K
+		loaded, maxAge, err := app.config.Loader(key)
+		if err != nil {
+			return zero, 0, err
+		}
+		// Nilai dari Loader dikonversi ke K dengan aturan yang sama seperti Set dan Get
+		data, err := encode(app.now(), loaded)
+		if err != nil {
+			return zero, 0, fmt.Errorf("key %q: %w", key, err)
+		}
+		result, err := decode[K](data)
+		if err != nil {
+			return zero, 0, fmt.Errorf("key %q: %w: %w", key, ErrTypeMismatch, err)
+		}
+		return *result, maxAge, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &value, nil
+}
@@ -0,0 +1,191 @@
+// Copyright (c) 2024, Jasakode Authors.
+// All rights reserved.
+// Use of this source code is governed by a BSD 3-Clause
+// license that can be found in the LICENSE file.
+
+package cago_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/jasakode/cago"
+)
+
+func TestGetOrSetCtx(t *testing.T) {
+	if err := cago.New(); err != nil {
+		t.Fatal(err)
+	}
+	calls := 0
+	compute := func(ctx context.Context) (string, error) {
+		calls++
+		return "computed", ctx.Err()
+	}
+
+	// Context yang sudah dibatalkan tidak menjalankan fn
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := cago.GetOrSetCtx(ctx, "key", 0, compute); !errors.Is(err, context.Canceled) {
+		t.Errorf("expected context.Canceled, got %v", err)
+	}
+	if calls != 0 {
+		t.Errorf("expected fn not to run, ran %d times", calls)
+	}
+
+	// Kesalahan context dari fn tidak menyimpan apa pun
+	ctx, cancel = context.WithCancel(context.Background())
+	_, err := cago.GetOrSetCtx(ctx, "key", 0, func(ctx context.Context) (string, error) {
+		cancel()
+		return compute(ctx)
+	})
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("expected context.Canceled, got %v", err)
+	}
+	if cago.Exist("key") {
+		t.Error("expected nothing to be stored after cancellation")
+	}
+
+	calls = 0
+	for i := 0; i < 2; i++ {
+		rs, err := cago.GetOrSetCtx(context.Background(), "key", 0, compute)
+		if err != nil || rs != "computed" {
+			t.Errorf("expected computed, got %q (%v)", rs, err)
+		}
+	}
+	if calls != 1 {
+		t.Errorf("expected fn to run once, ran %d times", calls)
+	}
+}
+
+func TestGetOrSetSingleFlight(t *testing.T) {
+	if err := cago.New(); err != nil {
+		t.Fatal(err)
+	}
+	var calls atomic.Int32
+	release := make(chan struct{})
+	compute := func() (int, error) {
+		calls.Add(1)
+		<-release
+		return 42, nil
+	}
+
+	var wg sync.WaitGroup
+	results := make(chan int, 10)
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			rs, err := cago.GetOrSet("answer", 0, compute)
+			if err != nil {
+				t.Error(err)
+			}
+			results <- rs
+		}()
+	}
+	time.Sleep(50 * time.Millisecond)
+	close(release)
+	wg.Wait()
+	close(results)
+
+	if n := calls.Load(); n != 1 {
+		t.Errorf("expected one computation, got %d", n)
+	}
+	for rs := range results {
+		if rs != 42 {
+			t.Errorf("expected 42, got %d", rs)
+		}
+	}
+
+	// Perhitungan yang gagal tidak meninggalkan waiter sehingga key dapat dihitung ulang
+	failed := errors.New("backend down")
+	if _, err := cago.GetOrSet("flaky", 0, func() (int, error) { return 0, failed }); !errors.Is(err, failed) {
+		t.Errorf("expected backend error, got %v", err)
+	}
+	if rs, err := cago.GetOrSet("flaky", 0, func() (int, error) { return 7, nil }); err != nil || rs != 7 {
+		t.Errorf("expected 7 after failure, got %d (%v)", rs, err)
+	}
+}
+
+func TestLoader(t *testing.T) {
+	var calls atomic.Int32
+	release := make(chan struct{})
+	c, err := cago.NewCache(cago.Config{
+		Loader: func(key string) (any, uint64, error) {
+			calls.Add(1)
+			<-release
+			if key == "broken" {
+				return nil, 0, errors.New("source unavailable")
+			}
+			return "loaded:" + key, 60000, nil
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer c.Close()
+
+	// Banyak miss bersamaan hanya memanggil Loader sekali
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if v := cago.GetFrom[string](c, "user"); v == nil || *v != "loaded:user" {
+				t.Errorf("expected loaded:user, got %v", v)
+			}
+		}()
+	}
+	time.Sleep(20 * time.Millisecond)
+	close(release)
+	wg.Wait()
+	if n := calls.Load(); n != 1 {
+		t.Errorf("expected Loader to run once, ran %d times", n)
+	}
+	if _, _, expires, ok := c.GetMeta("user"); !ok || expires.IsZero() {
+		t.Error("expected the loaded value to be stored with the loader max age")
+	}
+
+	// Kesalahan Loader diteruskan dan tidak ada yang disimpan
+	if v, ok := c.GetString("broken"); ok {
+		t.Errorf("expected no value for broken, got %q", v)
+	}
+	if c.Exist("broken") {
+		t.Error("expected nothing to be cached after a loader error")
+	}
+	loadErr := errors.New("source unavailable")
+	if err := cago.New(cago.Config{Loader: func(string) (any, uint64, error) { return nil, 0, loadErr }}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := cago.GetErr[string]("missing"); !errors.Is(err, loadErr) {
+		t.Errorf("expected loader error from GetErr, got %v", err)
+	}
+}
+
+func TestGetOrSetExpiredUnswept(t *testing.T) {
+	var now atomic.Int64
+	now.Store(1_700_000_000_000)
+	if err := cago.New(cago.Config{TimeoutCheck: 60000, Clock: now.Load}); err != nil {
+		t.Fatal(err)
+	}
+	cago.Put("k", "old", 1)
+	now.Add(1)
+
+	// Entri kedaluwarsa yang belum dihapus pemeriksaan latar belakang dihitung ulang
+	done := make(chan string, 1)
+	go func() {
+		value, _ := cago.GetOrSet("k", 60000, func() (string, error) { return "new", nil })
+		done <- value
+	}()
+	select {
+	case value := <-done:
+		if value != "new" {
+			t.Errorf("expected new, got %q", value)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("GetOrSet did not return for an expired but unswept key")
+	}
+}
@@ -0,0 +1,116 @@
+// Copyright (c) 2024, Jasakode Authors.
+// All rights reserved.
+// Use of this source code is governed by a BSD 3-Clause
+// license that can be found in the LICENSE file.
+
+package cago
+
+import (
+	"sort"
+
+	"github.com/jasakode/cago/store"
+)
+
+// Range memanggil fn untuk setiap entri yang belum kedaluwarsa di dalam cache.
+// Iterasi berhenti lebih awal jika fn mengembalikan false.
+//
+// Daftar key setiap shard diambil terlebih dahulu di bawah read lock shard
+// tersebut, lalu setiap entri dibaca ulang sebelum fn dipanggil. fn selalu dipanggil di luar lock sehingga
+// fn boleh memanggil fungsi yang mengubah cache seperti Set, Put, atau Remove
+// tanpa menyebabkan deadlock. Entri yang dihapus atau kedaluwarsa selama
+// iterasi berlangsung akan dilewati.
+//
+// Parameter:
+//   - fn (func(key string, value store.Store) bool): Fungsi yang dipanggil untuk
+//     setiap entri. Kembalikan false untuk menghentikan iterasi.
+func (app *App) Range(fn func(key string, value store.Store) bool) {
+	for _, s := range app.shards {
+		s.mu.RLock()
+		keys := make([]string, 0, len(s.data))
+		for k := range s.data {
+			keys = append(keys, k)
+		}
+		s.mu.RUnlock()
+
+		for _, k := range keys {
+			s.mu.RLock()
+			v, ok := s.data[k]
+			// Entri yang sudah dihapus atau kedaluwarsa selama iterasi dilewati
+			ok = ok && !app.expiredEntry(k, v, app.now())
+			s.mu.RUnlock()
+			if !ok {
+				continue
+			}
+			if !fn(k, v) {
+				return
+			}
+		}
+	}
+}
+
+// Range menjalankan App.Range pada instance global yang dibuat oleh New.
+func Range(fn func(key string, value store.Store) bool) {
+	app.Range(fn)
+}
+
+// Entries mengembalikan salinan semua entri yang belum kedaluwarsa dan dapat
+// dikonversi menjadi tipe T, misalnya ketika seluruh cache berisi satu tipe struct.
+// Entri yang gagal dikonversi menjadi T dilewati tanpa dihitung sebagai
+// kesalahan konversi. Semua entri dibaca di bawah read lock semua shard, dan
+// map yang dikembalikan adalah salinan sehingga mengubahnya tidak memengaruhi cache.
+//
+// Mengembalikan:
+//   - map[string]T: Nilai setiap entri yang sesuai dengan tipe T, berdasarkan key.
+func Entries[T store.Compare]() map[string]T {
+	now := app.now()
+	app.rlockAll()
+	defer app.runlockAll()
+	entries := make(map[string]T)
+	for _, s := range app.shards {
+		for key, value := range s.data {
+			if app.expiredEntry(key, value, now) {
+				continue
+			}
+			if result, err := decode[T](value); err == nil {
+				entries[key] = *result
+			}
+		}
+	}
+	return entries
+}
+
+// FindKeys mencari key berdasarkan nilainya, misalnya untuk keperluan admin
+// atau debug. Setiap entri yang belum kedaluwarsa diperiksa satu per satu
+// sehingga biayanya O(n) terhadap jumlah entri; jangan dipakai di jalur yang
+// sering dipanggil. match dipanggil di bawah read lock dan menerima store.Store
+// dari entri, sama seperti GetAny, sehingga match tidak boleh memanggil fungsi
+// cago lainnya.
+//
+// Parameter:
+//   - match (func(value any) bool): Fungsi yang mengembalikan true untuk nilai yang dicari.
+//
+// Mengembalikan:
+//   - []string: Key yang nilainya memenuhi match, diurutkan secara leksikografis.
+func (app *App) FindKeys(match func(value any) bool) []string {
+	now := app.now()
+	app.rlockAll()
+	defer app.runlockAll()
+	keys := []string{}
+	for _, s := range app.shards {
+		for key, value := range s.data {
+			if app.expiredEntry(key, value, now) {
+				continue
+			}
+			if match(value) {
+				keys = append(keys, key)
+			}
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// FindKeys menjalankan App.FindKeys pada instance global yang dibuat oleh New.
+func FindKeys(match func(value any) bool) []string {
+	return app.FindKeys(match)
+}
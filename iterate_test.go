@@ -0,0 +1,107 @@
+// Copyright (c) 2024, Jasakode Authors.
+// All rights reserved.
+// Use of this source code is governed by a BSD 3-Clause
+// license that can be found in the LICENSE file.
+
+package cago_test
+
+import (
+	"sync/atomic"
+	"testing"
+
+	"github.com/jasakode/cago"
+	"github.com/jasakode/cago/store"
+)
+
+func TestRange(t *testing.T) {
+	var now atomic.Int64
+	now.Store(1_700_000_000_000)
+	if err := cago.New(cago.Config{Clock: now.Load}); err != nil {
+		t.Fatal(err)
+	}
+	cago.Set("a", "1")
+	cago.Set("b", "2")
+	cago.Set("c", "3")
+	cago.Set("expired", "x", 1)
+	now.Add(1)
+
+	// Entri yang kedaluwarsa tidak boleh dikunjungi
+	seen := map[string]string{}
+	cago.Range(func(key string, value store.Store) bool {
+		seen[key] = value.Text()
+		return true
+	})
+	if len(seen) != 3 || seen["a"] != "1" || seen["b"] != "2" || seen["c"] != "3" {
+		t.Errorf("unexpected entries: %v", seen)
+	}
+
+	// Iterasi berhenti ketika fn mengembalikan false
+	count := 0
+	cago.Range(func(key string, value store.Store) bool {
+		count++
+		return false
+	})
+	if count != 1 {
+		t.Errorf("expected 1 visit, got %d", count)
+	}
+
+	// Mengubah cache dari dalam callback tidak boleh deadlock
+	cago.Range(func(key string, value store.Store) bool {
+		cago.Remove(key)
+		return true
+	})
+	if cago.Exist("a") || cago.Exist("b") || cago.Exist("c") {
+		t.Error("expected all keys to be removed")
+	}
+}
+
+func TestEntries(t *testing.T) {
+	var now atomic.Int64
+	now.Store(1_700_000_000_000)
+	if err := cago.New(cago.Config{Clock: now.Load}); err != nil {
+		t.Fatal(err)
+	}
+	type user struct {
+		Name string `json:"name"`
+	}
+	cago.Set("alice", user{Name: "Alice"})
+	cago.Set("bob", user{Name: "Bob"})
+	cago.Set("note", "not json")
+	cago.Set("expired", user{Name: "Eve"}, 1)
+	now.Add(1)
+
+	entries := cago.Entries[user]()
+	if len(entries) != 2 || entries["alice"].Name != "Alice" || entries["bob"].Name != "Bob" {
+		t.Errorf("unexpected entries: %v", entries)
+	}
+
+	// Map yang dikembalikan adalah salinan
+	entries["alice"] = user{Name: "Mallory"}
+	delete(entries, "bob")
+	if again := cago.Entries[user](); len(again) != 2 || again["alice"].Name != "Alice" {
+		t.Errorf("expected cache to be unaffected, got %v", again)
+	}
+}
+
+func TestFindKeys(t *testing.T) {
+	var now atomic.Int64
+	now.Store(1_700_000_000_000)
+	if err := cago.New(cago.Config{Clock: now.Load}); err != nil {
+		t.Fatal(err)
+	}
+	cago.Set("session:1", "alice")
+	cago.Set("session:2", "bob")
+	cago.Set("session:3", "alice")
+	cago.Set("session:4", "alice", 1)
+	now.Add(1)
+
+	keys := cago.FindKeys(func(value any) bool {
+		return value.(store.Store).Text() == "alice"
+	})
+	if len(keys) != 2 || keys[0] != "session:1" || keys[1] != "session:3" {
+		t.Errorf("expected [session:1 session:3], got %v", keys)
+	}
+	if keys := cago.FindKeys(func(any) bool { return false }); len(keys) != 0 {
+		t.Errorf("expected no keys, got %v", keys)
+	}
+}
@@ -8,6 +8,9 @@ package lib
 import (
 	"bytes"
 	"encoding/binary"
+	"fmt"
+	"math"
+	"time"
 )
 
 // Mengubah uint8 ke []byte.
@@ -114,6 +117,148 @@ func Int64ToByte(num int64) []byte {
 	return buf.Bytes()
 }
 
+// Mengubah float32 ke []byte.
+// Fungsi ini akan selalu menghasilkan slice byte dengan panjang 4 byte.
+// Nilai disimpan dalam format IEEE-754 sehingga NaN dan Inf juga dapat diubah.
+// Fungsi ini menggunakan encoding Big Endian untuk menyimpan nilai 32-bit ke dalam 4 byte.
+func Float32ToByte(f float32) []byte {
+	rs := make([]byte, 4)
+	binary.BigEndian.PutUint32(rs, math.Float32bits(f))
+	return rs
+}
+
+// Mengubah float64 ke []byte.
+// Fungsi ini akan selalu menghasilkan slice byte dengan panjang 8 byte.
+// Nilai disimpan dalam format IEEE-754 sehingga NaN dan Inf juga dapat diubah.
+// Fungsi ini menggunakan encoding Big Endian untuk menyimpan nilai 64-bit ke dalam 8 byte.
+func Float64ToByte(f float64) []byte {
+	rs := make([]byte, 8)
+	binary.BigEndian.PutUint64(rs, math.Float64bits(f))
+	return rs
+}
+
+// Mengubah time.Time ke []byte.
+// Fungsi ini akan selalu menghasilkan slice byte dengan panjang 12 byte: 8 byte
+// detik sejak unix epoch (int64) diikuti 4 byte nanodetik (uint32), keduanya Big Endian.
+// Detik dan nanodetik disimpan terpisah karena unix nanodetik dalam int64 hanya
+// mencakup tahun 1678 hingga 2262, sehingga zero time dan waktu sebelum epoch
+// tetap dapat diubah. Zona waktu dan pembacaan monotonic tidak disimpan.
+func TimeToByte(t time.Time) []byte {
+	rs := make([]byte, 12)
+	binary.BigEndian.PutUint64(rs, uint64(t.Unix()))
+	binary.BigEndian.PutUint32(rs[8:], uint32(t.Nanosecond()))
+	return rs
+}
+
+// Mengubah bool ke []byte.
+// Fungsi ini akan selalu menghasilkan slice byte dengan panjang 1 byte.
+// Nilai true diubah menjadi 1 dan nilai false diubah menjadi 0.
+func BoolToByte(b bool) []byte {
+	if b {
+		return []byte{1}
+	}
+	return []byte{0}
+}
+
+// Mengubah []byte ke uint8.
+// Fungsi ini membaca 1 byte pertama dengan encoding Big Endian, pasangan dari Uint8ToByte.
+// Jika panjang slice kurang dari 1 byte, fungsi akan mengembalikan kesalahan.
+func ByteToUint8(b []byte) (uint8, error) {
+	if len(b) < 1 {
+		return 0, fmt.Errorf("insufficient length for uint8 conversion")
+	}
+	return b[0], nil
+}
+
+// Mengubah []byte ke uint16.
+// Fungsi ini membaca 2 byte pertama dengan encoding Big Endian, pasangan dari Uint16ToByte.
+// Jika panjang slice kurang dari 2 byte, fungsi akan mengembalikan kesalahan.
+func ByteToUint16(b []byte) (uint16, error) {
+	if len(b) < 2 {
+		return 0, fmt.Errorf("insufficient length for uint16 conversion")
+	}
+	return binary.BigEndian.Uint16(b), nil
+}
+
+// Mengubah []byte ke uint32.
+// Fungsi ini membaca 4 byte pertama dengan encoding Big Endian, pasangan dari Uint32ToByte.
+// Jika panjang slice kurang dari 4 byte, fungsi akan mengembalikan kesalahan.
+func ByteToUint32(b []byte) (uint32, error) {
+	if len(b) < 4 {
+		return 0, fmt.Errorf("insufficient length for uint32 conversion")
+	}
+	return binary.BigEndian.Uint32(b), nil
+}
+
+// Mengubah []byte ke uint64.
+// Fungsi ini membaca 8 byte pertama dengan encoding Big Endian, pasangan dari Uint64ToByte.
+// Jika panjang slice kurang dari 8 byte, fungsi akan mengembalikan kesalahan.
+func ByteToUint64(b []byte) (uint64, error) {
+	if len(b) < 8 {
+		return 0, fmt.Errorf("insufficient length for uint64 conversion")
+	}
+	return binary.BigEndian.Uint64(b), nil
+}
+
+// Mengubah []byte ke int8.
+// Fungsi ini membaca 1 byte pertama dengan encoding Big Endian, pasangan dari Int8ToByte.
+// Nilai dibaca dalam format two's complement sehingga nilai negatif kembali utuh.
+// Jika panjang slice kurang dari 1 byte, fungsi akan mengembalikan kesalahan.
+func ByteToInt8(b []byte) (int8, error) {
+	if len(b) < 1 {
+		return 0, fmt.Errorf("insufficient length for int8 conversion")
+	}
+	return int8(b[0]), nil
+}
+
+// Mengubah []byte ke int16.
+// Fungsi ini membaca 2 byte pertama dengan encoding Big Endian, pasangan dari Int16ToByte.
+// Nilai dibaca dalam format two's complement sehingga nilai negatif kembali utuh.
+// Jika panjang slice kurang dari 2 byte, fungsi akan mengembalikan kesalahan.
+func ByteToInt16(b []byte) (int16, error) {
+	if len(b) < 2 {
+		return 0, fmt.Errorf("insufficient length for int16 conversion")
+	}
+	return int16(binary.BigEndian.Uint16(b)), nil
+}
+
+// Mengubah []byte ke int32.
+// Fungsi ini membaca 4 byte pertama dengan encoding Big Endian, pasangan dari Int32ToByte.
+// Nilai dibaca dalam format two's complement sehingga nilai negatif kembali utuh.
+// Jika panjang slice kurang dari 4 byte, fungsi akan mengembalikan kesalahan.
+func ByteToInt32(b []byte) (int32, error) {
+	if len(b) < 4 {
+		return 0, fmt.Errorf("insufficient length for int32 conversion")
+	}
+	return int32(binary.BigEndian.Uint32(b)), nil
+}
+
+// Mengubah []byte ke int64.
+// Fungsi ini membaca 8 byte pertama dengan encoding Big Endian, pasangan dari Int64ToByte.
+// Nilai dibaca dalam format two's complement sehingga nilai negatif kembali utuh.
+// Jika panjang slice kurang dari 8 byte, fungsi akan mengembalikan kesalahan.
+func ByteToInt64(b []byte) (int64, error) {
+	if len(b) < 8 {
+		return 0, fmt.Errorf("insufficient length for int64 conversion")
+	}
+	return int64(binary.BigEndian.Uint64(b)), nil
+}
+
+// Mengubah []byte ke time.Time.
+// Fungsi ini membaca 12 byte pertama dengan encoding Big Endian, pasangan dari TimeToByte.
+// Zero time dikembalikan sebagai time.Time{}, sedangkan waktu lain dikembalikan dalam UTC.
+// Jika panjang slice kurang dari 12 byte, fungsi akan mengembalikan kesalahan.
+func ByteToTime(b []byte) (time.Time, error) {
+	if len(b) < 12 {
+		return time.Time{}, fmt.Errorf("insufficient length for time.Time conversion")
+	}
+	t := time.Unix(int64(binary.BigEndian.Uint64(b)), int64(binary.BigEndian.Uint32(b[8:])))
+	if t.IsZero() {
+		return time.Time{}, nil
+	}
+	return t.UTC(), nil
+}
+
 // Mengubah string ke []byte.
 // Fungsi ini akan mengembalikan representasi byte dari string yang diberikan
 // dengan panjang yang sama dengan string tersebut.
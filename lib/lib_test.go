@@ -6,6 +6,7 @@
 package lib_test
 
 import (
+	"math"
 	"testing"
 
 	"github.com/jasakode/cago/lib"
@@ -122,7 +123,7 @@ func TestUint64ToByte(t *testing.T) {
 		{1, []byte{0, 0, 0, 0, 0, 0, 0, 1}},                                    // Nilai normal
 		{255, []byte{0, 0, 0, 0, 0, 0, 0, 255}},                                // Nilai maksimum untuk byte ketujuh
 		{256, []byte{0, 0, 0, 0, 0, 0, 1, 0}},                                  // Nilai dengan byte keenam
-		{65535, []byte{0, 0, 0, 0, 0, 255, 255, 0}},                            // Nilai maksimum untuk byte keenam dan ketujuh
+		{65535, []byte{0, 0, 0, 0, 0, 0, 255, 255}},                            // Nilai maksimum untuk byte keenam dan ketujuh
 		{4294967295, []byte{0, 0, 0, 0, 255, 255, 255, 255}},                   // Nilai maksimum untuk uint32
 		{18446744073709551615, []byte{255, 255, 255, 255, 255, 255, 255, 255}}, // Nilai maksimum untuk uint64
 		{9223372036854775808, []byte{128, 0, 0, 0, 0, 0, 0, 0}},                // Nilai tengah
@@ -148,7 +149,7 @@ func TestInt8ToByte(t *testing.T) {
 		input  int8
 		output []byte
 	}{
-		{-128, []byte{255}}, // Nilai minimum untuk int8
+		{-128, []byte{128}}, // Nilai minimum untuk int8
 		{-1, []byte{255}},   // Nilai negatif
 		{0, []byte{0}},      // Nilai nol
 		{1, []byte{1}},      // Nilai positif kecil
@@ -244,6 +245,143 @@ func TestInt64ToByte(t *testing.T) {
 	}
 }
 
+// TestFloat32ToByte menguji fungsi Float32ToByte dengan berbagai nilai.
+// Fungsi ini memeriksa apakah hasil konversi dari float32 ke []byte sesuai dengan format IEEE-754 big-endian.
+/*
+	1. Test Structure: Struktur tests berisi kombinasi nilai input dan output yang diharapkan untuk pengujian.
+	2. Kasus Uji: Mencakup nol, nilai negatif, NaN, +Inf, dan nilai hingga terbesar untuk float32.
+	3. Comparing Results: Fungsi equal digunakan untuk membandingkan dua slice byte, memastikan hasilnya sesuai dengan yang diharapkan.
+*/
+func TestFloat32ToByte(t *testing.T) {
+	tests := []struct {
+		input  float32
+		output []byte
+	}{
+		{0, []byte{0, 0, 0, 0}},                        // Nilai nol
+		{-1.5, []byte{191, 192, 0, 0}},                 // Nilai negatif
+		{float32(math.NaN()), []byte{127, 192, 0, 0}},  // NaN
+		{float32(math.Inf(1)), []byte{127, 128, 0, 0}}, // +Inf
+		{math.MaxFloat32, []byte{127, 127, 255, 255}},  // Nilai hingga terbesar untuk float32
+	}
+
+	for _, test := range tests {
+		result := lib.Float32ToByte(test.input)
+		if !equal(result, test.output) {
+			t.Errorf("Float32ToByte(%v) = %v; expected %v", test.input, result, test.output)
+		}
+	}
+}
+
+// TestFloat64ToByte menguji fungsi Float64ToByte dengan berbagai nilai.
+// Fungsi ini memeriksa apakah hasil konversi dari float64 ke []byte sesuai dengan format IEEE-754 big-endian.
+/*
+	1. Test Structure: Struktur tests berisi kombinasi nilai input dan output yang diharapkan untuk pengujian.
+	2. Kasus Uji: Mencakup nol, nilai negatif, NaN, +Inf, dan nilai hingga terbesar untuk float64.
+	3. Comparing Results: Fungsi equal digunakan untuk membandingkan dua slice byte, memastikan hasilnya sesuai dengan yang diharapkan.
+*/
+func TestFloat64ToByte(t *testing.T) {
+	tests := []struct {
+		input  float64
+		output []byte
+	}{
+		{0, []byte{0, 0, 0, 0, 0, 0, 0, 0}},                               // Nilai nol
+		{-1.5, []byte{191, 248, 0, 0, 0, 0, 0, 0}},                        // Nilai negatif
+		{math.NaN(), []byte{127, 248, 0, 0, 0, 0, 0, 1}},                  // NaN
+		{math.Inf(1), []byte{127, 240, 0, 0, 0, 0, 0, 0}},                 // +Inf
+		{math.MaxFloat64, []byte{127, 239, 255, 255, 255, 255, 255, 255}}, // Nilai hingga terbesar untuk float64
+	}
+
+	for _, test := range tests {
+		result := lib.Float64ToByte(test.input)
+		if !equal(result, test.output) {
+			t.Errorf("Float64ToByte(%v) = %v; expected %v", test.input, result, test.output)
+		}
+	}
+}
+
+// TestByteToNumber menguji fungsi ByteTo* dengan memasangkan setiap encoder dengan decoder-nya.
+// Fungsi ini memeriksa apakah nilai yang diubah ke []byte dapat dikembalikan tanpa perubahan.
+/*
+	1. Test Structure: Struktur tests berisi nama kasus dan fungsi round-trip untuk setiap tipe.
+	2. Kasus Uji: Mencakup nilai minimum, nol, dan maksimum untuk setiap tipe, termasuk nilai negatif two's complement.
+	3. Comparing Results: Nilai hasil decoder dibandingkan dengan nilai awal.
+*/
+func TestByteToNumber(t *testing.T) {
+	tests := []struct {
+		name string
+		ok   func() bool
+	}{
+		{"uint8", func() bool { v, err := lib.ByteToUint8(lib.Uint8ToByte(255)); return err == nil && v == 255 }},
+		{"uint16", func() bool { v, err := lib.ByteToUint16(lib.Uint16ToByte(65535)); return err == nil && v == 65535 }},
+		{"uint32", func() bool {
+			v, err := lib.ByteToUint32(lib.Uint32ToByte(math.MaxUint32))
+			return err == nil && v == math.MaxUint32
+		}},
+		{"uint64", func() bool {
+			v, err := lib.ByteToUint64(lib.Uint64ToByte(math.MaxUint64))
+			return err == nil && v == math.MaxUint64
+		}},
+		{"int8 min", func() bool { v, err := lib.ByteToInt8(lib.Int8ToByte(-128)); return err == nil && v == -128 }},
+		{"int8 max", func() bool { v, err := lib.ByteToInt8(lib.Int8ToByte(127)); return err == nil && v == 127 }},
+		{"int16 min", func() bool {
+			v, err := lib.ByteToInt16(lib.Int16ToByte(math.MinInt16))
+			return err == nil && v == math.MinInt16
+		}},
+		{"int16 zero", func() bool { v, err := lib.ByteToInt16(lib.Int16ToByte(0)); return err == nil && v == 0 }},
+		{"int32 min", func() bool {
+			v, err := lib.ByteToInt32(lib.Int32ToByte(math.MinInt32))
+			return err == nil && v == math.MinInt32
+		}},
+		{"int32 max", func() bool {
+			v, err := lib.ByteToInt32(lib.Int32ToByte(math.MaxInt32))
+			return err == nil && v == math.MaxInt32
+		}},
+		{"int64 min", func() bool {
+			v, err := lib.ByteToInt64(lib.Int64ToByte(math.MinInt64))
+			return err == nil && v == math.MinInt64
+		}},
+		{"int64 -1", func() bool { v, err := lib.ByteToInt64(lib.Int64ToByte(-1)); return err == nil && v == -1 }},
+	}
+
+	for _, test := range tests {
+		if !test.ok() {
+			t.Errorf("round-trip %s failed", test.name)
+		}
+	}
+
+	// Slice yang terlalu pendek menghasilkan kesalahan
+	if _, err := lib.ByteToUint64([]byte{1, 2, 3}); err == nil {
+		t.Error("ByteToUint64 with 3 bytes; expected error")
+	}
+	if _, err := lib.ByteToInt8(nil); err == nil {
+		t.Error("ByteToInt8 with empty slice; expected error")
+	}
+}
+
+// TestBoolToByte menguji fungsi BoolToByte dengan kedua nilai bool.
+// Fungsi ini memeriksa apakah hasil konversi dari bool ke []byte sesuai dengan yang diharapkan.
+/*
+	1. Test Structure: Struktur tests berisi kombinasi nilai input dan output yang diharapkan untuk pengujian.
+	2. Kasus Uji: Nilai true dan false.
+	3. Comparing Results: Fungsi equal digunakan untuk membandingkan dua slice byte, memastikan hasilnya sesuai dengan yang diharapkan.
+*/
+func TestBoolToByte(t *testing.T) {
+	tests := []struct {
+		input  bool
+		output []byte
+	}{
+		{true, []byte{1}},  // Nilai true
+		{false, []byte{0}}, // Nilai false
+	}
+
+	for _, test := range tests {
+		result := lib.BoolToByte(test.input)
+		if !equal(result, test.output) {
+			t.Errorf("BoolToByte(%v) = %v; expected %v", test.input, result, test.output)
+		}
+	}
+}
+
 // TestStringToByte menguji fungsi StringToByte dengan berbagai nilai string.
 // Fungsi ini memeriksa apakah hasil konversi dari string ke []byte sesuai dengan yang diharapkan.
 /*
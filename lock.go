@@ -0,0 +1,90 @@
+// Copyright (c) 2024, Jasakode Authors.
+// All rights reserved.
+// Use of this source code is governed by a BSD 3-Clause
+// license that can be found in the LICENSE file.
+
+package cago
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+
+	"github.com/jasakode/cago/store"
+)
+
+// Lock mengambil lock sederhana dengan key yang diberikan, seperti SET NX PX
+// pada Redis. Lock hanya berhasil jika key belum ada atau sudah kedaluwarsa,
+// dan nilainya diisi token acak yang harus diberikan ke Unlock. Lock yang tidak
+// dilepas akan kedaluwarsa setelah ttl sehingga dapat diambil pemanggil lain.
+//
+// Parameter:
+//   - key (string): Key yang digunakan sebagai lock.
+//   - ttl (uint64): Masa berlaku lock dalam milidetik. Nilai 0 berarti lock
+//     tidak pernah kedaluwarsa dan hanya dapat dilepas dengan Unlock.
+//
+// Mengembalikan:
+//   - string: Token pemilik lock, atau string kosong jika lock gagal diambil.
+//   - bool: True jika lock berhasil diambil.
+func (app *App) Lock(key string, ttl uint64) (string, bool) {
+	if err := app.checkKey(key); err != nil {
+		app.logf("cago: lock: %v", err)
+		return "", false
+	}
+	buf := make([]byte, 16)
+	if _, err := rand.Read(buf); err != nil {
+		app.logf("cago: lock %q: generating token: %v", key, err)
+		return "", false
+	}
+	token := hex.EncodeToString(buf)
+
+	defer app.dispatch()
+	s := app.shardFor(key)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if old, ok := s.data[key]; ok && !app.expiredEntry(key, old, app.now()) {
+		return "", false
+	}
+	if err := app.persist(key, store.NewStoreAt([]byte(token), app.now(), ttl).SetKind(store.KindString)); err != nil {
+		app.logf("cago: lock %q: %v", key, err)
+		return "", false
+	}
+	return token, true
+}
+
+// Lock menjalankan App.Lock pada instance global yang dibuat oleh New.
+func Lock(key string, ttl uint64) (string, bool) {
+	return app.Lock(key, ttl)
+}
+
+// Unlock melepas lock yang diambil oleh Lock. Lock hanya dihapus jika token
+// sesuai, sehingga pemanggil yang lock-nya sudah kedaluwarsa dan diambil alih
+// pemanggil lain tidak dapat melepas lock milik pemanggil tersebut.
+//
+// Parameter:
+//   - key (string): Key yang digunakan sebagai lock.
+//   - token (string): Token yang dikembalikan oleh Lock.
+//
+// Mengembalikan:
+//   - bool: True jika lock ditemukan, belum kedaluwarsa, dan token sesuai.
+func (app *App) Unlock(key, token string) bool {
+	defer app.dispatch()
+	s := app.shardFor(key)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	old, ok := s.data[key]
+	if !ok || app.expiredEntry(key, old, app.now()) || old.Text() != token {
+		return false
+	}
+	app.deleteEntry(key, EvictRemoved)
+	if app.db != nil {
+		if err := app.db.RemoveByKey(key); err != nil {
+			app.logf("cago: unlock %q: %v", key, err)
+		}
+	}
+	return true
+}
+
+// Unlock menjalankan App.Unlock pada instance global yang dibuat oleh New.
+func Unlock(key, token string) bool {
+	return app.Unlock(key, token)
+}
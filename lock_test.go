@@ -0,0 +1,75 @@
+// Copyright (c) 2024, Jasakode Authors.
+// All rights reserved.
+// Use of this source code is governed by a BSD 3-Clause
+// license that can be found in the LICENSE file.
+
+package cago_test
+
+import (
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/jasakode/cago"
+)
+
+func TestLock(t *testing.T) {
+	var now atomic.Int64
+	now.Store(1_700_000_000_000)
+	if err := cago.New(cago.Config{TimeoutCheck: 60000, Clock: now.Load}); err != nil {
+		t.Fatal(err)
+	}
+
+	token, ok := cago.Lock("job", 60000)
+	if !ok || token == "" {
+		t.Fatal("expected first Lock to succeed")
+	}
+	if _, ok := cago.Lock("job", 60000); ok {
+		t.Error("expected second Lock to fail while held")
+	}
+
+	// Token yang salah tidak melepas lock
+	if cago.Unlock("job", "wrong") {
+		t.Error("expected Unlock with wrong token to fail")
+	}
+	if !cago.Exist("job") {
+		t.Error("expected lock to stay after wrong token")
+	}
+	if !cago.Unlock("job", token) {
+		t.Error("expected Unlock with correct token to succeed")
+	}
+	if cago.Unlock("job", token) {
+		t.Error("expected second Unlock to fail")
+	}
+
+	// Lock yang kedaluwarsa dapat diambil pemanggil lain, dan token lama tidak berlaku lagi
+	stale, _ := cago.Lock("job", 1)
+	now.Add(1)
+	fresh, ok := cago.Lock("job", 60000)
+	if !ok || fresh == stale {
+		t.Fatalf("expected expired lock to be re-acquired with a new token, got %q (ok=%v)", fresh, ok)
+	}
+	if cago.Unlock("job", stale) {
+		t.Error("expected stale token not to release the new lock")
+	}
+
+	// Hanya satu dari banyak pemanggil yang mendapatkan lock
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	winners := 0
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, ok := cago.Lock("race", 60000); ok {
+				mu.Lock()
+				winners++
+				mu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+	if winners != 1 {
+		t.Errorf("expected exactly one winner, got %d", winners)
+	}
+}
@@ -0,0 +1,126 @@
+// Copyright (c) 2024, Jasakode Authors.
+// All rights reserved.
+// Use of this source code is governed by a BSD 3-Clause
+// license that can be found in the LICENSE file.
+
+package cago
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/jasakode/cago/store"
+)
+
+// Namespaced adalah tampilan cache yang menambahkan prefix "nama:" ke setiap
+// key, sehingga beberapa subsistem dapat berbagi satu cache tanpa bentrok.
+// Key yang diberikan ke dan dikembalikan dari Namespaced selalu tanpa prefix.
+type Namespaced struct {
+	c      *App   // Instance cache, atau nil untuk instance global.
+	prefix string // Nama namespace diikuti ":".
+}
+
+// Namespace membuat Namespaced di atas instance ini.
+//
+// Parameter:
+//   - name (string): Nama namespace. Key disimpan sebagai name + ":" + key.
+//
+// Mengembalikan:
+//   - *Namespaced: Namespace yang baru.
+func (app *App) Namespace(name string) *Namespaced {
+	return &Namespaced{c: app, prefix: name + ":"}
+}
+
+// Namespace membuat Namespaced di atas instance global. Namespace ini selalu
+// memakai instance global yang aktif, termasuk setelah New dipanggil ulang.
+func Namespace(name string) *Namespaced {
+	return &Namespaced{prefix: name + ":"}
+}
+
+// app mengembalikan instance cache yang dipakai namespace.
+func (ns *Namespaced) app() *App {
+	if ns.c != nil {
+		return ns.c
+	}
+	return app
+}
+
+// Set menjalankan App.Set dengan key di dalam namespace.
+func (ns *Namespaced) Set(key string, value store.Compare, maxAge ...uint64) error {
+	return ns.app().Set(ns.prefix+key, value, maxAge...)
+}
+
+// Put menjalankan App.Put dengan key di dalam namespace.
+func (ns *Namespaced) Put(key string, value store.Compare, maxAge ...uint64) error {
+	return ns.app().Put(ns.prefix+key, value, maxAge...)
+}
+
+// Get mengambil store mentah dengan key di dalam namespace, sama seperti
+// App.GetAny. Gunakan NewTypedCache dengan prefix yang sama untuk akses bertipe.
+//
+// Parameter:
+//   - key (string): Key tanpa prefix namespace.
+//
+// Mengembalikan:
+//   - store.Store: Store yang ditemukan, atau nil jika tidak ditemukan atau sudah kedaluwarsa.
+func (ns *Namespaced) Get(key string) store.Store {
+	value, ok := ns.app().getStore(ns.prefix + key)
+	if !ok {
+		return nil
+	}
+	return value
+}
+
+// Exist menjalankan App.Exist dengan key di dalam namespace.
+func (ns *Namespaced) Exist(key string) bool {
+	return ns.app().Exist(ns.prefix + key)
+}
+
+// Remove menjalankan App.Remove dengan key di dalam namespace.
+func (ns *Namespaced) Remove(key string) bool {
+	return ns.app().Remove(ns.prefix + key)
+}
+
+// RemovePrefix menghapus semua key di dalam namespace yang diawali prefix.
+// Prefix kosong menghapus seluruh isi namespace tanpa menyentuh namespace lain.
+func (ns *Namespaced) RemovePrefix(prefix string) int {
+	return ns.app().RemovePrefix(ns.prefix + prefix)
+}
+
+// Keys mengembalikan key yang belum kedaluwarsa di dalam namespace, tanpa
+// prefix dan diurutkan secara leksikografis.
+//
+// Mengembalikan:
+//   - []string: Daftar key di dalam namespace.
+func (ns *Namespaced) Keys() []string {
+	keys := []string{}
+	ns.Range(func(key string, value store.Store) bool {
+		keys = append(keys, key)
+		return true
+	})
+	sort.Strings(keys)
+	return keys
+}
+
+// Range menjalankan App.Range untuk entri di dalam namespace. Key diberikan
+// ke fn tanpa prefix namespace.
+func (ns *Namespaced) Range(fn func(key string, value store.Store) bool) {
+	ns.app().Range(func(key string, value store.Store) bool {
+		name, ok := strings.CutPrefix(key, ns.prefix)
+		if !ok {
+			return true
+		}
+		return fn(name, value)
+	})
+}
+
+// Export menjalankan App.Export hanya untuk entri di dalam namespace.
+// Key ditulis tanpa prefix, sehingga hasilnya dapat dimuat ke namespace lain dengan Import.
+func (ns *Namespaced) Export() ([]byte, error) {
+	return ns.app().export(ns.prefix)
+}
+
+// Import menjalankan App.Import dengan prefix namespace ditambahkan ke setiap key.
+func (ns *Namespaced) Import(data []byte, overwrite bool) error {
+	return ns.app().importEntries(data, ns.prefix, overwrite)
+}
@@ -0,0 +1,62 @@
+// Copyright (c) 2024, Jasakode Authors.
+// All rights reserved.
+// Use of this source code is governed by a BSD 3-Clause
+// license that can be found in the LICENSE file.
+
+package cago_test
+
+import (
+	"testing"
+
+	"github.com/jasakode/cago"
+)
+
+func TestNamespace(t *testing.T) {
+	if err := cago.New(); err != nil {
+		t.Fatal(err)
+	}
+	sessions := cago.Namespace("session")
+	users := cago.Namespace("user")
+
+	// Key logis yang sama tidak bentrok di antara namespace
+	sessions.Set("42", "token")
+	users.Set("42", "alice")
+	users.Set("43", "bob")
+	cago.Set("42", "global")
+	if v := sessions.Get("42"); v == nil || v.Text() != "token" {
+		t.Errorf("expected token, got %v", v)
+	}
+	if v := users.Get("42"); v == nil || v.Text() != "alice" {
+		t.Errorf("expected alice, got %v", v)
+	}
+	if !cago.Exist("session:42") || !cago.Exist("user:42") {
+		t.Error("expected keys to be stored as name:key")
+	}
+	if keys := users.Keys(); len(keys) != 2 || keys[0] != "42" || keys[1] != "43" {
+		t.Errorf("expected [42 43], got %v", keys)
+	}
+
+	// Export hanya berisi entri namespace tanpa prefix, dan Import memakai prefix tujuan
+	data, err := users.Export()
+	if err != nil {
+		t.Fatal(err)
+	}
+	backup := cago.Namespace("backup")
+	if err := backup.Import(data, false); err != nil {
+		t.Fatal(err)
+	}
+	if keys := backup.Keys(); len(keys) != 2 || !cago.Exist("backup:43") {
+		t.Errorf("expected imported keys under backup:, got %v", keys)
+	}
+
+	// RemovePrefix dan Remove tidak menyentuh namespace lain
+	if n := users.RemovePrefix(""); n != 2 {
+		t.Errorf("expected 2 removed, got %d", n)
+	}
+	if !sessions.Exist("42") || !cago.Exist("42") || users.Exist("42") {
+		t.Error("expected only the user namespace to be cleared")
+	}
+	if !sessions.Remove("42") || sessions.Exist("42") {
+		t.Error("expected session:42 to be removed")
+	}
+}
@@ -0,0 +1,120 @@
+// Copyright (c) 2024, Jasakode Authors.
+// All rights reserved.
+// Use of this source code is governed by a BSD 3-Clause
+// license that can be found in the LICENSE file.
+
+package cago
+
+import (
+	"container/list"
+	"hash/maphash"
+	"sync"
+
+	"github.com/jasakode/cago/store"
+)
+
+// shard adalah satu bagian dari cache dengan map dan lock sendiri. Setiap key
+// selalu berada di shard yang sama, lihat shardFor, sehingga operasi atas satu
+// key cukup memegang lock shard tersebut. Urutan EvictionPolicy, jumlah akses,
+// dan catatan penghapusan juga disimpan per shard.
+//
+// Field-field:
+//   - mu: Lock untuk semua field shard.
+//   - data: Entri cache di dalam shard.
+//   - data_size: Ukuran total entri beserta key di dalam shard.
+type shard struct {
+	mu        sync.RWMutex             // Mutex untuk semua field di dalam shard.
+	data      map[string]store.Store   // Entri cache di dalam shard.
+	data_size uint64                   // ukuran total data berserta key di dalam shard
+	removed   map[string]uint64        // Waktu penghapusan setiap key dalam milidetik, digunakan oleh DiffSince.
+	pruned    uint64                   // Waktu penghapusan terbaru yang catatannya sudah dibuang.
+	order     *list.List               // Daftar key sesuai EvictionPolicy, kandidat penghapusan di depan.
+	elems     map[string]*list.Element // Posisi setiap key di dalam order.
+	freq      map[string]uint64        // Jumlah akses setiap key untuk PolicyLFU.
+	access    map[string]uint64        // Waktu akses terakhir setiap key untuk SlidingTTL, dalam milidetik.
+	accesses  uint64                   // Jumlah akses sejak peluruhan freq terakhir.
+	flights   map[string]*flight       // Perhitungan GetOrSet yang sedang berjalan per key.
+}
+
+// newShard membuat shard kosong.
+func newShard() *shard {
+	return &shard{
+		data:    make(map[string]store.Store),
+		removed: make(map[string]uint64),
+		order:   list.New(),
+		elems:   make(map[string]*list.Element),
+		freq:    make(map[string]uint64),
+		access:  make(map[string]uint64),
+		flights: make(map[string]*flight),
+	}
+}
+
+// shardFor mengembalikan shard tempat key disimpan. Key dibagi berdasarkan
+// hash dengan seed acak per instance, sehingga pembagiannya tidak dapat
+// ditebak dari luar. Dengan satu shard, hash tidak dihitung sama sekali.
+func (app *App) shardFor(key string) *shard {
+	if len(app.shards) == 1 {
+		return app.shards[0]
+	}
+	return app.shards[maphash.String(app.seed, key)%uint64(len(app.shards))]
+}
+
+// lockAll mengambil write lock semua shard, selalu dengan urutan yang sama
+// agar tidak terjadi deadlock dengan pemanggil lain yang juga mengunci semua shard.
+// Dipakai oleh operasi atas banyak key yang harus terlihat atomik, seperti
+// Txn, MSet, dan Clear.
+func (app *App) lockAll() {
+	for _, s := range app.shards {
+		s.mu.Lock()
+	}
+}
+
+// unlockAll melepas write lock yang diambil oleh lockAll.
+func (app *App) unlockAll() {
+	for i := len(app.shards) - 1; i >= 0; i-- {
+		app.shards[i].mu.Unlock()
+	}
+}
+
+// rlockAll mengambil read lock semua shard dengan urutan yang sama seperti
+// lockAll, sehingga pembaca melihat seluruh cache pada satu titik waktu.
+func (app *App) rlockAll() {
+	for _, s := range app.shards {
+		s.mu.RLock()
+	}
+}
+
+// runlockAll melepas read lock yang diambil oleh rlockAll.
+func (app *App) runlockAll() {
+	for i := len(app.shards) - 1; i >= 0; i-- {
+		app.shards[i].mu.RUnlock()
+	}
+}
+
+// memLimit mengembalikan batas ukuran data satu shard dalam byte, yaitu
+// MAX_MEM dibagi rata ke semua shard.
+func (app *App) memLimit() uint64 {
+	return uint64(app.config.MAX_MEM) / 8 / uint64(len(app.shards)) // MAX_MEM dinyatakan dalam bit
+}
+
+// full memeriksa apakah shard s tidak dapat menerima store tanpa menghapus
+// entri lain karena MaxEntries atau MAX_MEM. Fungsi ini harus dipanggil ketika
+// lock s sedang dipegang.
+func (app *App) full(s *shard, key string, data store.Store) bool {
+	if s.data_size+uint64(len(key)+len(data)) > app.memLimit() {
+		return true
+	}
+	return app.atCapacity(s)
+}
+
+// atCapacity memeriksa apakah jumlah entri shard s sudah mencapai bagiannya
+// dari MaxEntries. MaxEntries dibagi rata ke semua shard dan dibulatkan ke
+// atas, sehingga setiap shard dapat menampung minimal satu entri.
+// Fungsi ini harus dipanggil ketika lock s sedang dipegang.
+func (app *App) atCapacity(s *shard) bool {
+	if app.config.MaxEntries <= 0 {
+		return false
+	}
+	n := len(app.shards)
+	return len(s.data) >= (app.config.MaxEntries+n-1)/n
+}
@@ -0,0 +1,312 @@
+// Copyright (c) 2024, Jasakode Authors.
+// All rights reserved.
+// Use of this source code is governed by a BSD 3-Clause
+// license that can be found in the LICENSE file.
+
+package cago_test
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/jasakode/cago"
+	"github.com/jasakode/cago/store"
+)
+
+func TestShards(t *testing.T) {
+	single, err := cago.NewCache()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer single.Close()
+	c, err := cago.NewCache(cago.Config{Shards: 8})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer c.Close()
+	for i := 0; i < 200; i++ {
+		key := fmt.Sprint("key-", i)
+		single.Put(key, i)
+		if err := c.Put(key, i); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// Setiap key dibaca dari shard yang sama dengan tempat key disimpan
+	for i := 0; i < 200; i++ {
+		if n, ok := c.GetInt(fmt.Sprint("key-", i)); !ok || n != i {
+			t.Fatalf("expected %d, got %d (%v)", i, n, ok)
+		}
+	}
+	// Fungsi atas seluruh cache melihat semua shard
+	if n := c.Stats().Entries; n != 200 {
+		t.Errorf("expected 200 entries, got %d", n)
+	}
+	if c.Size() != single.Size() {
+		t.Errorf("expected size %d, got %d", single.Size(), c.Size())
+	}
+	if keys := c.FindKeys(func(any) bool { return true }); len(keys) != 200 {
+		t.Errorf("expected 200 keys, got %d", len(keys))
+	}
+	seen := 0
+	c.Range(func(string, store.Store) bool {
+		seen++
+		return true
+	})
+	if seen != 200 {
+		t.Errorf("expected Range to visit 200 entries, got %d", seen)
+	}
+
+	if n := c.RemovePrefix("key-1"); n != 111 {
+		t.Errorf("expected 111 keys removed by prefix, got %d", n)
+	}
+	if err := c.Clear(); err != nil {
+		t.Fatal(err)
+	}
+	if n := c.Stats().Entries; n != 0 {
+		t.Errorf("expected an empty cache after Clear, got %d entries", n)
+	}
+}
+
+func TestShardsCapacity(t *testing.T) {
+	c, err := cago.NewCache(cago.Config{Shards: 4, MaxEntries: 8})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer c.Close()
+	for i := 0; i < 100; i++ {
+		c.Put(fmt.Sprint("key-", i), i)
+	}
+
+	// MaxEntries dibagi rata, sehingga setiap shard menampung paling banyak 2 entri
+	stats := c.Stats()
+	if stats.Entries == 0 || stats.Entries > 8 {
+		t.Errorf("expected between 1 and 8 entries, got %d", stats.Entries)
+	}
+	if stats.Evictions != uint64(100-stats.Entries) {
+		t.Errorf("expected %d evictions, got %d", 100-stats.Entries, stats.Evictions)
+	}
+	if !c.Exist("key-99") {
+		t.Error("expected the latest key to stay cached")
+	}
+}
+
+func TestShardsReload(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "shards.db")
+	c, err := cago.NewCache(cago.Config{Path: path, Shards: 4})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 50; i++ {
+		c.Put(fmt.Sprint("key-", i), i)
+	}
+	if err := c.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	// Pembagian shard tidak disimpan di database, sehingga jumlahnya boleh berubah
+	c, err = cago.NewCache(cago.Config{Path: path, Shards: 3})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer c.Close()
+	for i := 0; i < 50; i++ {
+		if n, ok := c.GetInt(fmt.Sprint("key-", i)); !ok || n != i {
+			t.Fatalf("expected %d after reload, got %d (%v)", i, n, ok)
+		}
+	}
+}
+
+func TestShardsBatch(t *testing.T) {
+	if err := cago.New(cago.Config{Shards: 8, MAX_MEM: 8 * 8 * 1000}); err != nil {
+		t.Fatal(err)
+	}
+	defer cago.Close()
+
+	// Txn dan MSet atas key di banyak shard diterapkan seluruhnya
+	err := cago.Txn(func(tx *cago.Tx) error {
+		for i := 0; i < 20; i++ {
+			if err := tx.Put(fmt.Sprint("key-", i), i); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n := cago.Stats().Entries; n != 20 {
+		t.Errorf("expected 20 entries after Txn, got %d", n)
+	}
+	if rs := cago.MGet("key-0", "key-19", "missing"); len(rs) != 2 {
+		t.Errorf("expected 2 values from MGet, got %d", len(rs))
+	}
+
+	// Batas memori berlaku per shard: satu nilai yang lebih besar dari bagian
+	// shard ditolak, dan tidak ada nilai lain di dalam batch yang disimpan
+	batch := map[string]store.Compare{"small": "x", "large": strings.Repeat("x", 1200)}
+	if err := cago.MSet(batch); !errors.Is(err, cago.ErrMemoryLimit) {
+		t.Fatalf("expected ErrMemoryLimit, got %v", err)
+	}
+	if cago.Exist("small") || cago.Exist("large") {
+		t.Error("expected nothing from the failed batch to be stored")
+	}
+
+	// DiffSince mengumpulkan perubahan dan tombstone dari semua shard
+	cago.Remove("key-0")
+	records, err := cago.DiffSince(0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	removed := 0
+	for _, r := range records {
+		if r.Removed {
+			removed++
+		}
+	}
+	if len(records) != 20 || removed != 1 {
+		t.Errorf("expected 19 live records and 1 tombstone, got %d records with %d tombstones", len(records), removed)
+	}
+}
+
+func TestShardsConcurrent(t *testing.T) {
+	if err := cago.New(cago.Config{Shards: 16, MaxEntries: 64, EvictionPolicy: cago.PolicyLRU}); err != nil {
+		t.Fatal(err)
+	}
+	defer cago.Close()
+
+	// Operasi atas satu key dan atas semua shard berjalan bersamaan tanpa deadlock
+	var wg sync.WaitGroup
+	for g := 0; g < 8; g++ {
+		wg.Add(1)
+		go func(g int) {
+			defer wg.Done()
+			for i := 0; i < 200; i++ {
+				key := fmt.Sprint("key-", (g*200+i)%100)
+				switch i % 5 {
+				case 0:
+					cago.Put(key, i)
+				case 1:
+					cago.Get[int](key)
+				case 2:
+					cago.Remove(key)
+				case 3:
+					cago.MSet(map[string]store.Compare{key: i, "other": i})
+				default:
+					cago.Txn(func(tx *cago.Tx) error {
+						tx.Put(key, i)
+						tx.Remove("other")
+						return nil
+					})
+				}
+			}
+		}(g)
+	}
+	wg.Wait()
+	if n := cago.Stats().Entries; n > 64 {
+		t.Errorf("expected at most 64 entries, got %d", n)
+	}
+}
+
+func TestShardsExpire(t *testing.T) {
+	var now atomic.Int64
+	now.Store(1000)
+	expired := 0
+	c, err := cago.NewCache(cago.Config{
+		Shards:       4,
+		Clock:        now.Load,
+		TimeoutCheck: 60000,
+		OnExpire:     func(string, store.Store) { expired++ },
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer c.Close()
+	for i := 0; i < 40; i++ {
+		c.Put(fmt.Sprint("key-", i), i, uint64(100*(i%2+1)))
+	}
+
+	// Pemeriksaan kedaluwarsa menjangkau setiap shard
+	now.Add(150)
+	if n := c.Sweep(); n != 20 || expired != 20 {
+		t.Errorf("expected 20 expired entries, got %d (%d callbacks)", n, expired)
+	}
+	now.Add(100)
+	if n := c.RemoveExpired(); n != 20 {
+		t.Errorf("expected the remaining 20 entries to expire, got %d", n)
+	}
+	if n := c.Stats().Entries; n != 0 {
+		t.Errorf("expected an empty cache, got %d entries", n)
+	}
+}
+
+func TestCleanWorkers(t *testing.T) {
+	var now atomic.Int64
+	now.Store(1000)
+	var expired atomic.Int64
+	c, err := cago.NewCache(cago.Config{
+		Shards:       8,
+		CleanWorkers: 3,
+		Clock:        now.Load,
+		TimeoutCheck: 60000,
+		OnExpire:     func(string, store.Store) { expired.Add(1) },
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer c.Close()
+	for i := 0; i < 100; i++ {
+		c.Put(fmt.Sprint("key-", i), i, uint64(100*(i%2+1)))
+	}
+
+	// Worker membersihkan semua shard, dan setiap entri hanya dihapus sekali
+	now.Add(150)
+	if n := c.Sweep(); n != 50 || expired.Load() != 50 {
+		t.Errorf("expected 50 expired entries, got %d (%d callbacks)", n, expired.Load())
+	}
+	now.Add(100)
+	if n := c.Sweep(); n != 50 {
+		t.Errorf("expected the remaining 50 entries to expire, got %d", n)
+	}
+	if n := c.Stats().Entries; n != 0 {
+		t.Errorf("expected an empty cache, got %d entries", n)
+	}
+}
+
+func BenchmarkSweep(b *testing.B) {
+	for _, workers := range []int{1, 8} {
+		b.Run(fmt.Sprint("workers-", workers), func(b *testing.B) {
+			var now atomic.Int64
+			now.Store(1000)
+			c, err := cago.NewCache(cago.Config{
+				Shards:       64,
+				CleanWorkers: workers,
+				Clock:        now.Load,
+				TimeoutCheck: 60000,
+			})
+			if err != nil {
+				b.Fatal(err)
+			}
+			defer c.Close()
+			for i := 0; i < b.N; i++ {
+				// Setiap pemeriksaan menghapus setengah dari entri yang baru disimpan
+				b.StopTimer()
+				for k := 0; k < 20000; k++ {
+					c.Put(fmt.Sprint("key-", k), k, uint64(100*(k%2+1)))
+				}
+				now.Add(150)
+				b.StartTimer()
+				c.Sweep()
+				b.StopTimer()
+				now.Add(100)
+				c.Sweep()
+				b.StartTimer()
+			}
+		})
+	}
+}
@@ -0,0 +1,277 @@
+// Copyright (c) 2024, Jasakode Authors.
+// All rights reserved.
+// Use of this source code is governed by a BSD 3-Clause
+// license that can be found in the LICENSE file.
+
+package cago
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/jasakode/cago/store"
+)
+
+// ExportEntry adalah satu entri cache di dalam hasil Export.
+//
+// Field-field:
+//   - Key: Key dari entri.
+//   - Value: Payload entri apa adanya, ditulis sebagai base64 di dalam JSON.
+//   - Created: Waktu entri dibuat.
+//   - Updated: Waktu pembaruan terakhir entri, sama dengan Created jika belum pernah diperbarui.
+//   - Expires: Waktu entri kedaluwarsa, atau nol jika entri tidak pernah kedaluwarsa.
+//   - Kind: Jenis nilai asal, dihilangkan untuk entri tanpa tag.
+type ExportEntry struct {
+	Key     string          `json:"key"`
+	Value   []byte          `json:"value"`
+	Created time.Time       `json:"created"`
+	Updated time.Time       `json:"updated"`
+	Expires time.Time       `json:"expires"`
+	Kind    store.StoreKind `json:"kind,omitempty"`
+}
+
+// Export menyalin semua entri yang belum kedaluwarsa ke dalam JSON.
+// Hasilnya tidak bergantung pada format file SQLite sehingga dapat dipakai
+// sebagai cadangan dan dimuat kembali dengan Import.
+//
+// Mengembalikan:
+//   - []byte: Array JSON berisi ExportEntry yang diurutkan berdasarkan key.
+//   - error: Kesalahan jika data gagal diubah menjadi JSON.
+func (app *App) Export() ([]byte, error) {
+	return app.export("")
+}
+
+// export menyalin entri yang belum kedaluwarsa dengan key yang diawali prefix
+// ke dalam JSON. Prefix dibuang dari key di hasil ekspor.
+func (app *App) export(prefix string) ([]byte, error) {
+	now := app.now()
+	app.rlockAll()
+	n := 0
+	for _, s := range app.shards {
+		n += len(s.data)
+	}
+	entries := make([]ExportEntry, 0, n)
+	for _, s := range app.shards {
+		for key, value := range s.data {
+			name, ok := strings.CutPrefix(key, prefix)
+			if !ok || app.expiredEntry(key, value, now) {
+				continue
+			}
+			entry := ExportEntry{Key: name, Value: value.Bytes(), Kind: value.Kind()}
+			entry.Created, entry.Updated, entry.Expires = app.times(key, value)
+			entries = append(entries, entry)
+		}
+	}
+	app.runlockAll()
+
+	sort.Slice(entries, func(i, j int) bool { return entries[i].Key < entries[j].Key })
+	return json.Marshal(entries)
+}
+
+// Export menjalankan App.Export pada instance global yang dibuat oleh New.
+func Export() ([]byte, error) {
+	return app.Export()
+}
+
+// Import memuat entri dari hasil Export ke dalam cache dan database.
+// Entri yang sudah kedaluwarsa dilewati. Key yang sudah ada hanya ditimpa
+// jika overwrite bernilai true. Perubahan ke database ditulis dalam satu transaksi.
+//
+// Parameter:
+//   - data ([]byte): JSON yang dihasilkan oleh Export.
+//   - overwrite (bool): Jika true, entri yang sudah ada akan ditimpa.
+//
+// Mengembalikan:
+//   - error: Kesalahan jika JSON tidak valid, memori tidak cukup, atau database gagal ditulis.
+func (app *App) Import(data []byte, overwrite bool) error {
+	return app.importEntries(data, "", overwrite)
+}
+
+// importEntries memuat hasil Export dengan prefix ditambahkan ke setiap key.
+func (app *App) importEntries(data []byte, prefix string, overwrite bool) error {
+	var entries []ExportEntry
+	if err := json.Unmarshal(data, &entries); err != nil {
+		return err
+	}
+
+	defer app.dispatch()
+	app.lockAll()
+	defer app.unlockAll()
+	now := app.now()
+	ops := make([]writeOp, 0, len(entries))
+	for _, entry := range entries {
+		created := uint64(entry.Created.UnixMilli())
+		var maxAge uint64
+		if !entry.Expires.IsZero() {
+			expires := uint64(entry.Expires.UnixMilli())
+			if expires <= now || expires <= created {
+				continue
+			}
+			maxAge = expires - created
+		}
+		key := prefix + entry.Key
+		if old, ok := app.shardFor(key).data[key]; ok && !app.expiredEntry(key, old, now) && !overwrite {
+			continue
+		}
+
+		value := store.NewStoreAt(entry.Value, created, maxAge).SetKind(entry.Kind)
+		if updated := uint64(entry.Updated.UnixMilli()); updated != created {
+			value = value.SetUpdateAt(updated)
+		}
+		if err := app.setEntry(key, value); err != nil {
+			return err
+		}
+		ops = append(ops, writeOp{key: key, data: value})
+	}
+	if app.db != nil {
+		return app.db.apply(ops)
+	}
+	return nil
+}
+
+// Import menjalankan App.Import pada instance global yang dibuat oleh New.
+func Import(data []byte, overwrite bool) error {
+	return app.Import(data, overwrite)
+}
+
+// SnapshotBinary menulis semua entri yang belum kedaluwarsa ke w dalam format
+// biner. Setiap entri ditulis sebagai panjang key (uint32), key, panjang store
+// (uint32), lalu blob store apa adanya, sehingga seluruh metadata ikut tersimpan.
+//
+// Parameter:
+//   - w (io.Writer): Tujuan penulisan snapshot.
+//
+// Mengembalikan:
+//   - error: Kesalahan jika penulisan ke w gagal.
+func (app *App) SnapshotBinary(w io.Writer) error {
+	bw := bufio.NewWriter(w)
+	now := app.now()
+	app.rlockAll()
+	defer app.runlockAll()
+	var size [4]byte
+	for _, s := range app.shards {
+		for key, value := range s.data {
+			if app.expiredEntry(key, value, now) {
+				continue
+			}
+			// Masa berlaku yang bergeser karena SlidingTTL ikut disimpan di MaxAge
+			if at, ok := s.access[key]; ok && at > value.CreateAt() {
+				value = value.SetMaxAge(app.deadline(key, value) - value.CreateAt())
+			}
+			for _, field := range [][]byte{[]byte(key), value} {
+				binary.BigEndian.PutUint32(size[:], uint32(len(field)))
+				if _, err := bw.Write(size[:]); err != nil {
+					return err
+				}
+				if _, err := bw.Write(field); err != nil {
+					return err
+				}
+			}
+		}
+	}
+	return bw.Flush()
+}
+
+// SnapshotBinary menjalankan App.SnapshotBinary pada instance global yang dibuat oleh New.
+func SnapshotBinary(w io.Writer) error {
+	return app.SnapshotBinary(w)
+}
+
+// RestoreBinary memuat entri dari snapshot yang ditulis oleh SnapshotBinary.
+// Entri yang sudah ada akan ditimpa, entri yang sudah kedaluwarsa dilewati,
+// dan perubahan ke database ditulis dalam satu transaksi.
+//
+// Parameter:
+//   - r (io.Reader): Sumber snapshot.
+//
+// Mengembalikan:
+//   - error: Kesalahan jika snapshot terpotong atau rusak, panjang key atau store
+//     melebihi MAX_MEM, memori tidak cukup, atau database gagal ditulis.
+func (app *App) RestoreBinary(r io.Reader) error {
+	br := bufio.NewReader(r)
+	// Entri yang lebih besar dari MAX_MEM tidak dapat dimuat, sehingga panjang
+	// di atas batas tersebut berarti snapshot rusak
+	limit := uint64(app.config.MAX_MEM) / 8 // MAX_MEM dinyatakan dalam bit
+	readField := func() ([]byte, error) {
+		var size [4]byte
+		if _, err := io.ReadFull(br, size[:]); err != nil {
+			return nil, err
+		}
+		n := binary.BigEndian.Uint32(size[:])
+		if uint64(n) > limit {
+			return nil, fmt.Errorf("field is %d bytes, limit is %d: %w", n, limit, ErrMemoryLimit)
+		}
+		// Buffer tumbuh sesuai data yang benar-benar terbaca, bukan sesuai panjang
+		// yang tertulis, agar snapshot terpotong tidak memesan memori sebesar n
+		var field bytes.Buffer
+		if _, err := io.CopyN(&field, br, int64(n)); err != nil {
+			return nil, io.ErrUnexpectedEOF
+		}
+		return field.Bytes(), nil
+	}
+
+	// Snapshot dibaca seluruhnya terlebih dahulu agar snapshot yang rusak
+	// tidak meninggalkan cache yang terisi sebagian
+	var ops []writeOp
+	for {
+		key, err := readField()
+		if err == io.EOF {
+			break
+		}
+		if errors.Is(err, ErrMemoryLimit) {
+			return fmt.Errorf("reading snapshot: %w", err)
+		}
+		if err != nil {
+			return fmt.Errorf("reading snapshot: %w", io.ErrUnexpectedEOF)
+		}
+		blob, err := readField()
+		if errors.Is(err, ErrMemoryLimit) {
+			return fmt.Errorf("reading snapshot: key %q: %w", key, err)
+		}
+		if err != nil {
+			return fmt.Errorf("reading snapshot: %w", io.ErrUnexpectedEOF)
+		}
+		value, err := store.ParseStoreChecked(blob)
+		if err != nil {
+			return fmt.Errorf("key %q: %w", key, err)
+		}
+		ops = append(ops, writeOp{key: string(key), data: value})
+	}
+
+	defer app.dispatch()
+	app.lockAll()
+	defer app.unlockAll()
+	now := app.now()
+	restored := ops[:0]
+	for _, op := range ops {
+		value := store.Store(op.data)
+		if expired(value, now) {
+			continue
+		}
+		value, err := app.upgrade(op.key, value)
+		if err != nil {
+			return err
+		}
+		if err := app.setEntry(op.key, value); err != nil {
+			return err
+		}
+		restored = append(restored, writeOp{key: op.key, data: value})
+	}
+	if app.db != nil {
+		return app.db.apply(restored)
+	}
+	return nil
+}
+
+// RestoreBinary menjalankan App.RestoreBinary pada instance global yang dibuat oleh New.
+func RestoreBinary(r io.Reader) error {
+	return app.RestoreBinary(r)
+}
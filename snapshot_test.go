@@ -0,0 +1,173 @@
+// Copyright (c) 2024, Jasakode Authors.
+// All rights reserved.
+// Use of this source code is governed by a BSD 3-Clause
+// license that can be found in the LICENSE file.
+
+package cago_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/jasakode/cago"
+)
+
+func TestExportImport(t *testing.T) {
+	var now atomic.Int64
+	now.Store(1_700_000_000_000)
+	src, err := cago.NewCache(cago.Config{Clock: now.Load})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer src.Close()
+	if err := src.Put("text", "hello"); err != nil {
+		t.Fatal(err)
+	}
+	if err := src.Put("num", 42, 60000); err != nil {
+		t.Fatal(err)
+	}
+	if err := src.Put("short", "gone", 1); err != nil {
+		t.Fatal(err)
+	}
+	now.Add(1)
+
+	data, err := src.Export()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var entries []cago.ExportEntry
+	if err := json.Unmarshal(data, &entries); err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 2 || entries[0].Key != "num" || entries[1].Key != "text" {
+		t.Fatalf("expected num and text to be exported, got %s", data)
+	}
+
+	dst, err := cago.NewCache(cago.Config{Clock: now.Load})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer dst.Close()
+	if err := dst.Put("text", "keep"); err != nil {
+		t.Fatal(err)
+	}
+	if err := dst.Import(data, false); err != nil {
+		t.Fatal(err)
+	}
+	if v := cago.GetFrom[string](dst, "text"); v == nil || *v != "keep" {
+		t.Errorf("expected existing key to be kept, got %v", v)
+	}
+	if v := cago.GetFrom[int](dst, "num"); v == nil || *v != 42 {
+		t.Errorf("expected 42, got %v", v)
+	}
+	created, _, expires, _ := src.GetMeta("num")
+	gotCreated, _, gotExpires, ok := dst.GetMeta("num")
+	if !ok || !gotCreated.Equal(created) || !gotExpires.Equal(expires) {
+		t.Errorf("expected metadata %v/%v, got %v/%v", created, expires, gotCreated, gotExpires)
+	}
+
+	if err := dst.Import(data, true); err != nil {
+		t.Fatal(err)
+	}
+	if v := cago.GetFrom[string](dst, "text"); v == nil || *v != "hello" {
+		t.Errorf("expected overwritten value hello, got %v", v)
+	}
+
+	// Entri yang sudah kedaluwarsa saat diimpor dibuang
+	past := time.UnixMilli(now.Load()).Add(-time.Minute)
+	stale, _ := json.Marshal([]cago.ExportEntry{{Key: "stale", Value: []byte("x"), Created: past.Add(-time.Minute), Updated: past, Expires: past}})
+	if err := dst.Import(stale, true); err != nil {
+		t.Fatal(err)
+	}
+	if dst.Exist("stale") {
+		t.Error("expected stale entry to be dropped")
+	}
+
+	if err := dst.Import([]byte("not json"), true); err == nil || !strings.Contains(err.Error(), "invalid") {
+		t.Errorf("expected JSON error, got %v", err)
+	}
+}
+
+func TestSnapshotBinary(t *testing.T) {
+	var now atomic.Int64
+	now.Store(1_700_000_000_000)
+	c, err := cago.NewCache(cago.Config{Clock: now.Load})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer c.Close()
+	if err := c.Put("text", "hello"); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.Put("num", 42, 60000); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.Put("short", "gone", 1); err != nil {
+		t.Fatal(err)
+	}
+	now.Add(1)
+	created, _, expires, _ := c.GetMeta("num")
+
+	var buf bytes.Buffer
+	if err := c.SnapshotBinary(&buf); err != nil {
+		t.Fatal(err)
+	}
+	snapshot := buf.Bytes()
+	if err := c.Clear(); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.RestoreBinary(bytes.NewReader(snapshot)); err != nil {
+		t.Fatal(err)
+	}
+
+	if v := cago.GetFrom[string](c, "text"); v == nil || *v != "hello" {
+		t.Errorf("expected hello, got %v", v)
+	}
+	if v := cago.GetFrom[int](c, "num"); v == nil || *v != 42 {
+		t.Errorf("expected 42, got %v", v)
+	}
+	if c.Exist("short") {
+		t.Error("expected expired entry to be left out of the snapshot")
+	}
+	gotCreated, _, gotExpires, ok := c.GetMeta("num")
+	if !ok || !gotCreated.Equal(created) || !gotExpires.Equal(expires) {
+		t.Errorf("expected metadata %v/%v, got %v/%v", created, expires, gotCreated, gotExpires)
+	}
+
+	if err := c.Clear(); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.RestoreBinary(bytes.NewReader(snapshot[:len(snapshot)-1])); !errors.Is(err, io.ErrUnexpectedEOF) {
+		t.Errorf("expected ErrUnexpectedEOF for truncated snapshot, got %v", err)
+	}
+	if c.Exist("text") || c.Exist("num") {
+		t.Error("expected truncated snapshot to restore nothing")
+	}
+}
+
+func TestRestoreBinaryLengthPrefix(t *testing.T) {
+	c, err := cago.NewCache()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer c.Close()
+
+	// Panjang di atas MAX_MEM ditolak sebelum dibaca
+	huge := []byte{0xff, 0xff, 0xff, 0xff, 'k'}
+	if err := c.RestoreBinary(bytes.NewReader(huge)); !errors.Is(err, cago.ErrMemoryLimit) {
+		t.Errorf("expected ErrMemoryLimit for an oversized length, got %v", err)
+	}
+
+	// Panjang yang masih di bawah MAX_MEM tetapi melebihi isi snapshot
+	// dilaporkan sebagai snapshot terpotong
+	short := []byte{0x20, 0x00, 0x00, 0x00, 'k'}
+	if err := c.RestoreBinary(bytes.NewReader(short)); !errors.Is(err, io.ErrUnexpectedEOF) {
+		t.Errorf("expected ErrUnexpectedEOF for a truncated field, got %v", err)
+	}
+}
@@ -0,0 +1,111 @@
+// Copyright (c) 2024, Jasakode Authors.
+// All rights reserved.
+// Use of this source code is governed by a BSD 3-Clause
+// license that can be found in the LICENSE file.
+
+package cago
+
+import "sync/atomic"
+
+// CacheStats adalah snapshot penghitung cache yang dikembalikan oleh Stats.
+//
+// Field-field:
+//   - Hits: Jumlah pembacaan yang menemukan key.
+//   - Misses: Jumlah pembacaan yang tidak menemukan key.
+//   - Expirations: Jumlah entri yang dihapus karena kedaluwarsa.
+//   - Evictions: Jumlah entri yang dihapus karena MaxEntries atau MAX_MEM.
+//   - DroppedEvents: Jumlah event Subscribe yang dibuang karena subscriber lambat.
+//   - Entries: Jumlah entri yang belum kedaluwarsa. Entri kedaluwarsa yang belum
+//     dihapus oleh pemeriksaan berikutnya tidak dihitung, sama seperti pada Get.
+//   - Subscribers: Jumlah watcher WatchKey dan subscriber Subscribe yang terdaftar.
+type CacheStats struct {
+	Hits          uint64 `json:"hits"`
+	Misses        uint64 `json:"misses"`
+	Expirations   uint64 `json:"expirations"`
+	Evictions     uint64 `json:"evictions"`
+	DroppedEvents uint64 `json:"dropped_events"`
+	Entries       int    `json:"entries"`
+	Subscribers   int    `json:"subscribers"`
+}
+
+// counters menyimpan penghitung Stats. Penghitung diperbarui secara atomik
+// sehingga jalur baca tidak memerlukan write lock hanya untuk mencatat statistik.
+type counters struct {
+	hits        atomic.Uint64
+	misses      atomic.Uint64
+	expirations atomic.Uint64
+	evictions   atomic.Uint64
+	dropped     atomic.Uint64
+}
+
+// recordLookup mencatat hasil pembacaan sebagai hit atau miss.
+func (app *App) recordLookup(hit bool) {
+	if hit {
+		app.stats.hits.Add(1)
+	} else {
+		app.stats.misses.Add(1)
+	}
+}
+
+// recordEvict mencatat entri yang keluar dari cache sesuai reason.
+// Penghapusan manual tidak dihitung.
+func (app *App) recordEvict(reason EvictReason) {
+	switch reason {
+	case EvictExpired:
+		app.stats.expirations.Add(1)
+	case EvictCapacity:
+		app.stats.evictions.Add(1)
+	}
+}
+
+// Stats mengembalikan snapshot penghitung cache sejak instance dibuat
+// atau sejak ResetStats terakhir dipanggil.
+//
+// Mengembalikan:
+//   - CacheStats: Snapshot penghitung dan jumlah entri saat ini.
+func (app *App) Stats() CacheStats {
+	now := app.now()
+	entries := 0
+	for _, s := range app.shards {
+		s.mu.RLock()
+		for key, value := range s.data {
+			if !app.expiredEntry(key, value, now) {
+				entries++
+			}
+		}
+		s.mu.RUnlock()
+	}
+	app.wmu.Lock()
+	subs := app.nwatch + len(app.subs)
+	app.wmu.Unlock()
+	return CacheStats{
+		Hits:          app.stats.hits.Load(),
+		Misses:        app.stats.misses.Load(),
+		Expirations:   app.stats.expirations.Load(),
+		Evictions:     app.stats.evictions.Load(),
+		DroppedEvents: app.stats.dropped.Load(),
+		Entries:       entries,
+		Subscribers:   subs,
+	}
+}
+
+// ResetStats mengembalikan semua penghitung Stats ke nol, termasuk penghitung
+// setiap subscriber di Subscriptions. Jumlah entri dan subscriber tidak terpengaruh.
+func (app *App) ResetStats() {
+	app.stats.hits.Store(0)
+	app.stats.misses.Store(0)
+	app.stats.expirations.Store(0)
+	app.stats.evictions.Store(0)
+	app.stats.dropped.Store(0)
+	app.resetSubscriberStats()
+}
+
+// Stats menjalankan App.Stats pada instance global yang dibuat oleh New.
+func Stats() CacheStats {
+	return app.Stats()
+}
+
+// ResetStats menjalankan App.ResetStats pada instance global yang dibuat oleh New.
+func ResetStats() {
+	app.ResetStats()
+}
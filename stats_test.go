@@ -0,0 +1,59 @@
+// Copyright (c) 2024, Jasakode Authors.
+// All rights reserved.
+// Use of this source code is governed by a BSD 3-Clause
+// license that can be found in the LICENSE file.
+
+package cago_test
+
+import (
+	"sync/atomic"
+	"testing"
+
+	"github.com/jasakode/cago"
+)
+
+func TestStats(t *testing.T) {
+	var now atomic.Int64
+	now.Store(1_700_000_000_000)
+	if err := cago.New(cago.Config{MaxEntries: 2, TimeoutCheck: 60000, Clock: now.Load}); err != nil {
+		t.Fatal(err)
+	}
+	cago.Set("a", 1)
+	cago.Set("b", 2, 20)
+	cago.Get[int]("a")
+	cago.Get[int]("a")
+	cago.Get[int]("missing")
+	cago.Set("c", 3) // mengeluarkan "a"
+
+	now.Add(20) // "b" kedaluwarsa
+	cago.RemoveExpired()
+
+	want := cago.CacheStats{Hits: 2, Misses: 1, Expirations: 1, Evictions: 1, Entries: 1}
+	if got := cago.Stats(); got != want {
+		t.Errorf("expected %+v, got %+v", want, got)
+	}
+
+	cago.ResetStats()
+	want = cago.CacheStats{Entries: 1}
+	if got := cago.Stats(); got != want {
+		t.Errorf("expected %+v after reset, got %+v", want, got)
+	}
+}
+
+func TestStatsEntriesSkipExpired(t *testing.T) {
+	var now atomic.Int64
+	now.Store(1_700_000_000_000)
+	c, err := cago.NewCache(cago.Config{TimeoutCheck: 60000, Clock: now.Load})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer c.Close()
+	c.Put("short", "x", 1)
+	c.Put("long", "y", 60000)
+	now.Add(1)
+
+	// "short" kedaluwarsa tetapi belum dihapus oleh pemeriksaan latar belakang
+	if n := c.Stats().Entries; n != 1 {
+		t.Errorf("expected 1 live entry, got %d", n)
+	}
+}
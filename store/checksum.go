@@ -0,0 +1,70 @@
+// Copyright (c) 2024, Jasakode Authors.
+// All rights reserved.
+// Use of this source code is governed by a BSD 3-Clause
+// license that can be found in the LICENSE file.
+
+package store
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash/crc32"
+)
+
+// FlagChecksum menandakan header store berisi CRC32 dari payload.
+// Blob versi lama tidak memiliki flag ini sehingga tidak diverifikasi.
+const FlagChecksum uint8 = 1 << 1
+
+// ErrChecksum dikembalikan ketika payload store tidak sesuai dengan
+// checksum atau panjang yang tercatat di header.
+var ErrChecksum = errors.New("store checksum mismatch")
+
+// setChecksum menghitung CRC32 dari payload lalu menyimpannya di header.
+func (s Store) setChecksum() {
+	binary.BigEndian.PutUint32(s[ChecksumIndex:LengthIndex], crc32.ChecksumIEEE(s[DataStartIndex:]))
+	s[FlagsIndex] |= FlagChecksum
+}
+
+// Verify memeriksa apakah payload store masih utuh dengan membandingkan
+// panjang dan CRC32 payload dengan nilai yang tercatat di header.
+// Store tanpa FlagChecksum hanya diperiksa panjangnya.
+//
+// Mengembalikan:
+//   - error: ErrChecksum jika payload terpotong atau rusak.
+func (s Store) Verify() error {
+	if len(s) < DataStartIndex {
+		return fmt.Errorf("%w: store shorter than header", ErrChecksum)
+	}
+	if n := uint64(len(s) - DataStartIndex); n != s.Length() {
+		return fmt.Errorf("%w: payload is %d bytes, header says %d", ErrChecksum, n, s.Length())
+	}
+	if s[FlagsIndex]&FlagChecksum == 0 {
+		return nil
+	}
+	if binary.BigEndian.Uint32(s[ChecksumIndex:LengthIndex]) != crc32.ChecksumIEEE(s[DataStartIndex:]) {
+		return ErrChecksum
+	}
+	return nil
+}
+
+// ParseStoreChecked bekerja seperti ParseStoreErr, tetapi juga memverifikasi
+// checksum payload. Fungsi ini
+// cocok untuk data yang dimuat dari penyimpanan persisten.
+//
+// Parameter:
+// - data: Data biner yang akan diuraikan menjadi Store.
+//
+// Mengembalikan:
+// - Store: Struktur penyimpanan yang berisi metadata dan data yang diberikan.
+// - error: ErrUnsupportedVersion atau ErrChecksum jika data tidak dapat digunakan.
+func ParseStoreChecked(data []byte) (Store, error) {
+	s, err := ParseStoreErr(data)
+	if err != nil {
+		return Store{}, err
+	}
+	if err := s.Verify(); err != nil {
+		return Store{}, err
+	}
+	return s, nil
+}
@@ -0,0 +1,70 @@
+// Copyright (c) 2024, Jasakode Authors.
+// All rights reserved.
+// Use of this source code is governed by a BSD 3-Clause
+// license that can be found in the LICENSE file.
+
+package store
+
+import (
+	"bytes"
+	"compress/flate"
+	"fmt"
+	"io"
+)
+
+// FlagCompressed menandakan payload store dikompresi dengan flate.
+const FlagCompressed uint8 = 1 << 0
+
+// CompressThreshold adalah ukuran minimum payload dalam byte yang akan
+// dikompresi oleh NewStoreCompressed. Payload yang lebih kecil disimpan
+// apa adanya karena overhead kompresi lebih besar dari penghematannya.
+var CompressThreshold = 256
+
+// NewStoreCompressed membuat penyimpanan baru seperti NewStore, tetapi
+// payload dikompresi dengan flate jika ukurannya mencapai CompressThreshold
+// dan hasil kompresinya lebih kecil. Text, Bytes, JSON, dan accessor lainnya
+// mendekompresi payload secara transparan.
+//
+// Parameter:
+// - data: Data biner yang akan disimpan.
+// - maxAge: Usia maksimum yang diperbolehkan untuk data (opsional).
+//
+// Mengembalikan:
+// - Store: Struktur penyimpanan yang berisi metadata dan data yang diberikan.
+func NewStoreCompressed(data []byte, maxAge ...uint64) Store {
+	if len(data) < CompressThreshold {
+		return NewStore(data, maxAge...)
+	}
+	var buf bytes.Buffer
+	w, _ := flate.NewWriter(&buf, flate.DefaultCompression) // Level yang valid tidak pernah gagal
+	w.Write(data)
+	w.Close()
+	if buf.Len() >= len(data) {
+		return NewStore(data, maxAge...)
+	}
+	s := NewStore(buf.Bytes(), maxAge...)
+	s[FlagsIndex] |= FlagCompressed
+	return s
+}
+
+// Compressed memeriksa apakah payload store dikompresi.
+//
+// Mengembalikan:
+//   - bool: True jika flag FlagCompressed diatur.
+func (s Store) Compressed() bool {
+	return s[FlagsIndex]&FlagCompressed != 0
+}
+
+// payload mengembalikan data asli store, didekompresi jika perlu.
+func (s Store) payload() ([]byte, error) {
+	if !s.Compressed() {
+		return s[DataStartIndex:], nil
+	}
+	r := flate.NewReader(bytes.NewReader(s[DataStartIndex:]))
+	defer r.Close()
+	p, err := io.ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("decompressing payload: %w", err)
+	}
+	return p, nil
+}
@@ -0,0 +1,88 @@
+// Copyright (c) 2024, Jasakode Authors.
+// All rights reserved.
+// Use of this source code is governed by a BSD 3-Clause
+// license that can be found in the LICENSE file.
+
+package store
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"errors"
+	"fmt"
+)
+
+// FlagEncrypted menandakan payload store dienkripsi dengan AES-GCM.
+const FlagEncrypted uint8 = 1 << 2
+
+// ErrNotEncrypted dikembalikan oleh Decrypt ketika store tidak dienkripsi.
+var ErrNotEncrypted = errors.New("store is not encrypted")
+
+// NewStoreEncrypted membuat penyimpanan baru dengan payload yang dienkripsi
+// menggunakan AES-GCM. Nonce disimpan di awal payload, diikuti ciphertext.
+// Metadata seperti CreateAt, MaxAge, dan Length tetap dapat dibaca tanpa key,
+// sedangkan isi payload hanya dapat dibaca melalui Decrypt.
+//
+// Parameter:
+// - data: Data biner yang akan dienkripsi dan disimpan.
+// - key: Key AES dengan panjang 16, 24, atau 32 byte.
+// - maxAge: Usia maksimum yang diperbolehkan untuk data (opsional).
+//
+// Mengembalikan:
+// - Store: Struktur penyimpanan yang berisi metadata dan data terenkripsi.
+// - error: Kesalahan jika key tidak valid atau nonce gagal dibuat.
+func NewStoreEncrypted(data []byte, key []byte, maxAge ...uint64) (Store, error) {
+	gcm, err := newGCM(key)
+	if err != nil {
+		return Store{}, err
+	}
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return Store{}, fmt.Errorf("generating nonce: %w", err)
+	}
+	s := NewStore(gcm.Seal(nonce, nonce, data, nil), maxAge...)
+	s[FlagsIndex] |= FlagEncrypted
+	return s, nil
+}
+
+// Encrypted memeriksa apakah payload store dienkripsi.
+//
+// Mengembalikan:
+//   - bool: True jika flag FlagEncrypted diatur.
+func (s Store) Encrypted() bool {
+	return s[FlagsIndex]&FlagEncrypted != 0
+}
+
+// Decrypt mendekripsi payload store yang dibuat oleh NewStoreEncrypted.
+//
+// Parameter:
+//   - key ([]byte): Key AES yang sama dengan saat enkripsi.
+//
+// Mengembalikan:
+//   - []byte: Payload asli.
+//   - error: ErrNotEncrypted jika store tidak dienkripsi, atau kesalahan
+//     autentikasi GCM jika key salah atau ciphertext telah diubah.
+func (s Store) Decrypt(key []byte) ([]byte, error) {
+	if !s.Encrypted() {
+		return nil, ErrNotEncrypted
+	}
+	gcm, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+	p := s[DataStartIndex:]
+	if len(p) < gcm.NonceSize() {
+		return nil, fmt.Errorf("insufficient length for nonce")
+	}
+	return gcm.Open(nil, p[:gcm.NonceSize()], p[gcm.NonceSize():], nil)
+}
+
+// newGCM membuat AES-GCM dari key yang diberikan.
+func newGCM(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
@@ -0,0 +1,164 @@
+// Copyright (c) 2024, Jasakode Authors.
+// All rights reserved.
+// Use of this source code is governed by a BSD 3-Clause
+// license that can be found in the LICENSE file.
+
+package store
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math"
+)
+
+// Int8 mengembalikan data yang disimpan dalam store sebagai int8.
+// Payload dibaca sesuai lebar yang tersimpan, lihat Int64.
+//
+// Mengembalikan:
+//   - int8: Data yang disimpan dalam store.
+//   - error: Kesalahan jika lebar payload tidak valid atau nilainya di luar rentang int8.
+func (s Store) Int8() (int8, error) {
+	v, err := s.signed("int8", math.MinInt8, math.MaxInt8)
+	return int8(v), err
+}
+
+// Int16 mengembalikan data yang disimpan dalam store sebagai int16.
+// Payload dibaca sesuai lebar yang tersimpan, lihat Int64.
+//
+// Mengembalikan:
+//   - int16: Data yang disimpan dalam store.
+//   - error: Kesalahan jika lebar payload tidak valid atau nilainya di luar rentang int16.
+func (s Store) Int16() (int16, error) {
+	v, err := s.signed("int16", math.MinInt16, math.MaxInt16)
+	return int16(v), err
+}
+
+// Int32 mengembalikan data yang disimpan dalam store sebagai int32.
+// Payload dibaca sesuai lebar yang tersimpan, lihat Int64.
+//
+// Mengembalikan:
+//   - int32: Data yang disimpan dalam store.
+//   - error: Kesalahan jika lebar payload tidak valid atau nilainya di luar rentang int32.
+func (s Store) Int32() (int32, error) {
+	v, err := s.signed("int32", math.MinInt32, math.MaxInt32)
+	return int32(v), err
+}
+
+// Int64 mengembalikan data yang disimpan dalam store sebagai int64.
+// Berbeda dengan Int yang selalu membaca 8 byte, lebar payload diambil dari
+// panjangnya dan harus 1, 2, 4, atau 8 byte, pasangan dari lib.Int8ToByte
+// hingga lib.Int64ToByte. Payload yang lebih sempit diperluas dengan
+// two's complement sehingga nilai negatif kembali utuh.
+//
+// Mengembalikan:
+//   - int64: Data yang disimpan dalam store.
+//   - error: Kesalahan jika lebar payload tidak valid.
+func (s Store) Int64() (int64, error) {
+	return s.signed("int64", math.MinInt64, math.MaxInt64)
+}
+
+// Uint8 mengembalikan data yang disimpan dalam store sebagai uint8.
+// Payload dibaca sesuai lebar yang tersimpan, lihat Uint64.
+//
+// Mengembalikan:
+//   - uint8: Data yang disimpan dalam store.
+//   - error: Kesalahan jika lebar payload tidak valid atau nilainya di luar rentang uint8.
+func (s Store) Uint8() (uint8, error) {
+	v, err := s.unsigned("uint8", math.MaxUint8)
+	return uint8(v), err
+}
+
+// Uint16 mengembalikan data yang disimpan dalam store sebagai uint16.
+// Payload dibaca sesuai lebar yang tersimpan, lihat Uint64.
+//
+// Mengembalikan:
+//   - uint16: Data yang disimpan dalam store.
+//   - error: Kesalahan jika lebar payload tidak valid atau nilainya di luar rentang uint16.
+func (s Store) Uint16() (uint16, error) {
+	v, err := s.unsigned("uint16", math.MaxUint16)
+	return uint16(v), err
+}
+
+// Uint32 mengembalikan data yang disimpan dalam store sebagai uint32.
+// Payload dibaca sesuai lebar yang tersimpan, lihat Uint64.
+//
+// Mengembalikan:
+//   - uint32: Data yang disimpan dalam store.
+//   - error: Kesalahan jika lebar payload tidak valid atau nilainya di luar rentang uint32.
+func (s Store) Uint32() (uint32, error) {
+	v, err := s.unsigned("uint32", math.MaxUint32)
+	return uint32(v), err
+}
+
+// Uint64 mengembalikan data yang disimpan dalam store sebagai uint64.
+// Lebar payload diambil dari panjangnya dan harus 1, 2, 4, atau 8 byte,
+// pasangan dari lib.Uint8ToByte hingga lib.Uint64ToByte.
+//
+// Mengembalikan:
+//   - uint64: Data yang disimpan dalam store.
+//   - error: Kesalahan jika lebar payload tidak valid.
+func (s Store) Uint64() (uint64, error) {
+	return s.unsigned("uint64", math.MaxUint64)
+}
+
+// signed membaca payload sebagai bilangan bertanda sesuai lebarnya, lalu
+// memastikan nilainya berada di antara lo dan hi. Store dengan tag KindUint
+// ditafsirkan sebagai tak bertanda agar nilai besar tidak terbaca negatif.
+func (s Store) signed(kind string, lo, hi int64) (int64, error) {
+	p, err := s.payload()
+	if err != nil {
+		return 0, err
+	}
+	var v int64
+	switch len(p) {
+	case 1:
+		v = int64(int8(p[0]))
+	case 2:
+		v = int64(int16(binary.BigEndian.Uint16(p)))
+	case 4:
+		v = int64(int32(binary.BigEndian.Uint32(p)))
+	case 8:
+		v = int64(binary.BigEndian.Uint64(p))
+	default:
+		return 0, fmt.Errorf("invalid length %d for %s conversion", len(p), kind)
+	}
+	// Integer tak bertanda dengan bit teratas menyala terbaca negatif
+	if s.Kind() == KindUint && p[0]&0x80 != 0 {
+		return 0, fmt.Errorf("unsigned value out of range for %s", kind)
+	}
+	if v < lo || v > hi {
+		return 0, fmt.Errorf("value %d out of range for %s", v, kind)
+	}
+	return v, nil
+}
+
+// unsigned membaca payload sebagai bilangan tak bertanda sesuai lebarnya,
+// lalu memastikan nilainya tidak melebihi hi. Store dengan tag KindInt yang
+// nilainya negatif ditolak.
+func (s Store) unsigned(kind string, hi uint64) (uint64, error) {
+	p, err := s.payload()
+	if err != nil {
+		return 0, err
+	}
+	var v uint64
+	switch len(p) {
+	case 1:
+		v = uint64(p[0])
+	case 2:
+		v = uint64(binary.BigEndian.Uint16(p))
+	case 4:
+		v = uint64(binary.BigEndian.Uint32(p))
+	case 8:
+		v = binary.BigEndian.Uint64(p)
+	default:
+		return 0, fmt.Errorf("invalid length %d for %s conversion", len(p), kind)
+	}
+	// Integer bertanda yang negatif tidak dapat dibaca sebagai tak bertanda
+	if s.Kind() == KindInt && p[0]&0x80 != 0 {
+		return 0, fmt.Errorf("negative value out of range for %s", kind)
+	}
+	if v > hi {
+		return 0, fmt.Errorf("value %d out of range for %s", v, kind)
+	}
+	return v, nil
+}
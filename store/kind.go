@@ -0,0 +1,74 @@
+// Copyright (c) 2024, Jasakode Authors.
+// All rights reserved.
+// Use of this source code is governed by a BSD 3-Clause
+// license that can be found in the LICENSE file.
+
+package store
+
+import "fmt"
+
+// StoreKind mencatat jenis nilai asal yang di-encode ke dalam payload store,
+// sehingga pembaca dapat menolak payload yang tidak sesuai dengan tipe yang
+// diminta alih-alih menafsirkan byte-nya secara keliru.
+type StoreKind uint8
+
+const (
+	// KindUnknown dipakai oleh store tanpa tag, misalnya blob lama atau store
+	// yang dibuat langsung dengan NewStore. Pembaca tidak memvalidasi tipenya.
+	KindUnknown StoreKind = iota
+	KindString            // Teks, termasuk *url.URL
+	KindInt               // Integer bertanda
+	KindUint              // Integer tak bertanda
+	KindFloat             // float32 atau float64 yang disimpan sebagai JSON
+	KindBool              // Boolean
+	KindJSON              // Nilai lain yang disimpan sebagai JSON
+	KindBytes             // Byte mentah, termasuk net.IP
+	KindTime              // time.Time
+)
+
+// String mengembalikan nama jenis untuk pesan kesalahan.
+func (k StoreKind) String() string {
+	switch k {
+	case KindUnknown:
+		return "unknown"
+	case KindString:
+		return "string"
+	case KindInt:
+		return "int"
+	case KindUint:
+		return "uint"
+	case KindFloat:
+		return "float"
+	case KindBool:
+		return "bool"
+	case KindJSON:
+		return "json"
+	case KindBytes:
+		return "bytes"
+	case KindTime:
+		return "time"
+	}
+	return fmt.Sprintf("kind(%d)", uint8(k))
+}
+
+// Kind mengembalikan jenis nilai yang tercatat pada KindIndex.
+//
+// Mengembalikan:
+//   - StoreKind: Jenis nilai, atau KindUnknown jika store tidak memiliki tag.
+func (s Store) Kind() StoreKind {
+	return StoreKind(s[KindIndex])
+}
+
+// SetKind menetapkan jenis nilai store. Tag tidak ikut dihitung dalam
+// checksum sehingga payload dan checksum tidak berubah. s tidak diubah.
+//
+// Parameter:
+//   - kind (StoreKind): Jenis nilai yang akan dicatat.
+//
+// Mengembalikan:
+//   - Store: Salinan store dengan tag baru.
+func (s Store) SetKind(kind StoreKind) Store {
+	n := s.clone()
+	n[KindIndex] = uint8(kind)
+	return n
+}
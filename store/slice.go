@@ -0,0 +1,94 @@
+// Copyright (c) 2024, Jasakode Authors.
+// All rights reserved.
+// Use of this source code is governed by a BSD 3-Clause
+// license that can be found in the LICENSE file.
+
+package store
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math"
+)
+
+// NewStoreInt64Slice membuat penyimpanan baru dari slice int64. Setiap elemen
+// disimpan sebagai 8 byte big-endian, sehingga jumlah elemen dapat dihitung
+// dari panjang payload dan tidak perlu disimpan terpisah.
+//
+// Parameter:
+// - values: Slice yang akan disimpan.
+// - maxAge: Usia maksimum yang diperbolehkan untuk data dalam milidetik (opsional).
+//
+// Mengembalikan:
+// - Store: Struktur penyimpanan yang berisi metadata dan slice yang diberikan.
+func NewStoreInt64Slice(values []int64, maxAge ...uint64) Store {
+	data := make([]byte, 8*len(values))
+	for i, v := range values {
+		binary.BigEndian.PutUint64(data[8*i:], uint64(v))
+	}
+	return NewStore(data, maxAge...)
+}
+
+// NewStoreFloat64Slice membuat penyimpanan baru dari slice float64. Setiap
+// elemen disimpan sebagai 8 byte IEEE-754 big-endian, sehingga NaN dan Inf
+// juga dapat disimpan tanpa kehilangan presisi.
+//
+// Parameter:
+// - values: Slice yang akan disimpan.
+// - maxAge: Usia maksimum yang diperbolehkan untuk data dalam milidetik (opsional).
+//
+// Mengembalikan:
+// - Store: Struktur penyimpanan yang berisi metadata dan slice yang diberikan.
+func NewStoreFloat64Slice(values []float64, maxAge ...uint64) Store {
+	data := make([]byte, 8*len(values))
+	for i, v := range values {
+		binary.BigEndian.PutUint64(data[8*i:], math.Float64bits(v))
+	}
+	return NewStore(data, maxAge...)
+}
+
+// Int64Slice mengembalikan data yang disimpan oleh NewStoreInt64Slice.
+//
+// Mengembalikan:
+//   - []int64: Slice yang disimpan dalam store.
+//   - error: Kesalahan jika panjang payload bukan kelipatan 8 byte.
+func (s Store) Int64Slice() ([]int64, error) {
+	p, err := s.slicePayload("int64")
+	if err != nil {
+		return nil, err
+	}
+	values := make([]int64, len(p)/8)
+	for i := range values {
+		values[i] = int64(binary.BigEndian.Uint64(p[8*i:]))
+	}
+	return values, nil
+}
+
+// Float64Slice mengembalikan data yang disimpan oleh NewStoreFloat64Slice.
+//
+// Mengembalikan:
+//   - []float64: Slice yang disimpan dalam store.
+//   - error: Kesalahan jika panjang payload bukan kelipatan 8 byte.
+func (s Store) Float64Slice() ([]float64, error) {
+	p, err := s.slicePayload("float64")
+	if err != nil {
+		return nil, err
+	}
+	values := make([]float64, len(p)/8)
+	for i := range values {
+		values[i] = math.Float64frombits(binary.BigEndian.Uint64(p[8*i:]))
+	}
+	return values, nil
+}
+
+// slicePayload mengembalikan payload yang panjangnya harus kelipatan 8 byte.
+func (s Store) slicePayload(kind string) ([]byte, error) {
+	p, err := s.payload()
+	if err != nil {
+		return nil, err
+	}
+	if len(p)%8 != 0 {
+		return nil, fmt.Errorf("payload length %d is not a multiple of 8 for []%s conversion", len(p), kind)
+	}
+	return p, nil
+}
@@ -8,7 +8,9 @@ package store
 import (
 	"encoding/binary"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"math"
 	"time"
 
 	"github.com/jasakode/cago/lib"
@@ -16,6 +18,12 @@ import (
 
 // Store adalah tipe data yang merepresentasikan sekumpulan byte.
 // Tipe ini dapat digunakan untuk menyimpan data biner dalam bentuk slice byte.
+//
+// Store yang sudah dibuat tidak pernah diubah oleh method-nya: SetCreateAt,
+// SetUpdateAt, SetMaxAge, SetLength, SetKind, SetData, dan Append selalu mengembalikan
+// salinan baru. Karena itu satu Store aman dibaca dari banyak goroutine
+// sekaligus, misalnya ketika Store yang sama dipegang oleh cache dan pemanggil
+// Get, selama tidak ada yang menulis langsung ke slice-nya.
 type Store []byte
 
 // Compare adalah interface yang mendefinisikan tipe data yang dapat dibandingkan.
@@ -27,24 +35,59 @@ type Compare interface {
 }
 
 const (
+	VersionIndex   = 0  // Indeks untuk versi format penyimpanan (byte teratas CreateAt)
+	FlagsIndex     = 1  // Indeks untuk flag penyimpanan, misalnya kompresi (byte kedua CreateAt)
+	KindIndex      = 8  // Indeks untuk jenis nilai asal, lihat StoreKind (byte teratas UpdateAt)
 	CreateAtIndex  = 0  // Indeks untuk waktu pembuatan dalam penyimpanan
 	UpdateAtIndex  = 8  // Indeks untuk waktu pembaruan dalam penyimpanan
 	MaxAgeIndex    = 16 // Indeks untuk usia maksimum data dalam penyimpanan
-	LengthIndex    = 24 // Indeks untuk panjang data yang disimpan
+	ChecksumIndex  = 24 // Indeks untuk CRC32 dari payload
+	LengthIndex    = 28 // Indeks untuk panjang data yang disimpan
 	DataStartIndex = 32 // Indeks awal untuk data aktual dalam penyimpanan
 )
 
+const (
+	// LegacyVersion adalah versi format Store sebelum adanya byte versi.
+	// Blob lama selalu memiliki nilai nol pada VersionIndex karena timestamp
+	// dalam milidetik tidak pernah menyentuh byte teratas CreateAt.
+	LegacyVersion uint8 = 1
+	// CurrentVersion adalah versi format Store yang ditulis oleh NewStore.
+	CurrentVersion uint8 = 2
+)
+
+// timestampMask digunakan untuk membaca timestamp 48-bit tanpa ikut membaca
+// byte metadata yang menempati byte teratas field timestamp.
+const timestampMask = 0x0000ffffffffffff
+
+// ErrUnsupportedVersion dikembalikan ketika versi format Store tidak dapat
+// digunakan atau tidak dikenali oleh versi cago yang sedang berjalan.
+var ErrUnsupportedVersion = errors.New("unsupported store version")
+
 // NewStore membuat penyimpanan baru dengan metadata dan data yang diberikan.
 // Fungsi ini menginisialisasi struktur penyimpanan dengan waktu pembuatan,
 // waktu pembaruan (default ke nol), usia maksimum, panjang data, dan data aktual.
 //
 // Parameter:
 // - data: Data biner yang akan disimpan.
-// - maxAge: Usia maksimum yang diperbolehkan untuk data (opsional).
+// - maxAge: Usia maksimum yang diperbolehkan untuk data dalam milidetik (opsional).
 //
 // Mengembalikan:
 // - Store: Struktur penyimpanan yang berisi metadata dan data yang diberikan.
 func NewStore(data []byte, maxAge ...uint64) Store {
+	return NewStoreAt(data, uint64(time.Now().UnixMilli()), maxAge...)
+}
+
+// NewStoreAt sama seperti NewStore, tetapi waktu pembuatan diambil dari createAt
+// dan bukan dari jam sistem. Fungsi ini digunakan oleh cache dengan Config.Clock.
+//
+// Parameter:
+// - data: Data biner yang akan disimpan.
+// - createAt: Waktu pembuatan dalam milidetik unix.
+// - maxAge: Usia maksimum yang diperbolehkan untuk data dalam milidetik (opsional).
+//
+// Mengembalikan:
+// - Store: Struktur penyimpanan yang berisi metadata dan data yang diberikan.
+func NewStoreAt(data []byte, createAt uint64, maxAge ...uint64) Store {
 	MaxAge := uint64(0) // Inisialisasi usia maksimum ke nol
 	if len(maxAge) > 0 {
 		MaxAge = maxAge[0] // Jika ada argumen maxAge, ambil nilainya
@@ -52,12 +95,14 @@ func NewStore(data []byte, maxAge ...uint64) Store {
 
 	// Membuat slice Store dengan panjang yang cukup untuk metadata dan data
 	s := make(Store, DataStartIndex+len(data))
-	copy(s[CreateAtIndex:UpdateAtIndex], lib.Uint64ToByte(uint64(time.Now().UnixMilli()))) // Menyimpan waktu pembuatan
-	copy(s[UpdateAtIndex:MaxAgeIndex], make([]byte, 8))                                    // Menyimpan nilai nol untuk waktu pembaruan
-	copy(s[MaxAgeIndex:LengthIndex], lib.Uint64ToByte(MaxAge))                             // Menyimpan usia maksimum
-	copy(s[LengthIndex:], lib.Uint64ToByte(uint64(len(data))))                             // Menyimpan panjang data
-	copy(s[DataStartIndex:], data)                                                         // Menyalin data aktual setelah metadata
-	return s                                                                               // Mengembalikan struktur penyimpanan yang telah dibuat
+	copy(s[CreateAtIndex:UpdateAtIndex], lib.Uint64ToByte(createAt)) // Menyimpan waktu pembuatan
+	copy(s[UpdateAtIndex:MaxAgeIndex], make([]byte, 8))              // Menyimpan nilai nol untuk waktu pembaruan
+	copy(s[MaxAgeIndex:ChecksumIndex], lib.Uint64ToByte(MaxAge))     // Menyimpan usia maksimum
+	copy(s[LengthIndex:], lib.Uint32ToByte(uint32(len(data))))       // Menyimpan panjang data
+	copy(s[DataStartIndex:], data)                                   // Menyalin data aktual setelah metadata
+	s[VersionIndex] = CurrentVersion                                 // Menandai versi format
+	s.setChecksum()                                                  // Menyimpan checksum payload
+	return s                                                         // Mengembalikan struktur penyimpanan yang telah dibuat
 }
 
 // ParseStore menguraikan data byte dan mengembalikan Store yang sesuai.
@@ -69,14 +114,39 @@ func NewStore(data []byte, maxAge ...uint64) Store {
 //
 // Mengembalikan:
 // - Store: Struktur penyimpanan yang berisi metadata dan data yang diberikan.
-// - Jika data tidak valid, kembalikan Store kosong.
+// - Jika data tidak valid atau versinya tidak dikenali, kembalikan Store kosong.
+//
+// Gunakan ParseStoreErr untuk mengetahui penyebab data ditolak.
 func ParseStore(data []byte) Store {
-	// Pastikan panjang data cukup untuk menampung semua metadata
-	if len(data) < DataStartIndex {
-		return Store{} // Mengembalikan Store kosong jika data tidak valid
-	}
+	s, _ := ParseStoreErr(data)
+	return s
+}
 
-	return Store(data) // Mengembalikan data sebagai Store
+// ParseStoreErr bekerja seperti ParseStore, tetapi mengembalikan kesalahan
+// sehingga data yang ditolak dapat dibedakan dari Store dengan payload kosong.
+// Data ditolak jika lebih pendek dari header, versinya tidak dikenali, atau
+// panjang payload tidak sama dengan panjang yang tercatat di header.
+// Checksum tidak diperiksa; gunakan ParseStoreChecked untuk itu.
+//
+// Parameter:
+// - data: Data biner yang akan diuraikan menjadi Store.
+//
+// Mengembalikan:
+// - Store: Struktur penyimpanan yang berisi metadata dan data yang diberikan.
+// - error: ErrChecksum jika data terpotong, atau ErrUnsupportedVersion.
+func ParseStoreErr(data []byte) (Store, error) {
+	s := Store(data)
+	if len(s) < DataStartIndex {
+		return Store{}, fmt.Errorf("%w: store is %d bytes, header needs %d", ErrChecksum, len(s), DataStartIndex)
+	}
+	// Layout versi yang tidak dikenali tidak dapat dibaca dengan aman
+	if s.Version() > CurrentVersion {
+		return Store{}, fmt.Errorf("%w: %d", ErrUnsupportedVersion, s.Version())
+	}
+	if n := uint64(len(s) - DataStartIndex); n != s.Length() {
+		return Store{}, fmt.Errorf("%w: payload is %d bytes, header says %d", ErrChecksum, n, s.Length())
+	}
+	return s, nil
 }
 
 // Values mengembalikan seluruh data yang disimpan dalam Store sebagai slice byte.
@@ -99,7 +169,63 @@ func (s Store) Values() []byte {
 //   - uint64: Timestamp dalam format Unix yang menunjukkan waktu pembuatan
 //     dari store dalam milidetik.
 func (s Store) CreateAt() uint64 {
-	return binary.BigEndian.Uint64(s[CreateAtIndex:UpdateAtIndex])
+	return binary.BigEndian.Uint64(s[CreateAtIndex:UpdateAtIndex]) & timestampMask
+}
+
+// SetCreateAt menetapkan timestamp saat store dibuat.
+// Timestamp disimpan dalam 48 bit bawah dari rentang CreateAtIndex hingga
+// UpdateAtIndex, sehingga byte versi dan flag tidak ikut berubah. s tidak diubah.
+//
+// Parameter:
+//   - date (uint64): Timestamp dalam format Unix (milidetik) saat store dibuat.
+//
+// Mengembalikan:
+//   - Store: Salinan store dengan timestamp baru.
+func (s Store) SetCreateAt(date uint64) Store {
+	n := s.clone()
+	header := binary.BigEndian.Uint64(n[CreateAtIndex:UpdateAtIndex]) &^ timestampMask
+	binary.BigEndian.PutUint64(n[CreateAtIndex:UpdateAtIndex], header|date&timestampMask)
+	return n
+}
+
+// clone mengembalikan salinan store yang tidak berbagi array dengan s.
+func (s Store) clone() Store {
+	return append(make(Store, 0, len(s)), s...)
+}
+
+// Version mengembalikan versi format Store.
+// Blob yang dibuat sebelum adanya byte versi memiliki nilai nol pada
+// VersionIndex dan dilaporkan sebagai LegacyVersion.
+//
+// Mengembalikan:
+//   - uint8: Versi format dari store.
+func (s Store) Version() uint8 {
+	if s[VersionIndex] == 0 {
+		return LegacyVersion
+	}
+	return s[VersionIndex]
+}
+
+// Upgrade menaikkan store dengan versi lama ke format CurrentVersion.
+// Store yang sudah memakai versi terbaru dikembalikan apa adanya, sedangkan
+// store hasil upgrade selalu berupa salinan baru sehingga data asli tidak berubah.
+//
+// Mengembalikan:
+//   - Store: Store dalam format CurrentVersion.
+//   - error: ErrUnsupportedVersion jika versi store tidak dikenali.
+func (s Store) Upgrade() (Store, error) {
+	switch s.Version() {
+	case CurrentVersion:
+		return s, nil
+	case LegacyVersion:
+		// Layout versi 1 identik dengan versi 2 kecuali byte versi dan checksum.
+		u := make(Store, len(s))
+		copy(u, s)
+		u[VersionIndex] = CurrentVersion
+		u.setChecksum()
+		return u, nil
+	}
+	return Store{}, fmt.Errorf("%w: %d", ErrUnsupportedVersion, s.Version())
 }
 
 // UpdateAt mengembalikan timestamp terakhir kali store diperbarui.
@@ -113,31 +239,33 @@ func (s Store) CreateAt() uint64 {
 //     pembaruan dari store dalam milidetik. Nilai ini akan bernilai nol
 //     jika store belum pernah diperbarui.
 func (s Store) UpdateAt() uint64 {
-	return binary.BigEndian.Uint64(s[UpdateAtIndex:MaxAgeIndex])
+	return binary.BigEndian.Uint64(s[UpdateAtIndex:MaxAgeIndex]) & timestampMask
 }
 
 // SetUpdateAt menetapkan timestamp terakhir kali store diperbarui.
 // Fungsi ini menerima parameter `date` yang merupakan timestamp dalam
-// format Unix dan mengupdate nilai timestamp di dalam store pada
-// indeks yang ditentukan (UpdateAtIndex hingga MaxAgeIndex).
+// format Unix dan menuliskannya ke salinan store pada indeks yang
+// ditentukan (UpdateAtIndex hingga MaxAgeIndex). Seperti CreateAt, hanya
+// 48 bit bawah yang ditulis sehingga byte KindIndex tidak berubah. s tidak diubah.
 //
 // Parameter:
 //   - date (uint64): Timestamp dalam format Unix yang menunjukkan waktu
 //     saat store diperbarui.
 //
 // Mengembalikan:
-//   - Store: Mengembalikan instance Store yang telah diperbarui
-//     dengan timestamp baru.
+//   - Store: Salinan store dengan timestamp baru.
 func (s Store) SetUpdateAt(date uint64) Store {
-	binary.BigEndian.PutUint64(s[UpdateAtIndex:MaxAgeIndex], date)
-	return s
+	n := s.clone()
+	n.setUpdateAt(date)
+	return n
 }
 
 // Length mengembalikan panjang data yang disimpan dalam store.
 // Jika parameter opsional `all` diisi dan bernilai true, maka
 // panjang keseluruhan store akan dikembalikan. Jika tidak,
-// fungsi ini akan membaca nilai panjang dari indeks yang ditentukan
-// (LengthIndex) dan mengembalikannya sebagai uint64.
+// fungsi ini akan membaca nilai panjang 4 byte dari indeks yang ditentukan
+// (LengthIndex) dan mengembalikannya sebagai uint64. Blob versi lama
+// menyimpan panjang dalam 8 byte, tetapi 4 byte teratasnya selalu nol.
 //
 // Parameter:
 // - all (opsional): Jika diisi true, mengembalikan panjang seluruh store.
@@ -148,7 +276,7 @@ func (s Store) Length(all ...bool) uint64 {
 	if len(all) > 0 && all[0] {
 		return uint64(len(s))
 	}
-	return binary.BigEndian.Uint64(s[LengthIndex:])
+	return uint64(binary.BigEndian.Uint32(s[LengthIndex:]))
 }
 
 // MaxAge mengembalikan usia maksimum yang disimpan dalam store.
@@ -156,42 +284,132 @@ func (s Store) Length(all ...bool) uint64 {
 // indeks MaxAgeIndex dan mengonversinya menjadi uint64.
 //
 // Mengembalikan:
-//   - uint64: Usia maksimum yang disimpan dalam store.
+//   - uint64: Usia maksimum dalam milidetik, dihitung dari CreateAt. Nilai 0 berarti tidak kedaluwarsa.
 func (s Store) MaxAge() uint64 {
-	return binary.BigEndian.Uint64(s[MaxAgeIndex:LengthIndex])
+	return binary.BigEndian.Uint64(s[MaxAgeIndex:ChecksumIndex])
+}
+
+// IsExpired memeriksa apakah store sudah kedaluwarsa pada waktu saat ini.
+//
+// Mengembalikan:
+//   - bool: True jika MaxAge bukan 0 dan MaxAge sudah lewat sejak CreateAt.
+func (s Store) IsExpired() bool {
+	return s.IsExpiredAt(uint64(time.Now().UnixMilli()))
+}
+
+// IsExpiredAt memeriksa apakah store sudah kedaluwarsa pada waktu now.
+// Store dengan MaxAge 0 tidak pernah kedaluwarsa, dan store dengan CreateAt
+// setelah now dianggap belum kedaluwarsa.
+//
+// Parameter:
+//   - now (uint64): Waktu pemeriksaan dalam unix milidetik.
+//
+// Mengembalikan:
+//   - bool: True jika now - CreateAt >= MaxAge.
+func (s Store) IsExpiredAt(now uint64) bool {
+	if s.MaxAge() == 0 || now < s.CreateAt() {
+		return false
+	}
+	return now-s.CreateAt() >= s.MaxAge()
 }
 
 // SetMaxAge mengatur usia maksimum yang disimpan dalam store.
 // Fungsi ini menerima nilai maxAge sebagai parameter dan menyimpannya
-// dalam penyimpanan mulai dari indeks MaxAgeIndex. Jika panjang
-// data tidak mencukupi untuk menyimpan usia maksimum, fungsi ini
-// akan mengembalikan kesalahan.
+// di salinan store mulai dari indeks MaxAgeIndex. s tidak diubah.
 //
 // Parameter:
-//   - maxAge: Usia maksimum yang ingin diatur dalam store.
+//   - maxAge: Usia maksimum dalam milidetik yang ingin diatur dalam store.
 //
 // Mengembalikan:
-//   - Store: Struktur penyimpanan yang diperbarui dengan usia maksimum baru.
+//   - Store: Salinan store dengan usia maksimum baru.
 func (s Store) SetMaxAge(maxAge uint64) Store {
-	// Mengonversi maxAge ke byte dan menyimpannya di penyimpanan
-	copy(s[MaxAgeIndex:LengthIndex], lib.Uint64ToByte(maxAge))
-	return s // Mengembalikan struktur penyimpanan yang telah diperbarui
+	n := s.clone()
+	// Mengonversi maxAge ke byte dan menyimpannya di salinan
+	copy(n[MaxAgeIndex:ChecksumIndex], lib.Uint64ToByte(maxAge))
+	return n
 }
 
 // SetLength menetapkan panjang data yang disimpan dalam store.
 // Fungsi ini menerima parameter `length` yang merupakan panjang data
-// yang ingin disimpan, dan mengupdate nilai panjang di dalam store
-// pada indeks yang ditentukan (LengthIndex).
+// yang ingin disimpan, dan menuliskannya ke salinan store pada indeks
+// yang ditentukan (LengthIndex). s tidak diubah.
 //
 // Parameter:
 // - length (uint64): Panjang data yang akan disimpan di dalam store.
 //
 // Mengembalikan:
-//   - Store: Mengembalikan instance Store yang telah diperbarui dengan
-//     panjang data baru.
+//   - Store: Salinan store dengan panjang data baru.
 func (s Store) SetLength(length uint64) Store {
-	binary.BigEndian.PutUint64(s[LengthIndex:], length)
-	return s
+	n := s.clone()
+	binary.BigEndian.PutUint32(n[LengthIndex:], uint32(length))
+	return n
+}
+
+// SetData mengganti payload store dengan data baru.
+// CreateAt dan MaxAge dipertahankan, panjang dan checksum diperbarui, dan
+// UpdateAt diatur ke waktu saat ini. s tidak diubah.
+//
+// Parameter:
+//   - data ([]byte): Payload baru yang akan disimpan.
+//
+// Mengembalikan:
+//   - Store: Salinan store dengan payload baru.
+func (s Store) SetData(data []byte) Store {
+	return s.SetDataAt(data, uint64(time.Now().UnixMilli()))
+}
+
+// SetDataAt sama seperti SetData, tetapi UpdateAt diatur ke now dan bukan ke
+// waktu dari jam sistem.
+//
+// Parameter:
+//   - data ([]byte): Payload baru yang akan disimpan.
+//   - now (uint64): Waktu pembaruan dalam milidetik unix.
+//
+// Mengembalikan:
+//   - Store: Salinan store dengan payload baru.
+func (s Store) SetDataAt(data []byte, now uint64) Store {
+	n := make(Store, DataStartIndex+len(data))
+	copy(n, s[:DataStartIndex])
+	copy(n[DataStartIndex:], data)
+	n[FlagsIndex] &^= FlagCompressed // Payload baru tidak terkompresi
+	n.touch(now)
+	return n
+}
+
+// touch memperbarui panjang, UpdateAt, dan checksum store baru setelah
+// payload-nya diganti. Fungsi ini mengubah s secara langsung sehingga hanya
+// boleh dipanggil pada store yang belum dibagikan.
+func (s Store) touch(now uint64) {
+	binary.BigEndian.PutUint32(s[LengthIndex:], uint32(len(s)-DataStartIndex))
+	s.setUpdateAt(now)
+	s.setChecksum()
+}
+
+// setUpdateAt menulis 48 bit bawah UpdateAt secara langsung pada s.
+func (s Store) setUpdateAt(date uint64) {
+	header := binary.BigEndian.Uint64(s[UpdateAtIndex:MaxAgeIndex]) &^ timestampMask
+	binary.BigEndian.PutUint64(s[UpdateAtIndex:MaxAgeIndex], header|date&timestampMask)
+}
+
+// Append menambahkan extra ke akhir payload store.
+// CreateAt dan MaxAge dipertahankan, panjang dan checksum diperbarui, dan
+// UpdateAt diatur ke waktu saat ini. s tidak diubah.
+//
+// Parameter:
+//   - extra ([]byte): Data yang akan ditambahkan ke payload.
+//
+// Mengembalikan:
+//   - Store: Salinan store dengan payload yang sudah ditambahkan.
+func (s Store) Append(extra []byte) Store {
+	// Payload terkompresi harus didekompresi sebelum dapat ditambahkan
+	if s.Compressed() {
+		return s.SetData(append(s.Bytes(), extra...))
+	}
+	n := make(Store, len(s)+len(extra))
+	copy(n, s)
+	copy(n[len(s):], extra)
+	n.touch(uint64(time.Now().UnixMilli()))
+	return n
 }
 
 // Text mengembalikan data yang disimpan dalam store sebagai string.
@@ -202,7 +420,7 @@ func (s Store) SetLength(length uint64) Store {
 //   - string: Data yang disimpan dalam store, dikonversi dari byte
 //     ke string.
 func (s Store) Text() string {
-	return string(s[DataStartIndex:])
+	return string(s.Bytes())
 }
 
 // Int mengembalikan data yang disimpan dalam store sebagai int.
@@ -216,22 +434,106 @@ func (s Store) Text() string {
 //   - error: Kesalahan jika panjang data tidak mencukupi untuk
 //     konversi.
 func (s Store) Int() (int, error) {
-	if s.Length() < 8 {
+	p, err := s.payload()
+	if err != nil {
+		return 0, err
+	}
+	if len(p) < 8 {
 		return 0, fmt.Errorf("insufficient length for int conversion")
 	}
-	return int(binary.BigEndian.Uint64(s[DataStartIndex:])), nil
+	return int(binary.BigEndian.Uint64(p)), nil
+}
+
+// Float64 mengembalikan data yang disimpan dalam store sebagai float64.
+// Payload dibaca sebagai 8 byte IEEE-754 big-endian, pasangan dari
+// lib.Float64ToByte. Jika panjang data kurang dari 8 byte, akan
+// mengembalikan kesalahan.
+//
+// Mengembalikan:
+//   - float64: Data yang disimpan dalam store, dikonversi dari byte ke float64.
+//   - error: Kesalahan jika panjang data tidak mencukupi untuk konversi.
+func (s Store) Float64() (float64, error) {
+	p, err := s.payload()
+	if err != nil {
+		return 0, err
+	}
+	if len(p) < 8 {
+		return 0, fmt.Errorf("insufficient length for float64 conversion")
+	}
+	return math.Float64frombits(binary.BigEndian.Uint64(p)), nil
+}
+
+// Float32 mengembalikan data yang disimpan dalam store sebagai float32.
+// Payload dibaca sebagai 4 byte IEEE-754 big-endian, pasangan dari
+// lib.Float32ToByte. Jika panjang data kurang dari 4 byte, akan
+// mengembalikan kesalahan.
+//
+// Mengembalikan:
+//   - float32: Data yang disimpan dalam store, dikonversi dari byte ke float32.
+//   - error: Kesalahan jika panjang data tidak mencukupi untuk konversi.
+func (s Store) Float32() (float32, error) {
+	p, err := s.payload()
+	if err != nil {
+		return 0, err
+	}
+	if len(p) < 4 {
+		return 0, fmt.Errorf("insufficient length for float32 conversion")
+	}
+	return math.Float32frombits(binary.BigEndian.Uint32(p)), nil
+}
+
+// Time mengembalikan data yang disimpan dalam store sebagai time.Time.
+// Payload dibaca sebagai 12 byte hasil lib.TimeToByte, sehingga presisi
+// nanodetik, zero time, dan waktu sebelum unix epoch kembali utuh.
+//
+// Mengembalikan:
+//   - time.Time: Waktu yang disimpan dalam store, dalam UTC.
+//   - error: Kesalahan jika panjang payload bukan 12 byte.
+func (s Store) Time() (time.Time, error) {
+	p, err := s.payload()
+	if err != nil {
+		return time.Time{}, err
+	}
+	if len(p) != 12 {
+		return time.Time{}, fmt.Errorf("invalid length for time.Time conversion")
+	}
+	return lib.ByteToTime(p)
+}
+
+// Bool mengembalikan data yang disimpan dalam store sebagai bool.
+// Nilai bool disimpan dalam payload 1 byte, sehingga payload dengan
+// panjang berbeda dianggap bukan bool dan akan mengembalikan kesalahan.
+// Byte bukan nol dianggap sebagai true.
+//
+// Mengembalikan:
+//   - bool: Data yang disimpan dalam store, dikonversi dari byte ke bool.
+//   - error: Kesalahan jika panjang payload bukan 1 byte.
+func (s Store) Bool() (bool, error) {
+	p, err := s.payload()
+	if err != nil {
+		return false, err
+	}
+	if len(p) != 1 {
+		return false, fmt.Errorf("invalid length for bool conversion")
+	}
+	return p[0] != 0, nil
 }
 
 // Bytes mengembalikan data yang disimpan dalam store sebagai slice byte.
 // Fungsi ini mengambil bagian dari store yang dimulai dari indeks
 // DataStartIndex hingga akhir, memberikan akses langsung ke data
-// mentah yang disimpan.
+// mentah yang disimpan. Payload yang terkompresi akan didekompresi
+// terlebih dahulu, dan nil dikembalikan jika payload tersebut rusak.
 //
 // Mengembalikan:
 //   - []byte: Slice byte yang berisi data yang disimpan dalam
 //     store, dimulai dari DataStartIndex.
 func (s Store) Bytes() []byte {
-	return s[DataStartIndex:]
+	p, err := s.payload()
+	if err != nil {
+		return nil
+	}
+	return p
 }
 
 // JSON meng-unmarshal data JSON yang disimpan ke dalam struktur tujuan yang diberikan.
@@ -247,5 +549,9 @@ func (s Store) Bytes() []byte {
 //   - error: Mengembalikan error jika terjadi masalah selama unmarshalling,
 //     atau nil jika berhasil.
 func (s Store) JSON(dest interface{}) error {
-	return json.Unmarshal(s[DataStartIndex:], dest) // Unmarshal data to provided interface{}
+	p, err := s.payload()
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(p, dest) // Unmarshal data to provided interface{}
 }
@@ -6,6 +6,11 @@
 package store_test
 
 import (
+	"encoding/binary"
+	"errors"
+	"math"
+	"strings"
+	"sync"
 	"testing"
 	"time"
 
@@ -98,3 +103,561 @@ func TestParseStore(t *testing.T) {
 		t.Error("expected empty Store for invalid data, got non-empty")
 	}
 }
+
+// TestParseStoreErr menguji fungsi ParseStoreErr dengan data valid, terpotong, dan berlebih.
+// Fungsi ini memastikan data yang ditolak dapat dibedakan dari Store dengan payload kosong.
+/*
+	1. Kasus Uji: Store dengan payload kosong, header terpotong, payload terpotong, dan payload dengan byte tambahan.
+	2. Validasi Output: Memastikan hanya data valid yang diterima dan ParseStore mengembalikan Store kosong untuk sisanya.
+*/
+func TestParseStoreErr(t *testing.T) {
+	empty := store.NewStore(nil)
+	if s, err := store.ParseStoreErr(empty); err != nil || len(s) != DataStartIndex || s.Length() != 0 {
+		t.Errorf("expected empty payload to be valid, got %v (len %d)", err, len(s))
+	}
+
+	full := store.NewStore([]byte("payload"))
+	tests := map[string][]byte{
+		"truncated header":  full[:DataStartIndex-1],
+		"truncated payload": full[:len(full)-1],
+		"extra payload":     append(append([]byte{}, full...), 'x'),
+	}
+	for name, data := range tests {
+		if _, err := store.ParseStoreErr(data); !errors.Is(err, store.ErrChecksum) {
+			t.Errorf("%s: expected ErrChecksum, got %v", name, err)
+		}
+		if s := store.ParseStore(data); len(s) != 0 {
+			t.Errorf("%s: expected ParseStore to return an empty Store, got %d bytes", name, len(s))
+		}
+	}
+}
+
+// TestParseStoreLengthField menguji bahwa blob dengan field panjang yang lebih
+// besar dari payload sebenarnya ditolak, misalnya karena blob terpotong saat disimpan.
+/*
+	1. Kasus Uji: Store valid yang field panjangnya diubah menjadi lebih besar dan lebih kecil dari payload.
+	2. Validasi Output: Memastikan ParseStore, ParseStoreErr, dan ParseStoreChecked menolak blob tersebut.
+*/
+func TestParseStoreLengthField(t *testing.T) {
+	for _, length := range []uint32{5, 1 << 20, math.MaxUint32} {
+		blob := append([]byte{}, store.NewStore([]byte("1.2.3.4"))...)
+		binary.BigEndian.PutUint32(blob[28:32], length)
+
+		if s := store.ParseStore(blob); len(s) != 0 {
+			t.Errorf("length %d: expected ParseStore to reject blob, got %d bytes", length, len(s))
+		}
+		if _, err := store.ParseStoreErr(blob); !errors.Is(err, store.ErrChecksum) {
+			t.Errorf("length %d: expected ErrChecksum, got %v", length, err)
+		}
+		if _, err := store.ParseStoreChecked(blob); !errors.Is(err, store.ErrChecksum) {
+			t.Errorf("length %d: expected ErrChecksum from ParseStoreChecked, got %v", length, err)
+		}
+	}
+}
+
+// TestStoreVersion menguji fungsi Version dan Upgrade pada Store.
+// Fungsi ini memastikan store baru memakai versi terbaru dan store versi lama dapat di-upgrade.
+/*
+	1. Kasus Uji: Store dari NewStore dan store versi lama (byte versi bernilai nol).
+	2. Validasi Output: Memastikan versi terbaca dengan benar dan upgrade tidak mengubah metadata maupun data.
+*/
+func TestStoreVersion(t *testing.T) {
+	s := store.NewStore([]byte("data"), 60)
+	if s.Version() != store.CurrentVersion {
+		t.Errorf("expected version %d, got %d", store.CurrentVersion, s.Version())
+	}
+
+	// Membuat store versi lama dengan mengosongkan byte versi
+	legacy := store.ParseStore(append([]byte{}, s...))
+	legacy[store.VersionIndex] = 0
+	if legacy.Version() != store.LegacyVersion {
+		t.Errorf("expected version %d, got %d", store.LegacyVersion, legacy.Version())
+	}
+
+	upgraded, err := legacy.Upgrade()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if upgraded.Version() != store.CurrentVersion {
+		t.Errorf("expected version %d, got %d", store.CurrentVersion, upgraded.Version())
+	}
+	if upgraded.CreateAt() != s.CreateAt() || upgraded.MaxAge() != s.MaxAge() {
+		t.Error("expected metadata to be preserved after upgrade")
+	}
+	if upgraded.Text() != "data" {
+		t.Errorf("expected data %q, got %q", "data", upgraded.Text())
+	}
+	// Store asli tidak boleh berubah
+	if legacy.Version() != store.LegacyVersion {
+		t.Error("expected legacy store to be left untouched")
+	}
+
+	// Kasus uji dengan versi yang tidak dikenali
+	unknown := store.ParseStore(append([]byte{}, s...))
+	unknown[store.VersionIndex] = 0xff
+	if _, err := unknown.Upgrade(); !errors.Is(err, store.ErrUnsupportedVersion) {
+		t.Errorf("expected ErrUnsupportedVersion, got %v", err)
+	}
+	if parsed := store.ParseStore(unknown); len(parsed) != 0 {
+		t.Error("expected ParseStore to reject unknown version")
+	}
+	if _, err := store.ParseStoreChecked(unknown); !errors.Is(err, store.ErrUnsupportedVersion) {
+		t.Errorf("expected ErrUnsupportedVersion, got %v", err)
+	}
+}
+
+// TestStoreFloat menguji fungsi Float64 dan Float32 pada Store.
+// Fungsi ini memastikan payload IEEE-754 dari lib.Float64ToByte dan lib.Float32ToByte terbaca kembali tanpa perubahan.
+/*
+	1. Kasus Uji: Payload float64, payload float32, dan payload yang terlalu pendek.
+	2. Validasi Output: Memastikan nilai sama persis dan payload pendek menghasilkan kesalahan.
+*/
+func TestStoreFloat(t *testing.T) {
+	f64, err := store.NewStore(lib.Float64ToByte(3.14)).Float64()
+	if err != nil || f64 != 3.14 {
+		t.Errorf("expected 3.14, got %v (%v)", f64, err)
+	}
+	f32, err := store.NewStore(lib.Float32ToByte(-2.5)).Float32()
+	if err != nil || f32 != -2.5 {
+		t.Errorf("expected -2.5, got %v (%v)", f32, err)
+	}
+
+	short := store.NewStore([]byte{1, 2})
+	if _, err := short.Float64(); err == nil {
+		t.Error("expected error for short float64 payload")
+	}
+	if _, err := short.Float32(); err == nil {
+		t.Error("expected error for short float32 payload")
+	}
+}
+
+// TestStoreIntegers menguji accessor integer pada Store.
+// Fungsi ini memastikan payload dibaca sesuai lebar yang tersimpan.
+/*
+	1. Kasus Uji: Payload dari lib.Int16ToByte, lib.Uint16ToByte, lib.Int8ToByte, dan lib.Int64ToByte, termasuk nilai negatif.
+	2. Validasi Output: Memastikan nilai kembali utuh pada lebar aslinya maupun lebar yang lebih besar, serta lebar atau rentang yang tidak sesuai menghasilkan kesalahan.
+*/
+func TestStoreIntegers(t *testing.T) {
+	i16 := store.NewStore(lib.Int16ToByte(-5))
+	if v, err := i16.Int16(); err != nil || v != -5 {
+		t.Errorf("expected -5, got %d (%v)", v, err)
+	}
+	if v, err := i16.Int64(); err != nil || v != -5 {
+		t.Errorf("expected -5 as int64, got %d (%v)", v, err)
+	}
+	u16 := store.NewStore(lib.Uint16ToByte(math.MaxUint16))
+	if v, err := u16.Uint16(); err != nil || v != math.MaxUint16 {
+		t.Errorf("expected %d, got %d (%v)", math.MaxUint16, v, err)
+	}
+	if v, err := u16.Uint64(); err != nil || v != math.MaxUint16 {
+		t.Errorf("expected %d as uint64, got %d (%v)", math.MaxUint16, v, err)
+	}
+	if v, err := store.NewStore(lib.Int8ToByte(math.MinInt8)).Int8(); err != nil || v != math.MinInt8 {
+		t.Errorf("expected %d, got %d (%v)", math.MinInt8, v, err)
+	}
+	if v, err := store.NewStore(lib.Uint32ToByte(7)).Uint32(); err != nil || v != 7 {
+		t.Errorf("expected 7, got %d (%v)", v, err)
+	}
+
+	// Nilai 8 byte yang muat dalam tipe yang lebih sempit tetap dapat dibaca
+	if v, err := store.NewStore(lib.Int64ToByte(-300)).Int16(); err != nil || v != -300 {
+		t.Errorf("expected -300, got %d (%v)", v, err)
+	}
+	if _, err := store.NewStore(lib.Int64ToByte(1 << 40)).Int32(); err == nil {
+		t.Error("expected error for value out of int32 range")
+	}
+	if _, err := u16.Uint8(); err == nil {
+		t.Error("expected error for value out of uint8 range")
+	}
+	if _, err := store.NewStore([]byte("abc")).Int64(); err == nil {
+		t.Error("expected error for 3-byte payload")
+	}
+	if _, err := store.NewStore([]byte("twelve bytes")).Uint64(); err == nil {
+		t.Error("expected error for 12-byte payload")
+	}
+}
+
+// TestStoreBool menguji fungsi Bool pada Store.
+// Fungsi ini memastikan payload dari lib.BoolToByte terbaca kembali dan payload yang bukan bool ditolak.
+/*
+	1. Kasus Uji: Payload true, false, byte bukan nol, payload kosong, dan payload lebih dari 1 byte.
+	2. Validasi Output: Memastikan nilai sesuai dan payload yang tidak valid menghasilkan kesalahan.
+*/
+func TestStoreBool(t *testing.T) {
+	tests := []struct {
+		data   []byte
+		output bool
+	}{
+		{lib.BoolToByte(true), true},
+		{lib.BoolToByte(false), false},
+		{[]byte{7}, true}, // Byte bukan nol dianggap true
+	}
+	for _, test := range tests {
+		result, err := store.NewStore(test.data).Bool()
+		if err != nil || result != test.output {
+			t.Errorf("Bool() of %v = %v (%v); expected %v", test.data, result, err, test.output)
+		}
+	}
+
+	for _, data := range [][]byte{{}, []byte("true")} {
+		if _, err := store.NewStore(data).Bool(); err == nil {
+			t.Errorf("Bool() of %v; expected error", data)
+		}
+	}
+}
+
+// TestStoreCompressed menguji fungsi NewStoreCompressed.
+// Fungsi ini memastikan payload besar dikompresi, payload kecil tidak, dan data terbaca kembali tanpa perubahan.
+/*
+	1. Kasus Uji: Payload JSON besar yang berulang dan payload kecil di bawah CompressThreshold.
+	2. Validasi Output: Memastikan flag kompresi, ukuran store, metadata, dan isi data sesuai.
+*/
+func TestStoreCompressed(t *testing.T) {
+	data := []byte(`{"items":[` + strings.Repeat(`{"name":"cago","value":1},`, 100) + `{}]}`)
+	s := store.NewStoreCompressed(data, 60)
+	if !s.Compressed() {
+		t.Fatal("expected large payload to be compressed")
+	}
+	if s.Length(true) >= uint64(store.DataStartIndex+len(data)) {
+		t.Errorf("expected compressed store to be smaller, got %d bytes", s.Length(true))
+	}
+	if s.Text() != string(data) {
+		t.Error("expected Text to return the decompressed payload")
+	}
+	var dest map[string]any
+	if err := s.JSON(&dest); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	if s.MaxAge() != 60 || s.CreateAt() == 0 {
+		t.Error("expected metadata to be unaffected by compression")
+	}
+
+	small := store.NewStoreCompressed([]byte("tiny"))
+	if small.Compressed() || small.Text() != "tiny" {
+		t.Error("expected small payload to be stored uncompressed")
+	}
+}
+
+// TestStoreVerify menguji fungsi Verify dan ParseStoreChecked.
+// Fungsi ini memastikan payload yang rusak atau terpotong terdeteksi.
+/*
+	1. Kasus Uji: Store utuh, satu byte payload dibalik, payload terpotong, dan store versi lama tanpa checksum.
+	2. Validasi Output: Memastikan hanya store yang rusak menghasilkan ErrChecksum.
+*/
+func TestStoreVerify(t *testing.T) {
+	s := store.NewStore([]byte("payload"))
+	if _, err := store.ParseStoreChecked(s); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+
+	flipped := append(store.Store{}, s...)
+	flipped[store.DataStartIndex+2] ^= 0x01
+	if _, err := store.ParseStoreChecked(flipped); !errors.Is(err, store.ErrChecksum) {
+		t.Errorf("expected ErrChecksum for flipped byte, got %v", err)
+	}
+	if _, err := store.ParseStoreChecked(s[:len(s)-1]); !errors.Is(err, store.ErrChecksum) {
+		t.Errorf("expected ErrChecksum for truncated payload, got %v", err)
+	}
+
+	// Store versi lama tidak memiliki checksum, sehingga hanya panjangnya yang diperiksa
+	legacy := append(store.Store{}, s...)
+	legacy[store.FlagsIndex] = 0
+	legacy[store.DataStartIndex] ^= 0x01
+	if err := legacy.Verify(); err != nil {
+		t.Errorf("unexpected error for store without checksum: %v", err)
+	}
+}
+
+// TestStoreSetData menguji fungsi SetData pada Store.
+// Fungsi ini memastikan payload diganti tanpa mengubah CreateAt dan MaxAge.
+/*
+	1. Kasus Uji: Payload diganti dengan data yang lebih panjang lalu lebih pendek.
+	2. Validasi Output: Memastikan isi, panjang, checksum, CreateAt, MaxAge, dan UpdateAt sesuai.
+*/
+func TestStoreSetData(t *testing.T) {
+	s := store.NewStore([]byte("short"), 60)
+	created := s.CreateAt()
+	time.Sleep(2 * time.Millisecond)
+
+	for _, data := range []string{"a much longer payload", "tiny"} {
+		s = s.SetData([]byte(data))
+		if s.Text() != data || s.Length() != uint64(len(data)) {
+			t.Errorf("expected payload %q, got %q (length %d)", data, s.Text(), s.Length())
+		}
+		if err := s.Verify(); err != nil {
+			t.Errorf("unexpected error: %v", err)
+		}
+		if s.CreateAt() != created || s.MaxAge() != 60 {
+			t.Error("expected CreateAt and MaxAge to be preserved")
+		}
+		if s.UpdateAt() <= created {
+			t.Errorf("expected UpdateAt to advance past %d, got %d", created, s.UpdateAt())
+		}
+	}
+}
+
+// TestStoreAppend menguji fungsi Append pada Store.
+// Fungsi ini memastikan data ditambahkan ke payload tanpa mengubah CreateAt dan MaxAge.
+/*
+	1. Kasus Uji: Beberapa baris log ditambahkan secara berurutan.
+	2. Validasi Output: Memastikan isi, panjang, checksum, CreateAt, MaxAge, dan UpdateAt sesuai.
+*/
+func TestStoreAppend(t *testing.T) {
+	s := store.NewStore([]byte("line 1\n"), 60)
+	created := s.CreateAt()
+	time.Sleep(2 * time.Millisecond)
+
+	s = s.Append([]byte("line 2\n"))
+	s = s.Append([]byte("line 3\n"))
+	if want := "line 1\nline 2\nline 3\n"; s.Text() != want || s.Length() != uint64(len(want)) {
+		t.Errorf("expected %q, got %q (length %d)", want, s.Text(), s.Length())
+	}
+	if err := s.Verify(); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	if s.CreateAt() != created || s.MaxAge() != 60 || s.UpdateAt() <= created {
+		t.Error("expected CreateAt and MaxAge to be preserved and UpdateAt to advance")
+	}
+}
+
+// TestStoreEncrypted menguji fungsi NewStoreEncrypted dan Decrypt.
+// Fungsi ini memastikan payload hanya dapat dibaca dengan key yang benar.
+/*
+	1. Kasus Uji: Dekripsi dengan key yang benar, key yang salah, ciphertext yang diubah, dan store tanpa enkripsi.
+	2. Validasi Output: Memastikan data kembali utuh, metadata tetap terbaca, dan kasus yang salah menghasilkan kesalahan.
+*/
+func TestStoreEncrypted(t *testing.T) {
+	key := []byte("0123456789abcdef0123456789abcdef")
+	s, err := store.NewStoreEncrypted([]byte("secret"), key, 60)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !s.Encrypted() || s.MaxAge() != 60 || s.Length() != uint64(len(s)-store.DataStartIndex) {
+		t.Error("expected encrypted store with readable metadata")
+	}
+	if strings.Contains(string(s), "secret") {
+		t.Error("expected payload to be encrypted")
+	}
+
+	data, err := s.Decrypt(key)
+	if err != nil || string(data) != "secret" {
+		t.Errorf("expected %q, got %q (%v)", "secret", data, err)
+	}
+	if _, err := s.Decrypt([]byte("fedcba9876543210fedcba9876543210")); err == nil {
+		t.Error("expected error for wrong key")
+	}
+	tampered := append(store.Store{}, s...)
+	tampered[len(tampered)-1] ^= 0x01
+	if _, err := tampered.Decrypt(key); err == nil {
+		t.Error("expected error for tampered ciphertext")
+	}
+	if _, err := store.NewStore([]byte("plain")).Decrypt(key); !errors.Is(err, store.ErrNotEncrypted) {
+		t.Errorf("expected ErrNotEncrypted, got %v", err)
+	}
+}
+
+func TestStoreSlice(t *testing.T) {
+	large := make([]int64, 10000)
+	largeFloat := make([]float64, 10000)
+	for i := range large {
+		large[i] = int64(i*i) - 5000
+		largeFloat[i] = float64(i) / 3
+	}
+
+	for _, values := range [][]int64{{}, {math.MinInt64}, {1, -1, math.MaxInt64}, large} {
+		s := store.ParseStore(store.NewStoreInt64Slice(values, 1000).Values())
+		got, err := s.Int64Slice()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if s.Length() != uint64(8*len(values)) || len(got) != len(values) {
+			t.Fatalf("expected %d elements, got %d", len(values), len(got))
+		}
+		for i := range values {
+			if got[i] != values[i] {
+				t.Fatalf("element %d: expected %d, got %d", i, values[i], got[i])
+			}
+		}
+	}
+
+	for _, values := range [][]float64{{}, {math.Inf(-1)}, {0.1, -2.5, math.MaxFloat64}, largeFloat} {
+		got, err := store.NewStoreFloat64Slice(values).Float64Slice()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(got) != len(values) {
+			t.Fatalf("expected %d elements, got %d", len(values), len(got))
+		}
+		for i := range values {
+			if got[i] != values[i] {
+				t.Fatalf("element %d: expected %v, got %v", i, values[i], got[i])
+			}
+		}
+	}
+
+	if _, err := store.NewStore([]byte("abc")).Int64Slice(); err == nil {
+		t.Error("expected error for payload that is not a multiple of 8")
+	}
+}
+
+func TestStoreIsExpired(t *testing.T) {
+	forever := store.NewStore([]byte("x"))
+	if forever.IsExpired() || forever.IsExpiredAt(math.MaxUint64) {
+		t.Error("expected store without max age to never expire")
+	}
+
+	s := store.NewStore([]byte("x"), 100)
+	created := s.CreateAt()
+	if s.IsExpiredAt(created + 99) {
+		t.Error("expected store to be live 1ms before its max age")
+	}
+	if !s.IsExpiredAt(created + 100) {
+		t.Error("expected store to expire exactly at its max age")
+	}
+	if s.IsExpiredAt(created - 1) {
+		t.Error("expected store created in the future to be live")
+	}
+	if s.IsExpired() {
+		t.Error("expected a fresh store to be live")
+	}
+}
+
+// TestStoreSetCreateAt menguji fungsi SetCreateAt pada Store.
+// Fungsi ini memastikan store yang dipulihkan memakai timestamp aslinya.
+/*
+	1. Kasus Uji: Store baru dengan MaxAge yang CreateAt-nya diganti ke waktu lampau.
+	2. Validasi Output: Memastikan CreateAt, byte versi dan flag, checksum, dan perhitungan kedaluwarsa sesuai timestamp baru.
+*/
+func TestStoreSetCreateAt(t *testing.T) {
+	s := store.NewStore([]byte("x"), 1000)
+	restored := s.SetCreateAt(1_700_000_000_000)
+	if restored.CreateAt() != 1_700_000_000_000 {
+		t.Errorf("expected CreateAt 1700000000000, got %d", restored.CreateAt())
+	}
+	if restored.Version() != s.Version() || restored[store.FlagsIndex] != s[store.FlagsIndex] || restored.Verify() != nil {
+		t.Error("expected version, flags and checksum to be kept")
+	}
+	if s.CreateAt() == restored.CreateAt() {
+		t.Error("expected original store to be unchanged")
+	}
+	// Kedaluwarsa dihitung dari CreateAt yang baru
+	if restored.IsExpiredAt(1_700_000_000_999) || !restored.IsExpiredAt(1_700_000_001_000) {
+		t.Error("expected expiry to follow the restored CreateAt")
+	}
+	if !restored.IsExpired() {
+		t.Error("expected store restored from the past to be expired")
+	}
+}
+
+// TestStoreKind menguji tag jenis nilai pada Store.
+// Fungsi ini memastikan tag tidak bercampur dengan timestamp UpdateAt.
+/*
+	1. Kasus Uji: Store baru tanpa tag, diberi tag dengan SetKind, lalu diperbarui dengan SetUpdateAt dan SetData.
+	2. Validasi Output: Memastikan Kind, UpdateAt, dan checksum sesuai, serta integer bertanda dan tak bertanda tidak saling terbaca keliru.
+*/
+func TestStoreKind(t *testing.T) {
+	s := store.NewStore(lib.Int64ToByte(-1))
+	if s.Kind() != store.KindUnknown {
+		t.Errorf("expected KindUnknown, got %v", s.Kind())
+	}
+	tagged := s.SetKind(store.KindInt)
+	if tagged.Kind() != store.KindInt || s.Kind() != store.KindUnknown {
+		t.Error("expected SetKind to tag a copy only")
+	}
+	if tagged.Verify() != nil {
+		t.Error("expected checksum to be unaffected by the tag")
+	}
+
+	// Timestamp UpdateAt tidak menimpa tag, dan sebaliknya
+	updated := tagged.SetUpdateAt(1_700_000_000_000).SetData(lib.Int64ToByte(-2))
+	if updated.Kind() != store.KindInt {
+		t.Errorf("expected tag to survive updates, got %v", updated.Kind())
+	}
+	if at := tagged.SetUpdateAt(1_700_000_000_000).UpdateAt(); at != 1_700_000_000_000 {
+		t.Errorf("expected UpdateAt 1700000000000, got %d", at)
+	}
+
+	// Integer negatif tidak dibaca sebagai tak bertanda, dan sebaliknya
+	if v, err := tagged.Uint64(); err == nil {
+		t.Errorf("expected error reading negative int as uint64, got %d", v)
+	}
+	big := store.NewStore(lib.Uint64ToByte(math.MaxUint64)).SetKind(store.KindUint)
+	if v, err := big.Int8(); err == nil {
+		t.Errorf("expected error reading MaxUint64 as int8, got %d", v)
+	}
+	if v, err := s.Int8(); err != nil || v != -1 {
+		t.Errorf("expected untagged store to read as -1, got %d (%v)", v, err)
+	}
+	if store.KindFloat.String() != "float" || store.StoreKind(200).String() != "kind(200)" {
+		t.Error("unexpected StoreKind names")
+	}
+}
+
+// TestStoreCopyOnWrite menguji bahwa method Set* dan Append tidak mengubah
+// store asal. Jalankan dengan -race untuk memastikan store yang sama aman
+// dibaca sementara goroutine lain membuat versi baru darinya.
+/*
+	1. Kasus Uji: Beberapa goroutine membaca store bersama sementara goroutine lain memanggil SetUpdateAt, SetMaxAge, SetData, dan Append.
+	2. Validasi Output: Memastikan store asal tetap utuh dan setiap hasil berisi perubahan yang diminta.
+*/
+func TestStoreCopyOnWrite(t *testing.T) {
+	shared := store.NewStore(make([]byte, 8), 60000)
+	created, updated := shared.CreateAt(), shared.UpdateAt()
+
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 1000; j++ {
+				if shared.UpdateAt() != updated || shared.MaxAge() != 60000 || shared.Verify() != nil {
+					t.Error("expected shared store to stay unchanged")
+					return
+				}
+			}
+		}()
+		go func(i int) {
+			defer wg.Done()
+			for j := 0; j < 1000; j++ {
+				date := uint64(i*1000 + j)
+				if s := shared.SetUpdateAt(date); s.UpdateAt() != date {
+					t.Errorf("expected UpdateAt %d, got %d", date, s.UpdateAt())
+				}
+				shared.SetMaxAge(date)
+				shared.SetData([]byte("new"))
+				shared.Append([]byte("more"))
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	if shared.CreateAt() != created || shared.Length() != 8 || shared.Verify() != nil {
+		t.Error("expected shared store to keep its original header and payload")
+	}
+}
+
+// TestReadStore menguji fungsi ReadStore dan WriteTo pada Store.
+// Fungsi ini memastikan payload yang dibaca dari io.Reader sama dengan hasil NewStore.
+/*
+	1. Kasus Uji: Payload kosong dan payload yang lebih besar dari buffer awal.
+	2. Validasi Output: Memastikan isi, panjang, MaxAge, dan checksum sesuai, lalu WriteTo menulis payload yang sama.
+*/
+func TestReadStore(t *testing.T) {
+	for _, data := range []string{"", strings.Repeat("payload", 1000)} {
+		s, err := store.ReadStore(strings.NewReader(data), 60)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if s.Text() != data || s.Length() != uint64(len(data)) || s.MaxAge() != 60 {
+			t.Errorf("expected payload of %d bytes, got %d", len(data), s.Length())
+		}
+		if err := s.Verify(); err != nil {
+			t.Errorf("unexpected error: %v", err)
+		}
+
+		var out strings.Builder
+		if n, err := s.WriteTo(&out); err != nil || n != int64(len(data)) || out.String() != data {
+			t.Errorf("expected WriteTo to write %d bytes, got %d (%v)", len(data), n, err)
+		}
+	}
+}
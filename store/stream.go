@@ -0,0 +1,74 @@
+// Copyright (c) 2024, Jasakode Authors.
+// All rights reserved.
+// Use of this source code is governed by a BSD 3-Clause
+// license that can be found in the LICENSE file.
+
+package store
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"math"
+	"time"
+)
+
+// ReadStore membuat penyimpanan baru dengan payload yang dibaca dari r sampai EOF.
+// Payload dibaca langsung ke dalam slice yang sudah menyisakan ruang untuk header,
+// sehingga tidak ada salinan tambahan seperti ketika data dibaca lalu diberikan ke NewStore.
+//
+// Parameter:
+// - r: Sumber payload.
+// - maxAge: Usia maksimum yang diperbolehkan untuk data dalam milidetik (opsional).
+//
+// Mengembalikan:
+// - Store: Struktur penyimpanan yang berisi metadata dan payload dari r.
+// - error: Kesalahan jika pembacaan r gagal atau payload melebihi 4 GiB.
+func ReadStore(r io.Reader, maxAge ...uint64) (Store, error) {
+	return ReadStoreAt(r, uint64(time.Now().UnixMilli()), maxAge...)
+}
+
+// ReadStoreAt sama seperti ReadStore, tetapi waktu pembuatan diambil dari
+// createAt dan bukan dari jam sistem.
+//
+// Parameter:
+// - r: Sumber payload.
+// - createAt: Waktu pembuatan dalam milidetik unix.
+// - maxAge: Usia maksimum yang diperbolehkan untuk data dalam milidetik (opsional).
+//
+// Mengembalikan:
+// - Store: Struktur penyimpanan yang berisi metadata dan payload dari r.
+// - error: Kesalahan jika pembacaan r gagal atau payload melebihi 4 GiB.
+func ReadStoreAt(r io.Reader, createAt uint64, maxAge ...uint64) (Store, error) {
+	buf := bytes.NewBuffer(make([]byte, DataStartIndex, DataStartIndex+bytes.MinRead))
+	if _, err := buf.ReadFrom(r); err != nil {
+		return Store{}, err
+	}
+	s := Store(buf.Bytes())
+	if n := len(s) - DataStartIndex; uint64(n) > math.MaxUint32 {
+		return Store{}, fmt.Errorf("payload of %d bytes does not fit the length field", n)
+	}
+	copy(s, NewStoreAt(nil, createAt, maxAge...))
+	binary.BigEndian.PutUint32(s[LengthIndex:], uint32(len(s)-DataStartIndex))
+	s.setChecksum()
+	return s, nil
+}
+
+// WriteTo menulis payload store ke w, didekompresi jika perlu.
+// Method ini memenuhi io.WriterTo sehingga store dapat dipakai bersama io.Copy.
+//
+// Parameter:
+// - w: Tujuan penulisan payload.
+//
+// Mengembalikan:
+// - int64: Jumlah byte yang ditulis.
+// - error: Kesalahan jika payload gagal didekompresi atau penulisan ke w gagal.
+func (s Store) WriteTo(w io.Writer) (int64, error) {
+	p, err := s.payload()
+	if err != nil {
+		return 0, err
+	}
+	n, err := w.Write(p)
+	return int64(n), err
+}
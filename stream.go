@@ -0,0 +1,104 @@
+// Copyright (c) 2024, Jasakode Authors.
+// All rights reserved.
+// Use of this source code is governed by a BSD 3-Clause
+// license that can be found in the LICENSE file.
+
+package cago
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/jasakode/cago/store"
+)
+
+// SetStream menyimpan payload yang dibaca dari r sampai EOF dengan key yang
+// diberikan, untuk nilai besar yang tidak ingin disiapkan sebagai []byte oleh
+// pemanggil. Payload dibaca langsung ke dalam store sehingga hanya disalin sekali.
+// Key yang sudah ada akan ditimpa seperti Put. Jika Config.MaxValueSize diatur,
+// pembacaan berhenti begitu batas terlampaui.
+//
+// Parameter:
+//   - key (string): Key unik yang digunakan untuk mengidentifikasi nilai dalam store.
+//   - r (io.Reader): Sumber payload.
+//   - maxAge (uint64): Waktu maksimal dalam milidetik selama nilai akan disimpan.
+//     Nilai 0 berarti tidak pernah kedaluwarsa.
+//
+// Mengembalikan:
+//   - error: Kesalahan jika key tidak valid, r gagal dibaca, payload melebihi
+//     MaxValueSize, atau penyimpanan gagal.
+func (app *App) SetStream(key string, r io.Reader, maxAge uint64) error {
+	if err := app.checkKey(key); err != nil {
+		return err
+	}
+	limit := app.config.MaxValueSize
+	if limit > 0 {
+		// Satu byte tambahan cukup untuk mengetahui batas terlampaui
+		r = io.LimitReader(r, int64(limit)+1)
+	}
+	data, err := store.ReadStoreAt(r, app.now(), app.jitter([]uint64{maxAge})...)
+	if err != nil {
+		return fmt.Errorf("key %q: reading stream: %w", key, err)
+	}
+	if limit > 0 && data.Length() > limit {
+		return fmt.Errorf("key %q: value exceeds limit of %d bytes: %w", key, limit, ErrValueTooLarge)
+	}
+
+	defer app.dispatch()
+	s := app.shardFor(key)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return app.persist(key, data)
+}
+
+// SetStream menjalankan App.SetStream pada instance global yang dibuat oleh New.
+func SetStream(key string, r io.Reader, maxAge uint64) error {
+	return app.SetStream(key, r, maxAge)
+}
+
+// GetStream menulis payload dengan key yang diberikan ke w. Store tidak pernah
+// diubah setelah disimpan, sehingga lock dilepas sebelum penulisan ke w dimulai
+// dan w yang lambat tidak menahan operasi cache lainnya.
+//
+// Parameter:
+//   - key (string): Key unik yang digunakan untuk mencari nilai dalam store.
+//   - w (io.Writer): Tujuan penulisan payload.
+//
+// Mengembalikan:
+//   - bool: True jika key ditemukan dan belum kedaluwarsa.
+//   - error: Kesalahan jika payload gagal didekompresi atau penulisan ke w gagal.
+func (app *App) GetStream(key string, w io.Writer) (bool, error) {
+	value, ok := app.getStore(key)
+	if !ok {
+		return false, nil
+	}
+	if _, err := value.WriteTo(w); err != nil {
+		return true, fmt.Errorf("key %q: %w", key, err)
+	}
+	return true, nil
+}
+
+// GetStream menjalankan App.GetStream pada instance global yang dibuat oleh New.
+func GetStream(key string, w io.Writer) (bool, error) {
+	return app.GetStream(key, w)
+}
+
+// getStore mengambil store yang belum kedaluwarsa untuk jalur baca, dengan
+// pencatatan statistik dan akses PolicyLRU atau PolicyLFU yang sama seperti Get.
+func (app *App) getStore(key string) (store.Store, bool) {
+	s := app.shardFor(key)
+	// PolicyLRU, PolicyLFU, dan SlidingTTL mengubah entri saat dibaca sehingga membutuhkan write lock
+	if app.writeOnRead() {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+	} else {
+		s.mu.RLock()
+		defer s.mu.RUnlock()
+	}
+	value, ok := app.lookup(key)
+	app.recordLookup(ok)
+	if ok {
+		app.touch(key)
+	}
+	return value, ok
+}
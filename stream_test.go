@@ -0,0 +1,60 @@
+// Copyright (c) 2024, Jasakode Authors.
+// All rights reserved.
+// Use of this source code is governed by a BSD 3-Clause
+// license that can be found in the LICENSE file.
+
+package cago_test
+
+import (
+	"bytes"
+	"crypto/rand"
+	"errors"
+	"path/filepath"
+	"testing"
+
+	"github.com/jasakode/cago"
+)
+
+func TestStream(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "stream.db")
+	c, err := cago.NewCache(cago.Config{Path: path})
+	if err != nil {
+		t.Fatal(err)
+	}
+	payload := make([]byte, 5<<20)
+	rand.Read(payload)
+	if err := c.SetStream("blob", bytes.NewReader(payload), 60000); err != nil {
+		t.Fatal(err)
+	}
+
+	var out bytes.Buffer
+	if ok, err := c.GetStream("blob", &out); !ok || err != nil {
+		t.Fatalf("expected blob to be found, got %v (ok=%v)", err, ok)
+	}
+	if !bytes.Equal(out.Bytes(), payload) {
+		t.Errorf("expected %d bytes back, got %d differing bytes", len(payload), out.Len())
+	}
+	if ok, err := c.GetStream("missing", &out); ok || err != nil {
+		t.Errorf("expected missing key, got %v (ok=%v)", err, ok)
+	}
+	c.Close()
+
+	// Payload juga tersimpan ke SQLite
+	c, err = cago.NewCache(cago.Config{Path: path, MaxValueSize: 1 << 20})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer c.Close()
+	out.Reset()
+	if ok, _ := c.GetStream("blob", &out); !ok || !bytes.Equal(out.Bytes(), payload) {
+		t.Errorf("expected blob to survive a reopen, got %d bytes", out.Len())
+	}
+
+	// MaxValueSize juga berlaku untuk stream
+	if err := c.SetStream("big", bytes.NewReader(payload), 0); !errors.Is(err, cago.ErrValueTooLarge) {
+		t.Errorf("expected ErrValueTooLarge, got %v", err)
+	}
+	if c.Exist("big") {
+		t.Error("expected oversized stream not to be stored")
+	}
+}
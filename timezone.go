@@ -0,0 +1,76 @@
+// Copyright (c) 2024, Jasakode Authors.
+// All rights reserved.
+// Use of this source code is governed by a BSD 3-Clause
+// license that can be found in the LICENSE file.
+
+package cago
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/jasakode/cago/store"
+)
+
+// Timezone adalah nama zona waktu IANA yang dipakai untuk menampilkan
+// timestamp, misalnya oleh GetMeta dan Export. Perhitungan masa berlaku
+// tetap memakai unix milidetik sehingga tidak dipengaruhi zona waktu.
+type Timezone string
+
+const (
+	// TimezoneLocal memakai zona waktu lokal dari sistem.
+	TimezoneLocal Timezone = ""
+	// TimezoneUTC memakai Coordinated Universal Time.
+	TimezoneUTC Timezone = "UTC"
+	// TimezoneJakarta memakai Waktu Indonesia Barat (UTC+7).
+	TimezoneJakarta Timezone = "Asia/Jakarta"
+	// TimezoneMakassar memakai Waktu Indonesia Tengah (UTC+8).
+	TimezoneMakassar Timezone = "Asia/Makassar"
+	// TimezoneJayapura memakai Waktu Indonesia Timur (UTC+9).
+	TimezoneJayapura Timezone = "Asia/Jayapura"
+	// TimezoneSingapore memakai zona waktu Singapura (UTC+8).
+	TimezoneSingapore Timezone = "Asia/Singapore"
+	// TimezoneTokyo memakai zona waktu Jepang (UTC+9).
+	TimezoneTokyo Timezone = "Asia/Tokyo"
+)
+
+// Location mengubah Timezone menjadi *time.Location.
+//
+// Mengembalikan:
+//   - *time.Location: Lokasi zona waktu, atau time.Local untuk TimezoneLocal.
+//   - error: Kesalahan jika nama zona waktu tidak dikenali.
+func (tz Timezone) Location() (*time.Location, error) {
+	if tz == TimezoneLocal {
+		return time.Local, nil
+	}
+	loc, err := time.LoadLocation(string(tz))
+	if err != nil {
+		return nil, fmt.Errorf("timezone %q: %w", string(tz), err)
+	}
+	return loc, nil
+}
+
+// location mengembalikan zona waktu yang dipakai untuk menampilkan timestamp.
+func (app *App) location() *time.Location {
+	if app.loc == nil {
+		return time.Local
+	}
+	return app.loc
+}
+
+// times mengubah metadata entri menjadi waktu dalam zona waktu Config.Timezone.
+// UpdateAt yang bernilai nol dilaporkan sama dengan CreateAt, dan expires
+// bernilai nol jika store tidak pernah kedaluwarsa. Fungsi ini harus dipanggil
+// ketika lock shard dari key sedang dipegang, minimal dengan read lock.
+func (app *App) times(key string, value store.Store) (created, updated, expires time.Time) {
+	loc := app.location()
+	created = time.UnixMilli(int64(value.CreateAt())).In(loc)
+	updated = created
+	if at := value.UpdateAt(); at != 0 {
+		updated = time.UnixMilli(int64(at)).In(loc)
+	}
+	if at := app.deadline(key, value); at != 0 {
+		expires = time.UnixMilli(int64(at)).In(loc)
+	}
+	return created, updated, expires
+}
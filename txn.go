@@ -0,0 +1,192 @@
+// Copyright (c) 2024, Jasakode Authors.
+// All rights reserved.
+// Use of this source code is governed by a BSD 3-Clause
+// license that can be found in the LICENSE file.
+
+package cago
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/jasakode/cago/store"
+)
+
+// Tx merepresentasikan transaksi yang sedang berjalan di dalam Txn.
+// Semua perubahan ditampung di dalam Tx dan baru diterapkan ke cache
+// ketika fungsi transaksi selesai tanpa error.
+//
+// Field-field:
+//   - writes: Perubahan yang ditampung, dengan store nil menandakan penghapusan.
+type Tx struct {
+	writes map[string]store.Store // Perubahan yang belum diterapkan, nil berarti key dihapus.
+}
+
+// Txn menjalankan fn sebagai satu transaksi atomik atas beberapa key.
+// Selama fn berjalan, write lock semua shard dipegang sehingga pembaca lain tidak
+// pernah melihat perubahan yang baru diterapkan sebagian. Jika fn mengembalikan
+// error, semua perubahan dibatalkan dan error tersebut dikembalikan. Perubahan
+// yang tidak muat dalam MAX_MEM atau gagal ditulis ke database juga tidak
+// diterapkan sama sekali, sehingga semua key di dalam transaksi tetap seperti semula.
+//
+// fn tidak boleh memanggil fungsi paket seperti Get, Set, atau Remove karena
+// lock sudah dipegang oleh Txn; gunakan method pada Tx sebagai gantinya.
+//
+// Parameter:
+//   - fn (func(tx *Tx) error): Fungsi transaksi yang membaca dan mengubah cache melalui tx.
+//
+// Mengembalikan:
+//   - error: Error dari fn, atau kesalahan saat menyimpan perubahan ke database.
+func Txn(fn func(tx *Tx) error) error {
+	defer app.dispatch()
+	app.lockAll()
+	defer app.unlockAll()
+
+	tx := &Tx{writes: make(map[string]store.Store)}
+	if err := fn(tx); err != nil {
+		return err
+	}
+
+	// Perubahan diurutkan dari yang paling mengurangi memori, sehingga ukuran
+	// cache naik secara monoton menuju ukuran akhirnya selama diterapkan
+	type change struct {
+		key   string
+		data  store.Store
+		delta int64
+	}
+	changes := make([]change, 0, len(tx.writes))
+	// Batas memori berlaku per shard, sehingga ukuran akhir dihitung per shard
+	limit := int64(app.memLimit())
+	totals := make(map[*shard]int64)
+	for key, data := range tx.writes {
+		s := app.shardFor(key)
+		if _, ok := totals[s]; !ok {
+			totals[s] = int64(s.data_size)
+		}
+		var delta int64
+		if old, ok := s.data[key]; ok {
+			delta -= int64(len(key) + len(old))
+		}
+		if data != nil {
+			size := int64(len(key) + len(data))
+			if size > limit {
+				return fmt.Errorf("key %q: %w", key, ErrMemoryLimit)
+			}
+			delta += size
+		}
+		totals[s] += delta
+		changes = append(changes, change{key: key, data: data, delta: delta})
+	}
+	// Batas memori diperiksa di awal agar setEntry tidak gagal di tengah jalan
+	for _, total := range totals {
+		if !app.config.EvictOldestOnMaxMem && total > limit {
+			return ErrMemoryLimit
+		}
+	}
+	sort.Slice(changes, func(i, j int) bool { return changes[i].delta < changes[j].delta })
+
+	// Database ditulis terlebih dahulu dalam satu transaksi, sehingga kegagalan
+	// database tidak meninggalkan cache yang berbeda dari database
+	if app.db != nil {
+		ops := make([]writeOp, 0, len(changes))
+		for _, c := range changes {
+			ops = append(ops, writeOp{key: c.key, data: c.data, remove: c.data == nil})
+		}
+		if err := app.db.apply(ops); err != nil {
+			return err
+		}
+	}
+	for _, c := range changes {
+		if c.data == nil {
+			app.deleteEntry(c.key, EvictRemoved)
+		} else if err := app.setEntry(c.key, c.data); err != nil {
+			return err // Tidak terjadi karena batas memori sudah diperiksa di atas
+		}
+	}
+	return nil
+}
+
+// Get mengambil store dengan key yang diberikan dari sudut pandang transaksi.
+// Perubahan yang sudah ditampung di dalam tx ikut diperhitungkan.
+//
+// Parameter:
+//   - key (string): Key yang dicari.
+//
+// Mengembalikan:
+//   - store.Store: Store yang ditemukan.
+//   - bool: True jika key ada dan belum kedaluwarsa.
+func (tx *Tx) Get(key string) (store.Store, bool) {
+	if data, ok := tx.writes[key]; ok {
+		return data, data != nil
+	}
+	data, ok := app.shardFor(key).data[key]
+	if !ok || app.expiredEntry(key, data, app.now()) {
+		return nil, false
+	}
+	return data, true
+}
+
+// Set menampung nilai baru untuk key yang belum ada, dengan aturan yang sama seperti Set.
+//
+// Parameter:
+//   - key (string): Key unik yang digunakan untuk mengidentifikasi nilai.
+//   - value (store.Compare): Nilai yang akan disimpan.
+//   - maxAge (opsional) (uint64): Waktu maksimal dalam milidetik selama nilai akan disimpan.
+//
+// Mengembalikan:
+//   - error: Kesalahan jika key tidak valid, key sudah ada, atau value tidak dapat dikonversi.
+func (tx *Tx) Set(key string, value store.Compare, maxAge ...uint64) error {
+	if err := app.checkKey(key); err != nil {
+		return err
+	}
+	if _, ok := tx.Get(key); ok {
+		return ErrKeyExists
+	}
+	data, err := app.encode(key, value, maxAge)
+	if err != nil {
+		return err
+	}
+	tx.writes[key] = data
+	return nil
+}
+
+// Put menampung nilai baru atau pengganti untuk key, dengan aturan yang sama seperti Put.
+// Jika maxAge tidak diberikan, maxAge dari nilai lama akan dipertahankan.
+//
+// Parameter:
+//   - key (string): Key unik yang digunakan untuk mengidentifikasi nilai.
+//   - value (store.Compare): Nilai yang akan disimpan.
+//   - maxAge (opsional) (uint64): Waktu maksimal dalam milidetik selama nilai akan disimpan.
+//
+// Mengembalikan:
+//   - error: Kesalahan jika key tidak valid atau value tidak dapat dikonversi.
+func (tx *Tx) Put(key string, value store.Compare, maxAge ...uint64) error {
+	if err := app.checkKey(key); err != nil {
+		return err
+	}
+	data, err := app.encode(key, value, maxAge)
+	if err != nil {
+		return err
+	}
+	// Tanpa maxAge, masa berlaku nilai lama dipertahankan tanpa jitter tambahan
+	if len(maxAge) == 0 {
+		if old, ok := tx.Get(key); ok {
+			data = data.SetMaxAge(old.MaxAge())
+		}
+	}
+	tx.writes[key] = data
+	return nil
+}
+
+// Remove menampung penghapusan key.
+//
+// Parameter:
+//   - key (string): Key yang akan dihapus.
+//
+// Mengembalikan:
+//   - bool: True jika key ada sebelum dihapus.
+func (tx *Tx) Remove(key string) bool {
+	_, ok := tx.Get(key)
+	tx.writes[key] = nil
+	return ok
+}
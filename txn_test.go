@@ -0,0 +1,99 @@
+// Copyright (c) 2024, Jasakode Authors.
+// All rights reserved.
+// Use of this source code is governed by a BSD 3-Clause
+// license that can be found in the LICENSE file.
+
+package cago_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/jasakode/cago"
+)
+
+func TestTxn(t *testing.T) {
+	if err := cago.New(); err != nil {
+		t.Fatal(err)
+	}
+	cago.Set("a", "value")
+
+	// Transaksi yang gagal di tengah jalan tidak boleh mengubah apa pun
+	errAbort := errors.New("abort")
+	err := cago.Txn(func(tx *cago.Tx) error {
+		v, _ := tx.Get("a")
+		if err := tx.Put("b", v.Text()); err != nil {
+			return err
+		}
+		tx.Remove("a")
+		return errAbort
+	})
+	if !errors.Is(err, errAbort) {
+		t.Fatalf("expected errAbort, got %v", err)
+	}
+	if !cago.Exist("a") || cago.Exist("b") {
+		t.Error("expected failed transaction to leave keys unchanged")
+	}
+
+	// Transaksi yang berhasil menerapkan semua perubahan bersamaan
+	err = cago.Txn(func(tx *cago.Tx) error {
+		v, ok := tx.Get("a")
+		if !ok {
+			return errors.New("missing a")
+		}
+		if err := tx.Set("b", v.Text()); err != nil {
+			return err
+		}
+		tx.Remove("a")
+		// Perubahan yang ditampung terlihat di dalam transaksi
+		if _, ok := tx.Get("a"); ok {
+			return errors.New("a still visible")
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if cago.Exist("a") {
+		t.Error("expected a to be removed")
+	}
+	if rs := cago.Get[string]("b"); rs == nil || *rs != "value" {
+		t.Errorf("expected b to be %q, got %v", "value", rs)
+	}
+}
+
+func TestTxnMemoryLimit(t *testing.T) {
+	if err := cago.New(cago.Config{MAX_MEM: 8 * 200}); err != nil {
+		t.Fatal(err)
+	}
+	// Dua nilai 100 byte tidak muat bersama, sehingga tidak ada yang diterapkan
+	err := cago.Txn(func(tx *cago.Tx) error {
+		tx.Put("a", strings.Repeat("x", 100))
+		tx.Put("b", strings.Repeat("y", 100))
+		return nil
+	})
+	if !errors.Is(err, cago.ErrMemoryLimit) {
+		t.Errorf("expected ErrMemoryLimit, got %v", err)
+	}
+	if cago.Exist("a") || cago.Exist("b") {
+		t.Error("expected a failed transaction to leave every key unchanged")
+	}
+
+	// Tx.Set dan Tx.Put memeriksa key dan ukuran nilai seperti Set
+	if err := cago.New(cago.Config{MaxKeyLength: 4, MaxValueSize: 8}); err != nil {
+		t.Fatal(err)
+	}
+	err = cago.Txn(func(tx *cago.Tx) error {
+		return tx.Set("too-long", "x")
+	})
+	if !errors.Is(err, cago.ErrInvalidKey) {
+		t.Errorf("expected ErrInvalidKey, got %v", err)
+	}
+	err = cago.Txn(func(tx *cago.Tx) error {
+		return tx.Put("k", strings.Repeat("x", 9))
+	})
+	if !errors.Is(err, cago.ErrValueTooLarge) {
+		t.Errorf("expected ErrValueTooLarge, got %v", err)
+	}
+}
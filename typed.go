@@ -0,0 +1,90 @@
+// Copyright (c) 2024, Jasakode Authors.
+// All rights reserved.
+// Use of this source code is governed by a BSD 3-Clause
+// license that can be found in the LICENSE file.
+
+package cago
+
+import (
+	"strings"
+
+	"github.com/jasakode/cago/store"
+)
+
+// TypedCache membungkus cache untuk satu tipe nilai T, sehingga kesalahan tipe
+// terdeteksi saat kompilasi dan bukan sebagai Get yang gagal saat program berjalan.
+// Semua key diberi prefix yang ditentukan saat TypedCache dibuat, sehingga
+// beberapa TypedCache dengan tipe berbeda dapat berbagi satu cache tanpa bentrok.
+type TypedCache[T store.Compare] struct {
+	c      *App   // Instance cache, atau nil untuk instance global.
+	prefix string // Prefix yang ditambahkan ke setiap key.
+}
+
+// NewTypedCache membuat TypedCache untuk tipe T.
+//
+// Parameter:
+//   - c (*App): Instance cache yang dibungkus. Jika nil, TypedCache memakai
+//     instance global yang aktif saat setiap fungsi dipanggil, termasuk setelah New dipanggil ulang.
+//   - prefix (string): Prefix untuk setiap key, misalnya "user:". Boleh kosong.
+//
+// Mengembalikan:
+//   - *TypedCache[T]: TypedCache yang baru.
+func NewTypedCache[T store.Compare](c *App, prefix string) *TypedCache[T] {
+	return &TypedCache[T]{c: c, prefix: prefix}
+}
+
+// app mengembalikan instance cache yang dibungkus.
+func (t *TypedCache[T]) app() *App {
+	if t.c != nil {
+		return t.c
+	}
+	return app
+}
+
+// Set menjalankan App.Set dengan key yang diberi prefix.
+func (t *TypedCache[T]) Set(key string, value T, maxAge ...uint64) error {
+	return t.app().Set(t.prefix+key, value, maxAge...)
+}
+
+// Put menjalankan App.Put dengan key yang diberi prefix.
+func (t *TypedCache[T]) Put(key string, value T, maxAge ...uint64) error {
+	return t.app().Put(t.prefix+key, value, maxAge...)
+}
+
+// Get mengambil nilai dengan key yang diberi prefix.
+//
+// Parameter:
+//   - key (string): Key tanpa prefix.
+//
+// Mengembalikan:
+//   - T: Nilai yang ditemukan, atau zero value.
+//   - bool: False jika key tidak ditemukan, sudah kedaluwarsa, atau isinya tidak dapat dikonversi ke T.
+func (t *TypedCache[T]) Get(key string) (T, bool) {
+	return getValue[T](t.app(), t.prefix+key)
+}
+
+// Remove menjalankan App.Remove dengan key yang diberi prefix.
+func (t *TypedCache[T]) Remove(key string) bool {
+	return t.app().Remove(t.prefix + key)
+}
+
+// Range memanggil fn untuk setiap entri dengan prefix TypedCache yang dapat
+// dikonversi ke T. Key diberikan ke fn tanpa prefix. Aturan lock sama seperti
+// App.Range, sehingga fn boleh memanggil fungsi cago lainnya.
+//
+// Parameter:
+//   - fn (func(key string, value T) bool): Fungsi yang dipanggil untuk setiap
+//     entri. Kembalikan false untuk menghentikan iterasi.
+func (t *TypedCache[T]) Range(fn func(key string, value T) bool) {
+	t.app().Range(func(key string, value store.Store) bool {
+		name, ok := strings.CutPrefix(key, t.prefix)
+		if !ok {
+			return true
+		}
+		result, err := decode[T](value)
+		if err != nil {
+			return true
+		}
+		return fn(name, *result)
+	})
+}
@@ -0,0 +1,69 @@
+// Copyright (c) 2024, Jasakode Authors.
+// All rights reserved.
+// Use of this source code is governed by a BSD 3-Clause
+// license that can be found in the LICENSE file.
+
+package cago_test
+
+import (
+	"testing"
+
+	"github.com/jasakode/cago"
+)
+
+func TestTypedCache(t *testing.T) {
+	c, err := cago.NewCache()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer c.Close()
+	users := cago.NewTypedCache[Person](c, "user:")
+	visits := cago.NewTypedCache[int](c, "visits:")
+
+	// Key logis yang sama tidak bentrok karena prefix berbeda
+	if err := users.Set("1", Person{Name: "Alice", Age: 30}); err != nil {
+		t.Fatal(err)
+	}
+	if err := visits.Set("1", 7); err != nil {
+		t.Fatal(err)
+	}
+	if p, ok := users.Get("1"); !ok || p.Name != "Alice" {
+		t.Errorf("expected Alice, got %v (ok=%v)", p, ok)
+	}
+	if n, ok := visits.Get("1"); !ok || n != 7 {
+		t.Errorf("expected 7, got %d (ok=%v)", n, ok)
+	}
+	if !c.Exist("user:1") || !c.Exist("visits:1") {
+		t.Error("expected keys to be stored with their prefix")
+	}
+
+	users.Put("2", Person{Name: "Bob"})
+	c.Put("other", "not a user")
+	seen := map[string]string{}
+	users.Range(func(key string, p Person) bool {
+		seen[key] = p.Name
+		return true
+	})
+	if len(seen) != 2 || seen["1"] != "Alice" || seen["2"] != "Bob" {
+		t.Errorf("expected only users without prefix, got %v", seen)
+	}
+
+	if !users.Remove("1") || users.Remove("1") {
+		t.Error("expected Remove to succeed once")
+	}
+	if _, ok := visits.Get("1"); !ok {
+		t.Error("expected visits:1 to be kept")
+	}
+}
+
+func TestTypedCacheGlobal(t *testing.T) {
+	names := cago.NewTypedCache[string](nil, "")
+	if err := cago.New(); err != nil {
+		t.Fatal(err)
+	}
+	// TypedCache tanpa instance mengikuti instance global terbaru
+	names.Set("greeting", "hello")
+	if rs := cago.Get[string]("greeting"); rs == nil || *rs != "hello" {
+		t.Errorf("expected global instance to be used, got %v", rs)
+	}
+}
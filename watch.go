@@ -0,0 +1,377 @@
+// Copyright (c) 2024, Jasakode Authors.
+// All rights reserved.
+// Use of this source code is governed by a BSD 3-Clause
+// license that can be found in the LICENSE file.
+
+package cago
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/jasakode/cago/store"
+)
+
+// Op menjelaskan jenis perubahan yang dikirim melalui Event.
+type Op int
+
+const (
+	// OpSet berarti key baru disimpan.
+	OpSet Op = iota
+	// OpPut berarti nilai key yang masih berlaku ditimpa.
+	OpPut
+	// OpRemove berarti key dihapus, baik secara manual maupun karena batas kapasitas.
+	OpRemove
+	// OpExpire berarti key dihapus karena MaxAge-nya telah lewat.
+	OpExpire
+	// OpClear berarti seluruh cache dikosongkan. Event ini hanya dikirim ke
+	// Subscribe dengan Key kosong; watcher key menerima OpRemove.
+	OpClear
+)
+
+// Event adalah satu perubahan pada key yang dikirim ke watcher.
+//
+// Field-field:
+//   - Op: Jenis perubahan.
+//   - Key: Key yang berubah.
+//   - Value: Store baru untuk OpSet dan OpPut, atau store terakhir untuk OpRemove dan OpExpire.
+type Event struct {
+	Op    Op
+	Key   string
+	Value store.Store
+}
+
+// watchBuffer adalah kapasitas channel yang dikembalikan oleh WatchKey.
+const watchBuffer = 16
+
+// ErrTooManySubscribers dikembalikan oleh WatchKey dan Subscribe ketika jumlah
+// watcher dan subscriber sudah mencapai Config.MaxSubscribers.
+var ErrTooManySubscribers = errors.New("too many subscribers")
+
+// watcher adalah satu pendaftaran WatchKey.
+type watcher struct {
+	id      uint64
+	ch      chan Event
+	dropped atomic.Uint64 // Jumlah event lama yang dibuang karena buffer penuh.
+}
+
+// subscriber adalah satu pendaftaran Subscribe.
+type subscriber struct {
+	id      uint64
+	ch      chan Event
+	done    chan struct{} // Ditutup saat berhenti berlangganan agar pengiriman yang memblokir dapat berhenti.
+	once    sync.Once
+	dropped atomic.Uint64 // Jumlah event yang dibuang karena channel penuh.
+}
+
+// SubscriberStats adalah penghitung satu watcher atau subscriber yang
+// dikembalikan oleh Subscriptions.
+//
+// Field-field:
+//   - ID: Nomor urut pendaftaran, unik di dalam satu instance cache.
+//   - Key: Key yang diawasi oleh WatchKey, atau kosong untuk Subscribe.
+//   - Events: Channel yang dikembalikan saat mendaftar, sehingga pemanggil dapat
+//     mengenali pendaftarannya sendiri dengan membandingkan channel.
+//   - Dropped: Jumlah event yang dibuang karena pembaca terlalu lambat.
+type SubscriberStats struct {
+	ID      uint64       `json:"id"`
+	Key     string       `json:"key,omitempty"`
+	Events  <-chan Event `json:"-"`
+	Dropped uint64       `json:"dropped"`
+}
+
+// register memberi nomor urut untuk pendaftaran baru, atau mengembalikan
+// ErrTooManySubscribers jika Config.MaxSubscribers sudah tercapai.
+// Fungsi ini harus dipanggil ketika app.wmu sedang dipegang.
+func (app *App) register() (uint64, error) {
+	if limit := app.config.MaxSubscribers; limit > 0 && app.nwatch+len(app.subs) >= limit {
+		return 0, fmt.Errorf("%w: limit is %d", ErrTooManySubscribers, limit)
+	}
+	app.nextSub++
+	return app.nextSub, nil
+}
+
+// Subscriptions mengembalikan penghitung setiap watcher dan subscriber yang
+// sedang terdaftar, sehingga konsumen yang lambat dapat dikenali dari Dropped.
+//
+// Mengembalikan:
+//   - []SubscriberStats: Penghitung setiap pendaftaran, diurutkan berdasarkan ID.
+func (app *App) Subscriptions() []SubscriberStats {
+	app.wmu.Lock()
+	defer app.wmu.Unlock()
+	stats := make([]SubscriberStats, 0, app.nwatch+len(app.subs))
+	for key, list := range app.watchers {
+		for _, w := range list {
+			stats = append(stats, SubscriberStats{ID: w.id, Key: key, Events: w.ch, Dropped: w.dropped.Load()})
+		}
+	}
+	for _, s := range app.subs {
+		stats = append(stats, SubscriberStats{ID: s.id, Events: s.ch, Dropped: s.dropped.Load()})
+	}
+	sort.Slice(stats, func(i, j int) bool { return stats[i].ID < stats[j].ID })
+	return stats
+}
+
+// Subscriptions menjalankan App.Subscriptions pada instance global yang dibuat oleh New.
+func Subscriptions() []SubscriberStats {
+	return app.Subscriptions()
+}
+
+// resetSubscriberStats mengembalikan penghitung semua watcher dan subscriber ke nol.
+func (app *App) resetSubscriberStats() {
+	app.wmu.Lock()
+	defer app.wmu.Unlock()
+	for _, list := range app.watchers {
+		for _, w := range list {
+			w.dropped.Store(0)
+		}
+	}
+	for _, s := range app.subs {
+		s.dropped.Store(0)
+	}
+}
+
+// WatchKey mendaftarkan watcher untuk perubahan pada key.
+// Event dikirim tanpa memblokir goroutine yang mengubah cache: jika buffer
+// channel penuh karena subscriber lambat, event paling lama dibuang sehingga
+// subscriber selalu menerima perubahan terbaru. Channel ditutup ketika fungsi
+// unsubscribe dipanggil atau ketika Close dipanggil. Event yang dibuang
+// dihitung per watcher di Subscriptions.
+//
+// Parameter:
+//   - key (string): Key yang akan diawasi.
+//
+// Mengembalikan:
+//   - <-chan Event: Channel yang menerima setiap perubahan pada key.
+//   - func(): Fungsi untuk berhenti mengawasi key dan menutup channel. Aman dipanggil lebih dari sekali.
+//   - error: ErrTooManySubscribers jika Config.MaxSubscribers sudah tercapai.
+func (app *App) WatchKey(key string) (<-chan Event, func(), error) {
+	app.wmu.Lock()
+	id, err := app.register()
+	if err != nil {
+		app.wmu.Unlock()
+		return nil, func() {}, err
+	}
+	w := &watcher{id: id, ch: make(chan Event, watchBuffer)}
+	if app.watchers == nil {
+		app.watchers = make(map[string][]*watcher)
+	}
+	app.watchers[key] = append(app.watchers[key], w)
+	app.nwatch++
+	app.wmu.Unlock()
+
+	return w.ch, func() {
+		app.wmu.Lock()
+		defer app.wmu.Unlock()
+		list := app.watchers[key]
+		for i, other := range list {
+			if other == w {
+				app.watchers[key] = append(list[:i:i], list[i+1:]...)
+				if len(app.watchers[key]) == 0 {
+					delete(app.watchers, key)
+				}
+				app.nwatch--
+				close(w.ch)
+				return
+			}
+		}
+	}, nil
+}
+
+// WatchKey menjalankan App.WatchKey pada instance global yang dibuat oleh New.
+func WatchKey(key string) (<-chan Event, func(), error) {
+	return app.WatchKey(key)
+}
+
+// Subscribe mendaftarkan subscriber yang menerima event untuk setiap perubahan
+// pada semua key, termasuk OpClear. Setiap subscriber memiliki channel sendiri.
+// Jika channel penuh, event dibuang dan dihitung di CacheStats.DroppedEvents
+// serta per subscriber di Subscriptions,
+// kecuali Config.BlockingSubscribers aktif sehingga perubahan cache menunggu
+// subscriber membaca. Dalam mode tersebut, goroutine pembaca tidak boleh
+// memanggil fungsi cago karena lock cache masih dipegang selama pengiriman.
+// Channel ditutup ketika fungsi unsubscribe atau Close dipanggil; Close tidak
+// menunggu subscriber yang berhenti membaca.
+//
+// Parameter:
+//   - buf (int): Kapasitas buffer channel.
+//
+// Mengembalikan:
+//   - <-chan Event: Channel yang menerima setiap perubahan.
+//   - func(): Fungsi untuk berhenti berlangganan dan menutup channel. Aman dipanggil lebih dari sekali.
+//   - error: ErrTooManySubscribers jika Config.MaxSubscribers sudah tercapai.
+func (app *App) Subscribe(buf int) (<-chan Event, func(), error) {
+	if buf < 0 {
+		buf = 0
+	}
+	app.wmu.Lock()
+	id, err := app.register()
+	if err != nil {
+		app.wmu.Unlock()
+		return nil, func() {}, err
+	}
+	s := &subscriber{id: id, ch: make(chan Event, buf), done: make(chan struct{})}
+	app.subs = append(app.subs, s)
+	app.wmu.Unlock()
+
+	return s.ch, func() {
+		// done ditutup sebelum mengambil wmu agar pengiriman yang sedang
+		// memblokir ke subscriber ini dapat berhenti dan melepas wmu
+		s.once.Do(func() { close(s.done) })
+		app.wmu.Lock()
+		defer app.wmu.Unlock()
+		for i, other := range app.subs {
+			if other == s {
+				app.subs = append(app.subs[:i:i], app.subs[i+1:]...)
+				close(s.ch)
+				return
+			}
+		}
+	}, nil
+}
+
+// Subscribe menjalankan App.Subscribe pada instance global yang dibuat oleh New.
+func Subscribe(buf int) (<-chan Event, func(), error) {
+	return app.Subscribe(buf)
+}
+
+// notify mengirim perubahan pada key ke watcher key tersebut dan ke semua subscriber.
+// Fungsi ini harus dipanggil ketika write lock shard dari key sedang dipegang.
+func (app *App) notify(op Op, key string, value store.Store) {
+	app.notifyWatch(op, key, value)
+	if !app.clearing {
+		app.publish(Event{Op: op, Key: key, Value: value})
+	}
+}
+
+// notifyWatch mengirim event ke semua watcher key tanpa memblokir.
+// Jika buffer watcher penuh, event paling lama dibuang untuk memberi tempat.
+func (app *App) notifyWatch(op Op, key string, value store.Store) {
+	app.wmu.Lock()
+	defer app.wmu.Unlock()
+	for _, w := range app.watchers[key] {
+		e := Event{Op: op, Key: key, Value: value}
+		select {
+		case w.ch <- e:
+		default:
+			// Buffer penuh, buang event paling lama. Pengiriman hanya terjadi
+			// ketika wmu dipegang, sehingga setelahnya pasti ada tempat.
+			select {
+			case <-w.ch:
+				w.dropped.Add(1)
+			default:
+			}
+			w.ch <- e
+		}
+	}
+}
+
+// publish mengirim event ke semua subscriber sesuai Config.BlockingSubscribers.
+func (app *App) publish(e Event) {
+	app.wmu.Lock()
+	defer app.wmu.Unlock()
+	for _, s := range app.subs {
+		if app.config.BlockingSubscribers {
+			// Pengiriman didahulukan, sehingga event tetap terkirim ke subscriber
+			// yang siap membaca meskipun Close sudah dimulai
+			select {
+			case s.ch <- e:
+				continue
+			default:
+			}
+			select {
+			case s.ch <- e:
+			case <-s.done:
+			case <-app.closing:
+			}
+			continue
+		}
+		select {
+		case s.ch <- e:
+		default:
+			app.stats.dropped.Add(1)
+			s.dropped.Add(1)
+		}
+	}
+}
+
+// closeWatchers menutup channel semua watcher dan subscriber. Dipanggil oleh Close.
+func (app *App) closeWatchers() {
+	app.wmu.Lock()
+	defer app.wmu.Unlock()
+	for _, list := range app.watchers {
+		for _, w := range list {
+			close(w.ch)
+		}
+	}
+	app.watchers = nil
+	app.nwatch = 0
+	for _, s := range app.subs {
+		s.once.Do(func() { close(s.done) })
+		close(s.ch)
+	}
+	app.subs = nil
+}
+
+// WaitFor menunggu sampai key berisi nilai bertipe K, misalnya pada alur
+// produsen dan konsumen. Jika key sudah ada, nilainya langsung dikembalikan.
+// Jika belum, WaitFor menunggu OpSet atau OpPut melalui WatchKey tanpa polling.
+// Watcher didaftarkan sebelum key diperiksa sehingga perubahan di antara
+// keduanya tidak terlewat. Nilai yang tidak dapat dikonversi ke K diabaikan.
+// Config.Loader tidak dipanggil.
+//
+// Parameter:
+//   - ctx (context.Context): Context untuk membatalkan penantian.
+//   - key (string): Key yang ditunggu.
+//   - timeout (uint64): Batas waktu menunggu dalam milidetik. Nilai 0 berarti
+//     hanya dibatasi oleh ctx.
+//
+// Mengembalikan:
+//   - K: Nilai yang ditemukan, atau zero value.
+//   - bool: False jika ctx dibatalkan, timeout tercapai, cache ditutup, atau
+//     Config.MaxSubscribers tercapai sebelum key ada.
+func WaitFor[K store.Compare](ctx context.Context, key string, timeout uint64) (K, bool) {
+	var zero K
+	c := app
+	events, cancel, err := c.WatchKey(key)
+	defer cancel()
+
+	if value, ok := c.getStore(key); ok {
+		if result, err := decode[K](value); err == nil {
+			return *result, true
+		}
+	}
+
+	if err != nil {
+		// Tanpa watcher, WaitFor tidak dapat menunggu perubahan berikutnya
+		c.logf("cago: wait for %q: %v", key, err)
+		return zero, false
+	}
+
+	if timeout > 0 {
+		var stop context.CancelFunc
+		ctx, stop = context.WithTimeout(ctx, time.Duration(timeout)*time.Millisecond)
+		defer stop()
+	}
+	for {
+		select {
+		case <-ctx.Done():
+			return zero, false
+		case e, ok := <-events:
+			if !ok {
+				return zero, false
+			}
+			if e.Op != OpSet && e.Op != OpPut {
+				continue
+			}
+			if result, err := decode[K](e.Value); err == nil {
+				return *result, true
+			}
+		}
+	}
+}
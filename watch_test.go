@@ -0,0 +1,338 @@
+// Copyright (c) 2024, Jasakode Authors.
+// All rights reserved.
+// Use of this source code is governed by a BSD 3-Clause
+// license that can be found in the LICENSE file.
+
+package cago_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/jasakode/cago"
+)
+
+func TestWatchKey(t *testing.T) {
+	c, err := cago.NewCache(cago.Config{TimeoutCheck: 10})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer c.Close()
+
+	events, unsubscribe, err := c.WatchKey("user")
+	if err != nil {
+		t.Fatal(err)
+	}
+	expect := func(op cago.Op, value string) {
+		t.Helper()
+		select {
+		case e := <-events:
+			if e.Key != "user" || e.Op != op || e.Value.Text() != value {
+				t.Errorf("expected %v %s, got %v %s %s", op, value, e.Op, e.Key, e.Value.Text())
+			}
+		case <-time.After(time.Second):
+			t.Fatalf("timed out waiting for %v", op)
+		}
+	}
+
+	if err := c.Set("other", "ignored"); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.Set("user", "alice"); err != nil {
+		t.Fatal(err)
+	}
+	expect(cago.OpSet, "alice")
+	if err := c.Put("user", "bob", 1); err != nil {
+		t.Fatal(err)
+	}
+	expect(cago.OpPut, "bob")
+	expect(cago.OpExpire, "bob")
+	if err := c.Set("user", "carol"); err != nil {
+		t.Fatal(err)
+	}
+	expect(cago.OpSet, "carol")
+	c.Remove("user")
+	expect(cago.OpRemove, "carol")
+
+	unsubscribe()
+	unsubscribe()
+	if _, ok := <-events; ok {
+		t.Error("expected channel to be closed after unsubscribe")
+	}
+}
+
+func TestWatchKeySlowSubscriber(t *testing.T) {
+	c, err := cago.NewCache()
+	if err != nil {
+		t.Fatal(err)
+	}
+	events, _, err := c.WatchKey("counter")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Subscriber tidak membaca sama sekali, Put tidak boleh terblokir
+	for i := 0; i < 100; i++ {
+		if err := c.Put("counter", fmt.Sprint(i)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	var last cago.Event
+	for i := 0; i < cap(events); i++ {
+		last = <-events
+	}
+	if last.Value.Text() != "99" {
+		t.Errorf("expected the latest event to be kept, got %s", last.Value.Text())
+	}
+
+	c.Close()
+	if _, ok := <-events; ok {
+		t.Error("expected channel to be closed after Close")
+	}
+}
+
+func TestSubscribe(t *testing.T) {
+	c, err := cago.NewCache()
+	if err != nil {
+		t.Fatal(err)
+	}
+	events, _, err := c.Subscribe(16)
+	if err != nil {
+		t.Fatal(err)
+	}
+	slow, unsubscribeSlow, err := c.Subscribe(1)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := c.Set("a", "1"); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.Put("a", "2"); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.Set("b", "3"); err != nil {
+		t.Fatal(err)
+	}
+	c.Remove("a")
+	if err := c.Clear(); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []struct {
+		op  cago.Op
+		key string
+	}{{cago.OpSet, "a"}, {cago.OpPut, "a"}, {cago.OpSet, "b"}, {cago.OpRemove, "a"}, {cago.OpClear, ""}}
+	for _, want := range expected {
+		e := <-events
+		if e.Op != want.op || e.Key != want.key {
+			t.Errorf("expected %v %q, got %v %q", want.op, want.key, e.Op, e.Key)
+		}
+	}
+	if n := c.Stats().DroppedEvents; n != uint64(len(expected)-1) {
+		t.Errorf("expected %d dropped events for the slow subscriber, got %d", len(expected)-1, n)
+	}
+	if e := <-slow; e.Op != cago.OpSet || e.Key != "a" {
+		t.Errorf("expected the slow subscriber to keep the first event, got %v %q", e.Op, e.Key)
+	}
+	unsubscribeSlow()
+	if _, ok := <-slow; ok {
+		t.Error("expected channel to be closed after unsubscribe")
+	}
+
+	c.Close()
+	if _, ok := <-events; ok {
+		t.Error("expected channel to be closed after Close")
+	}
+}
+
+func TestMaxSubscribers(t *testing.T) {
+	c, err := cago.NewCache(cago.Config{MaxSubscribers: 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer c.Close()
+
+	_, unwatch, err := c.WatchKey("a")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := c.Subscribe(1); err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := c.Subscribe(1); !errors.Is(err, cago.ErrTooManySubscribers) {
+		t.Errorf("expected ErrTooManySubscribers from Subscribe, got %v", err)
+	}
+	if _, _, err := c.WatchKey("b"); !errors.Is(err, cago.ErrTooManySubscribers) {
+		t.Errorf("expected ErrTooManySubscribers from WatchKey, got %v", err)
+	}
+	if n := c.Stats().Subscribers; n != 2 {
+		t.Errorf("expected 2 subscribers, got %d", n)
+	}
+
+	unwatch()
+	if _, _, err := c.WatchKey("b"); err != nil {
+		t.Errorf("expected a free slot after unsubscribe, got %v", err)
+	}
+}
+
+func TestSubscriberDropCounts(t *testing.T) {
+	c, err := cago.NewCache()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer c.Close()
+	fast, _, err := c.Subscribe(64)
+	if err != nil {
+		t.Fatal(err)
+	}
+	slow, _, err := c.Subscribe(1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	watch, _, err := c.WatchKey("k")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Tidak ada yang membaca, Put tidak boleh terblokir
+	done := make(chan struct{})
+	go func() {
+		for i := 0; i < 20; i++ {
+			c.Put("k", i)
+		}
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(2 * time.Second):
+		t.Fatal("publisher blocked behind a slow subscriber")
+	}
+
+	dropped := map[<-chan cago.Event]uint64{}
+	for _, s := range c.Subscriptions() {
+		dropped[s.Events] = s.Dropped
+	}
+	if n := dropped[fast]; n != 0 {
+		t.Errorf("expected no drops for the fast subscriber, got %d", n)
+	}
+	if n := dropped[slow]; n != 19 {
+		t.Errorf("expected 19 drops for the slow subscriber, got %d", n)
+	}
+	if n := dropped[watch]; n != 4 {
+		t.Errorf("expected 4 drops for the watcher, got %d", n)
+	}
+
+	c.ResetStats()
+	for _, s := range c.Subscriptions() {
+		if s.Dropped != 0 {
+			t.Errorf("expected drop count of subscriber %d to be reset, got %d", s.ID, s.Dropped)
+		}
+	}
+}
+
+func TestSubscribeBlocking(t *testing.T) {
+	c, err := cago.NewCache(cago.Config{BlockingSubscribers: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer c.Close()
+	events, unsubscribe, err := c.Subscribe(0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	received := make(chan int)
+	go func() {
+		n := 0
+		for range events {
+			n++
+		}
+		received <- n
+	}()
+	for i := 0; i < 50; i++ {
+		if err := c.Put(fmt.Sprint(i), i); err != nil {
+			t.Fatal(err)
+		}
+	}
+	unsubscribe()
+	if n := <-received; n != 50 {
+		t.Errorf("expected all 50 events to be delivered, got %d", n)
+	}
+	if n := c.Stats().DroppedEvents; n != 0 {
+		t.Errorf("expected no dropped events, got %d", n)
+	}
+}
+
+func TestCloseStalledSubscriber(t *testing.T) {
+	c, err := cago.NewCache(cago.Config{BlockingSubscribers: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	events, _, err := c.Subscribe(0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Subscriber tidak pernah membaca, sehingga Put memblokir sambil memegang lock
+	put := make(chan struct{})
+	go func() {
+		c.Put("k", "v")
+		close(put)
+	}()
+	time.Sleep(10 * time.Millisecond)
+
+	closed := make(chan error, 1)
+	go func() { closed <- c.Close() }()
+	select {
+	case err := <-closed:
+		if err != nil {
+			t.Errorf("unexpected error from Close: %v", err)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("Close blocked behind a stalled subscriber")
+	}
+	<-put
+	for range events {
+		// Channel ditutup oleh Close
+	}
+}
+
+func TestWaitFor(t *testing.T) {
+	if err := cago.New(); err != nil {
+		t.Fatal(err)
+	}
+
+	// Key yang sudah ada langsung dikembalikan
+	cago.Set("ready", 1)
+	if v, ok := cago.WaitFor[int](context.Background(), "ready", 10); !ok || v != 1 {
+		t.Errorf("expected immediate 1, got %d (ok=%v)", v, ok)
+	}
+
+	// Key yang disimpan kemudian membangunkan penunggu
+	go func() {
+		time.Sleep(10 * time.Millisecond)
+		cago.Remove("job")
+		cago.Set("job", "done")
+	}()
+	start := time.Now()
+	if v, ok := cago.WaitFor[string](context.Background(), "job", 5000); !ok || v != "done" {
+		t.Errorf("expected done, got %q (ok=%v)", v, ok)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("expected wake-up on Set, waited %v", elapsed)
+	}
+
+	// Timeout dan pembatalan context mengembalikan zero value
+	if v, ok := cago.WaitFor[string](context.Background(), "never", 20); ok || v != "" {
+		t.Errorf("expected timeout, got %q (ok=%v)", v, ok)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, ok := cago.WaitFor[string](ctx, "never", 0); ok {
+		t.Error("expected cancelled context to stop waiting")
+	}
+}
@@ -0,0 +1,179 @@
+// Copyright (c) 2024, Jasakode Authors.
+// All rights reserved.
+// Use of this source code is governed by a BSD 3-Clause
+// license that can be found in the LICENSE file.
+
+package cago
+
+import (
+	"database/sql"
+	"errors"
+	"time"
+)
+
+// writeOp adalah satu perubahan yang menunggu untuk ditulis ke database.
+type writeOp struct {
+	key    string     // Key yang diubah.
+	data   []byte     // Store baru untuk key, jika bukan penghapusan.
+	remove bool       // True jika key dihapus.
+	all    bool       // True jika seluruh tabel dikosongkan.
+	flush  chan error // Jika tidak nil, operasi ini hanya meminta antrean dikosongkan.
+}
+
+// writeBehind mengumpulkan perubahan lalu menuliskannya ke database dalam
+// satu transaksi setiap interval tertentu atau ketika jumlahnya mencapai size.
+type writeBehind struct {
+	db       *database     // Database tujuan penulisan.
+	ops      chan writeOp  // Antrean perubahan, diproses berurutan oleh run.
+	interval time.Duration // Jarak waktu maksimal antar penulisan.
+	size     int           // Jumlah perubahan yang memicu penulisan.
+	stopped  chan struct{} // Ditutup ketika run selesai.
+	err      error         // Kesalahan penulisan pertama sejak flush terakhir, hanya diakses oleh run.
+}
+
+// startWriteBehind menjalankan antrean penulisan asinkron untuk db.
+func (db *database) startWriteBehind(interval time.Duration, size int) {
+	wb := &writeBehind{
+		db:       db,
+		ops:      make(chan writeOp, size),
+		interval: interval,
+		size:     size,
+		stopped:  make(chan struct{}),
+	}
+	db.wb = wb
+	go wb.run()
+}
+
+// enqueue menambahkan perubahan ke antrean. Fungsi ini menunggu jika antrean penuh.
+func (wb *writeBehind) enqueue(op writeOp) {
+	wb.ops <- op
+}
+
+// run memproses antrean sampai antrean ditutup oleh stop.
+func (wb *writeBehind) run() {
+	defer close(wb.stopped)
+	ticker := time.NewTicker(wb.interval)
+	defer ticker.Stop()
+
+	batch := make([]writeOp, 0, wb.size)
+	for {
+		select {
+		case op, ok := <-wb.ops:
+			if !ok {
+				wb.write(batch)
+				return
+			}
+			if op.flush != nil {
+				batch = wb.write(batch)
+				op.flush <- wb.err
+				wb.err = nil
+				continue
+			}
+			batch = append(batch, op)
+			if len(batch) >= wb.size {
+				batch = wb.write(batch)
+			}
+		case <-ticker.C:
+			batch = wb.write(batch)
+		}
+	}
+}
+
+// write menuliskan batch ke database dan mengembalikan batch kosong untuk dipakai ulang.
+func (wb *writeBehind) write(batch []writeOp) []writeOp {
+	if len(batch) == 0 {
+		return batch
+	}
+	if err := wb.db.writeBatch(batch); err != nil && wb.err == nil {
+		wb.err = err
+	}
+	return batch[:0]
+}
+
+// flush menunggu sampai semua perubahan yang sudah diantrekan ditulis.
+//
+// Mengembalikan:
+//   - error: Kesalahan penulisan pertama sejak flush terakhir.
+func (wb *writeBehind) flush() error {
+	done := make(chan error, 1)
+	wb.ops <- writeOp{flush: done}
+	return <-done
+}
+
+// stop menulis semua perubahan yang tersisa lalu menghentikan run.
+// Tidak boleh ada enqueue setelah stop dipanggil.
+func (wb *writeBehind) stop() error {
+	err := wb.flush()
+	close(wb.ops)
+	<-wb.stopped
+	return err
+}
+
+// writeBatch menuliskan semua perubahan dalam satu transaksi.
+func (db *database) writeBatch(batch []writeOp) error {
+	return db.WithTx(func(tx *sql.Tx) error {
+		for _, op := range batch {
+			var err error
+			switch {
+			case op.all:
+				err = db.removeAll(tx)
+			case op.remove:
+				err = db.removeByKey(tx, op.key)
+			default:
+				err = db.insertOrUpdate(tx, op.key, op.data)
+			}
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// apply menuliskan beberapa perubahan sekaligus. Tanpa WriteBehind semua
+// perubahan ditulis dalam satu transaksi, sedangkan dengan WriteBehind
+// perubahan diantrekan dan ditulis bersama antrean lainnya.
+func (db *database) apply(batch []writeOp) error {
+	if len(batch) == 0 {
+		return nil
+	}
+	if db.wb != nil {
+		for _, op := range batch {
+			db.wb.enqueue(op)
+		}
+		return nil
+	}
+	return db.writeBatch(batch)
+}
+
+// close mengosongkan antrean penulisan lalu menutup koneksi database.
+func (db *database) close() error {
+	var err error
+	if db.wb != nil {
+		err = db.wb.stop()
+	}
+	return errors.Join(err, db.sqldb.Close())
+}
+
+// Flush menunggu sampai semua perubahan yang diantrekan oleh Config.WriteBehind
+// ditulis ke database. Tanpa WriteBehind, setiap perubahan sudah ditulis secara
+// langsung sehingga Flush tidak melakukan apa pun.
+//
+// Mengembalikan:
+//   - error: Kesalahan penulisan pertama sejak Flush terakhir.
+func (app *App) Flush() error {
+	// Close mengunci semua shard, sehingga read lock satu shard sudah
+	// mencegah Close menghentikan antrean selama Flush berjalan
+	s := app.shards[0]
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if app.db == nil || app.db.wb == nil {
+		return nil
+	}
+	return app.db.wb.flush()
+}
+
+// Flush menjalankan App.Flush pada instance global yang dibuat oleh New.
+func Flush() error {
+	return app.Flush()
+}
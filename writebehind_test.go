@@ -0,0 +1,59 @@
+// Copyright (c) 2024, Jasakode Authors.
+// All rights reserved.
+// Use of this source code is governed by a BSD 3-Clause
+// license that can be found in the LICENSE file.
+
+package cago_test
+
+import (
+	"database/sql"
+	"fmt"
+	"path/filepath"
+	"testing"
+
+	"github.com/jasakode/cago"
+)
+
+// countRows menghitung jumlah baris di tabel cagos pada file database path.
+func countRows(t *testing.T, path string) int {
+	t.Helper()
+	db, err := sql.Open("sqlite3", path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+	var count int
+	if err := db.QueryRow(`SELECT COUNT(*) FROM cagos;`).Scan(&count); err != nil {
+		t.Fatal(err)
+	}
+	return count
+}
+
+func TestWriteBehind(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "writebehind.db")
+	c, err := cago.NewCache(cago.Config{Path: path, WriteBehind: true, FlushInterval: 60000})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 1000; i++ {
+		if err := c.Set(fmt.Sprintf("key-%d", i), i); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := c.Flush(); err != nil {
+		t.Fatal(err)
+	}
+	if n := countRows(t, path); n != 1000 {
+		t.Errorf("expected 1000 rows after Flush, got %d", n)
+	}
+
+	// Close mengosongkan antrean tanpa perlu memanggil Flush
+	c.Remove("key-0")
+	c.Put("extra", "value")
+	if err := c.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if n := countRows(t, path); n != 1000 {
+		t.Errorf("expected 1000 rows after Close, got %d", n)
+	}
+}